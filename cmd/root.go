@@ -11,6 +11,8 @@ import (
 
 	"github.com/mesosphere/dkp-cli-runtime/core/cmd/root"
 	"github.com/mesosphere/dkp-cli-runtime/core/output"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/proxy"
 )
 
 // NewCommand creates root command.
@@ -18,9 +20,17 @@ func NewCommand(in io.Reader, out, errOut io.Writer) (*cobra.Command, output.Out
 	rootCmd, rootOpts := root.NewCommand(out, errOut)
 
 	// Enable structured logging
-	slog.SetDefault(slog.New(slog.NewTextHandler(rootOpts.Output.V(1).InfoWriter(), &slog.HandlerOptions{
-		Level: slog.LevelDebug,
-	})))
+	logger, err := proxy.NewLogger(
+		proxy.WithLoggerOutput(rootOpts.Output.V(1).InfoWriter()),
+		proxy.WithLoggerLevel(slog.LevelDebug),
+	)
+	if err != nil {
+		rootOpts.Output.Warnf("Using default logger: %s", err)
+		logger = slog.New(slog.NewTextHandler(rootOpts.Output.V(1).InfoWriter(), &slog.HandlerOptions{
+			Level: slog.LevelDebug,
+		}))
+	}
+	slog.SetDefault(logger)
 
 	rootCmd.AddCommand(NewServeCommand(rootOpts.Output))
 
@@ -2,9 +2,12 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"runtime"
+	"strings"
+	"time"
 
 	"github.com/gorilla/handlers"
 
@@ -25,6 +28,17 @@ type serveOptions struct {
 	envtestArch           string
 	serviceClusterIPRange string
 	serviceNodePortRange  string
+	logsEncoding          string
+	namespaceMapping      map[string]string
+	plan                  bool
+	logsMaxLineLength     int
+	logsReadTimeout       time.Duration
+	logsMaxConcurrency    int
+	logsMaxGlobMatches    int
+	logsContentType       string
+	decompressedCacheSize int64
+	strict                bool
+	pathRewrites          []string
 }
 
 // NewServeCommand serves the provided bundle.
@@ -33,6 +47,9 @@ func NewServeCommand(out output.Output) *cobra.Command {
 		kubeconfigPath: "./support-bundle-kubeconfig",
 		proxyAddress:   "localhost:8080",
 		envtestArch:    runtime.GOARCH,
+
+		logsMaxGlobMatches: proxy.DefaultLogsMaxGlobMatches,
+		logsContentType:    proxy.DefaultLogsContentType,
 	}
 
 	cmd := &cobra.Command{
@@ -70,17 +87,108 @@ func NewServeCommand(out output.Output) *cobra.Command {
 		"override k8s api server service node port range",
 	)
 
+	cmd.Flags().StringVar(
+		&options.logsEncoding, "logs-encoding", options.logsEncoding,
+		fmt.Sprintf(
+			"encoding of pod logs without BOM, one of %q, %q, %q. Logs with BOM are always transcoded to UTF-8.",
+			proxy.EncodingLatin1, proxy.EncodingUTF16LE, proxy.EncodingUTF16BE,
+		),
+	)
+
+	cmd.Flags().IntVar(
+		&options.logsMaxLineLength, "logs-max-line-length", options.logsMaxLineLength,
+		"truncate pod log lines longer than given number of bytes, 0 disables truncating",
+	)
+
+	cmd.Flags().DurationVar(
+		&options.logsReadTimeout, "logs-read-timeout", options.logsReadTimeout,
+		"maximum time spent by reading pod logs from the bundle, 0 disables the timeout",
+	)
+
+	cmd.Flags().IntVar(
+		&options.logsMaxConcurrency, "logs-max-concurrency", options.logsMaxConcurrency,
+		"maximum number of concurrently served pod logs requests, 0 disables the limit",
+	)
+
+	cmd.Flags().IntVar(
+		&options.logsMaxGlobMatches, "logs-max-glob-matches", options.logsMaxGlobMatches,
+		"maximum number of directories matching a pod considered when looking up its logs, 0 disables the limit",
+	)
+
+	cmd.Flags().StringVar(
+		&options.logsContentType, "logs-content-type", options.logsContentType,
+		"content type of served pod logs",
+	)
+
+	cmd.Flags().Int64Var(
+		&options.decompressedCacheSize, "decompressed-cache-size", options.decompressedCacheSize,
+		"maximum number of bytes of decompressed bundle files kept in memory, 0 disables the cache",
+	)
+
+	cmd.Flags().StringToStringVar(
+		&options.namespaceMapping, "namespace-mapping", options.namespaceMapping,
+		"import resources from bundle namespace to a different namespace, e.g. kube-system=bundle-b-kube-system",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&options.pathRewrites, "path-rewrite", options.pathRewrites,
+		"rewrite request paths matching a regexp before routing, in format <pattern>=<replacement>, "+
+			"e.g. '^/legacy/(.*)$=/api/v1/$1'. The first matching rule is applied.",
+	)
+
+	cmd.Flags().BoolVar(
+		&options.strict, "strict", options.strict,
+		"fail when the bundle contains files that are not handled by any loader",
+	)
+
+	cmd.Flags().BoolVar(
+		&options.plan, "plan", options.plan,
+		"print a JSON report of which bundle files will be imported and exit without starting the server",
+	)
+
 	return cmd
 }
 
 func runServe(bundlePath string, o *serveOptions, out output.Output) error {
+	if err := proxy.ValidateEncoding(o.logsEncoding); err != nil {
+		return err
+	}
+	pathRewrites, err := proxy.ParsePathRewrites(o.pathRewrites)
+	if err != nil {
+		return err
+	}
+
 	supportBundle, err := bundle.New(bundlePath)
 	if err != nil {
 		return fmt.Errorf("failed to get bundle from path %q: %w", bundlePath, err)
 	}
+	if o.decompressedCacheSize > 0 {
+		supportBundle = bundle.FromFsWithLayout(
+			bundle.NewDecompressedCacheFs(supportBundle, o.decompressedCacheSize), supportBundle.Layout())
+	}
+
+	if o.strict {
+		orphans, err := bundle.FindOrphanFiles(supportBundle)
+		if err != nil {
+			return fmt.Errorf("failed to look up files not handled by any loader: %w", err)
+		}
+		if len(orphans) > 0 {
+			return fmt.Errorf("bundle contains %d files not handled by any loader: %s",
+				len(orphans), strings.Join(orphans, ", "))
+		}
+	}
+
+	if o.plan {
+		return printImportPlan(supportBundle, out)
+	}
 
 	ctx := context.Background()
 
+	rr := rewriter.Default()
+	if len(o.namespaceMapping) > 0 {
+		rr = rewriter.Multi(rr, rewriter.RemapNamespace(o.namespaceMapping))
+	}
+
 	out.StartOperation("Starting k8s server")
 	testEnv, err := startK8sServer(ctx, supportBundle, out, o)
 	out.EndOperation(err == nil)
@@ -95,7 +203,7 @@ func runServe(bundlePath string, o *serveOptions, out output.Output) error {
 	}()
 
 	out.StartOperation("Importing bundle resources")
-	err = importer.ImportBundle(ctx, supportBundle, testEnv.Config, out)
+	err = importer.ImportBundle(ctx, supportBundle, testEnv.Config, rr, out)
 	out.EndOperation(err == nil)
 	if err != nil {
 		out.Error(err, "failed to import support bundle resources to API server")
@@ -110,13 +218,41 @@ func runServe(bundlePath string, o *serveOptions, out output.Output) error {
 	out.Infof("Running HTTPs proxy service on: %s", proxyHTTPAddress)
 	out.Infof("KUBECONFIG=%s", kubeconfigPath)
 
-	proxyHandler := proxy.New(testEnv.Config, supportBundle, rewriter.Default())
-	loggedProxyHandler := handlers.LoggingHandler(out.InfoWriter(), proxyHandler)
+	proxyHandler := proxy.New(
+		testEnv.Config, supportBundle, rr,
+		proxy.WithLogsEncoding(o.logsEncoding),
+		proxy.WithLogsNamespaceMapping(o.namespaceMapping),
+		proxy.WithLogsMaxLineLength(o.logsMaxLineLength),
+		proxy.WithLogsReadTimeout(o.logsReadTimeout),
+		proxy.WithLogsMaxConcurrency(o.logsMaxConcurrency),
+		proxy.WithLogsMaxGlobMatches(o.logsMaxGlobMatches),
+		proxy.WithLogsContentType(o.logsContentType),
+	)
+	loggedProxyHandler := handlers.LoggingHandler(out.InfoWriter(), proxy.RewritePaths(proxyHandler, pathRewrites))
 
 	http.Handle("/", loggedProxyHandler)
 	return http.ListenAndServe(o.proxyAddress, nil) //nolint:gosec // not a production server
 }
 
+func printImportPlan(supportBundle bundle.Bundle, out output.Output) error {
+	plan, err := importer.ImportPlan(supportBundle)
+	if err != nil {
+		return fmt.Errorf("failed to create import plan: %w", err)
+	}
+
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	out.Info(string(data))
+	out.Infof(
+		"%d importable, %d skipped, %d problematic files",
+		plan.Count(importer.PlanImportable), plan.Count(importer.PlanSkipped), plan.Count(importer.PlanProblematic),
+	)
+	return nil
+}
+
 func startK8sServer(
 	ctx context.Context,
 	supportBundle bundle.Bundle,
@@ -0,0 +1,118 @@
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/afero"
+)
+
+// Severities of analyzer results.
+const (
+	AnalysisPass = "pass"
+	AnalysisWarn = "warn"
+	AnalysisFail = "fail"
+)
+
+// AnalysisResult is a result of a troubleshoot analyzer.
+type AnalysisResult struct {
+	Name string `json:"name,omitempty"`
+	// Severity is AnalysisPass, AnalysisWarn or AnalysisFail.
+	Severity string `json:"severity"`
+	Title    string `json:"title"`
+	Message  string `json:"message,omitempty"`
+	URI      string `json:"uri,omitempty"`
+}
+
+// bundleAnalysisResult is an item of the `analysis.json` file written by
+// troubleshoot to the bundle.
+type bundleAnalysisResult struct {
+	Name    string `json:"name"`
+	Insight struct {
+		Primary  string `json:"primary"`
+		Detail   string `json:"detail"`
+		Severity string `json:"severity"`
+	} `json:"insight"`
+	Severity string `json:"severity"`
+}
+
+// analyzeOutput is the `--output json` output of `support-bundle analyze`
+// with results grouped by severity.
+type analyzeOutput map[string][]struct {
+	Title   string `json:"title"`
+	Message string `json:"message"`
+	URI     string `json:"uri"`
+}
+
+// LoadAnalysisResults returns analyzer results stored in the bundle at
+// the layout analysis path. Both the `analysis.json` written by troubleshoot,
+// with `debug`, `warn` and `error` severities, and the JSON output of
+// `support-bundle analyze`, grouped by `pass`, `warn` and `fail`, are
+// supported. Empty result is returned when the bundle doesn't contain
+// the analysis.
+func LoadAnalysisResults(b Bundle) ([]AnalysisResult, error) {
+	path := b.Layout().Analysis()
+	data, err := afero.ReadFile(b, path)
+	if isNotCollected(err) {
+		return []AnalysisResult{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := parseAnalysisResults(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse analysis %q: %w", path, err)
+	}
+	return results, nil
+}
+
+func parseAnalysisResults(data []byte) ([]AnalysisResult, error) {
+	var items []bundleAnalysisResult
+	if err := json.Unmarshal(data, &items); err == nil {
+		results := make([]AnalysisResult, 0, len(items))
+		for _, item := range items {
+			severity := item.Insight.Severity
+			if severity == "" {
+				severity = item.Severity
+			}
+			results = append(results, AnalysisResult{
+				Name:     item.Name,
+				Severity: analysisSeverity(severity),
+				Title:    item.Insight.Primary,
+				Message:  item.Insight.Detail,
+			})
+		}
+		return results, nil
+	}
+
+	output := analyzeOutput{}
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, err
+	}
+	results := []AnalysisResult{}
+	for _, severity := range []string{AnalysisFail, AnalysisWarn, AnalysisPass} {
+		for _, item := range output[severity] {
+			results = append(results, AnalysisResult{
+				Severity: severity,
+				Title:    item.Title,
+				Message:  item.Message,
+				URI:      item.URI,
+			})
+		}
+	}
+	return results, nil
+}
+
+// analysisSeverity converts troubleshoot insight severity to the analyzer
+// outcome.
+func analysisSeverity(severity string) string {
+	switch severity {
+	case "error", AnalysisFail:
+		return AnalysisFail
+	case "warn", "warning":
+		return AnalysisWarn
+	default:
+		return AnalysisPass
+	}
+}
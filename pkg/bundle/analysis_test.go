@@ -0,0 +1,55 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadAnalysisResults(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"analysis.json": `[
+  {"name": "kubernetes.version", "severity": "debug", "analyzerSpec": "",
+   "insight": {"name": "kubernetes.version", "primary": "Kubernetes version", "detail": "Your cluster meets the recommended version", "severity": "debug"}},
+  {"name": "node.resources", "severity": "warn",
+   "insight": {"name": "node.resources", "primary": "Node resources", "detail": "Nodes have less than 8Gi memory", "severity": "warn"}},
+  {"name": "storage.class", "severity": "error",
+   "insight": {"name": "storage.class", "primary": "Default storage class", "detail": "No default storage class", "severity": "error"}}
+]`,
+	})
+
+	results, err := LoadAnalysisResults(b)
+	require.NoError(t, err)
+	assert.Equal(t, []AnalysisResult{
+		{Name: "kubernetes.version", Severity: AnalysisPass, Title: "Kubernetes version", Message: "Your cluster meets the recommended version"},
+		{Name: "node.resources", Severity: AnalysisWarn, Title: "Node resources", Message: "Nodes have less than 8Gi memory"},
+		{Name: "storage.class", Severity: AnalysisFail, Title: "Default storage class", Message: "No default storage class"},
+	}, results)
+}
+
+func TestLoadAnalysisResults_AnalyzeOutput(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"analysis.json": `{
+  "pass": [{"title": "Kubernetes version", "message": "OK"}],
+  "fail": [{"title": "Default storage class", "message": "Missing", "uri": "https://kubernetes.io/docs/concepts/storage/storage-classes/"}]
+}`,
+	})
+
+	results, err := LoadAnalysisResults(b)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, AnalysisFail, results[0].Severity)
+	assert.Equal(t, "https://kubernetes.io/docs/concepts/storage/storage-classes/", results[0].URI)
+	assert.Equal(t, AnalysisPass, results[1].Severity)
+}
+
+func TestLoadAnalysisResults_NotCollected(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/nodes.json": `{"items": []}`,
+	})
+
+	results, err := LoadAnalysisResults(b)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
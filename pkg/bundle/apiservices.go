@@ -0,0 +1,66 @@
+package bundle
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// APIServiceLocal is the backend of API services served by kube-apiserver
+// itself.
+const APIServiceLocal = "Local"
+
+// APIServiceInfo describes an API registered in the API aggregation layer.
+type APIServiceInfo struct {
+	Name    string `json:"name"`
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	// Service is `<namespace>/<name>` of the backing service or
+	// APIServiceLocal for APIs served by kube-apiserver.
+	Service string `json:"service"`
+	// Available is the status of the `Available` condition.
+	Available bool   `json:"available"`
+	Reason    string `json:"reason,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// ListAPIServices returns APIServices from the bundle. The apiservices are not
+// imported to the API server, as the aggregated APIs cannot be served, but
+// they show which extension APIs, e.g. metrics, were available in the cluster.
+// Empty result is returned when APIServices were not collected.
+func ListAPIServices(b Bundle) ([]APIServiceInfo, error) {
+	list, err := loadClusterResources(b, "apiservices")
+	if isNotCollected(err) {
+		return []APIServiceInfo{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]APIServiceInfo, 0, len(list.Items))
+	for i := range list.Items {
+		item := list.Items[i].Object
+
+		info := APIServiceInfo{Name: list.Items[i].GetName(), Service: APIServiceLocal}
+		info.Group, _, _ = unstructured.NestedString(item, "spec", "group")
+		info.Version, _, _ = unstructured.NestedString(item, "spec", "version")
+		if name, ok, _ := unstructured.NestedString(item, "spec", "service", "name"); ok {
+			namespace, _, _ := unstructured.NestedString(item, "spec", "service", "namespace")
+			info.Service = fmt.Sprintf("%s/%s", namespace, name)
+		}
+
+		conditions, _, _ := unstructured.NestedSlice(item, "status", "conditions")
+		for _, c := range conditions {
+			condition, ok := c.(map[string]any)
+			if !ok || condition["type"] != "Available" {
+				continue
+			}
+			info.Available = condition["status"] == "True"
+			info.Reason, _, _ = unstructured.NestedString(condition, "reason")
+			info.Message, _, _ = unstructured.NestedString(condition, "message")
+		}
+
+		result = append(result, info)
+	}
+	return result, nil
+}
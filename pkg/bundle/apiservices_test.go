@@ -0,0 +1,41 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAPIServices(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/apiservices.json": `{"items": [
+			{"metadata": {"name": "v1.apps"}, "spec": {"group": "apps", "version": "v1"},
+			 "status": {"conditions": [{"type": "Available", "status": "True", "reason": "Local"}]}},
+			{"metadata": {"name": "v1beta1.metrics.k8s.io"},
+			 "spec": {"group": "metrics.k8s.io", "version": "v1beta1",
+			  "service": {"namespace": "kube-system", "name": "metrics-server", "port": 443}},
+			 "status": {"conditions": [{"type": "Available", "status": "False", "reason": "FailedDiscoveryCheck",
+			  "message": "failing or missing response from https://10.96.0.10:443/apis/metrics.k8s.io/v1beta1"}]}}
+		]}`,
+	})
+
+	services, err := ListAPIServices(b)
+	require.NoError(t, err)
+	require.Len(t, services, 2)
+	assert.Equal(t, APIServiceInfo{
+		Name: "v1.apps", Group: "apps", Version: "v1", Service: APIServiceLocal, Available: true, Reason: "Local",
+	}, services[0])
+
+	metrics := services[1]
+	assert.Equal(t, "metrics.k8s.io", metrics.Group)
+	assert.Equal(t, "kube-system/metrics-server", metrics.Service)
+	assert.False(t, metrics.Available)
+	assert.Equal(t, "FailedDiscoveryCheck", metrics.Reason)
+}
+
+func TestListAPIServices_NotCollected(t *testing.T) {
+	services, err := ListAPIServices(newTestBundle(t, nil))
+	require.NoError(t, err)
+	assert.Empty(t, services)
+}
@@ -0,0 +1,103 @@
+package bundle
+
+import (
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const clusterAutoscalerName = "cluster-autoscaler"
+
+// AutoscalerNodeGroup is a node group configured by the `--nodes` flag.
+type AutoscalerNodeGroup struct {
+	Name string `json:"name"`
+	Min  int    `json:"min"`
+	Max  int    `json:"max"`
+}
+
+// AutoscalerInfo contains configuration of the cluster-autoscaler. Settings
+// that are not set by flags contain the cluster-autoscaler defaults.
+type AutoscalerInfo struct {
+	// Kind is the kind of the resource the configuration comes from,
+	// `Deployment` or `Pod`.
+	Kind  string `json:"kind"`
+	Name  string `json:"name"`
+	Image string `json:"image"`
+	// Flags are all flags passed to the cluster-autoscaler.
+	Flags map[string]string `json:"flags"`
+
+	NodeGroups             []AutoscalerNodeGroup `json:"nodeGroups,omitempty"`
+	NodeGroupAutoDiscovery []string              `json:"nodeGroupAutoDiscovery,omitempty"`
+	MaxNodesTotal          int                   `json:"maxNodesTotal"`
+	Expander               string                `json:"expander"`
+
+	ScaleDownEnabled              bool   `json:"scaleDownEnabled"`
+	ScaleDownDelayAfterAdd        string `json:"scaleDownDelayAfterAdd"`
+	ScaleDownUnneededTime         string `json:"scaleDownUnneededTime"`
+	ScaleDownUtilizationThreshold string `json:"scaleDownUtilizationThreshold"`
+}
+
+// DetectClusterAutoscaler returns configuration of the cluster-autoscaler
+// from its deployment in the `kube-system` namespace, or from its pod when
+// deployments weren't collected. Nil is returned when the cluster-autoscaler
+// isn't present.
+func DetectClusterAutoscaler(b Bundle) (*AutoscalerInfo, error) {
+	workload, err := findKubeSystemWorkload(b, isClusterAutoscaler, "deployments", "pods")
+	if err != nil || workload == nil {
+		return nil, err
+	}
+	return newAutoscalerInfo(workload.Kind, workload.Name, workload.Containers), nil
+}
+
+func isClusterAutoscaler(name string, labels map[string]string) bool {
+	return strings.Contains(name, clusterAutoscalerName) ||
+		labels["app.kubernetes.io/name"] == clusterAutoscalerName ||
+		labels["app"] == clusterAutoscalerName
+}
+
+func newAutoscalerInfo(kind, name string, containers []corev1.Container) *AutoscalerInfo {
+	info := &AutoscalerInfo{Kind: kind, Name: name, Flags: map[string]string{}}
+	container := findContainer(containers, clusterAutoscalerName)
+	if container == nil {
+		return info
+	}
+
+	info.Image = container.Image
+	info.Flags = containerFlags(container)
+	for _, nodes := range containerFlagValues(container, "nodes") {
+		if group, ok := parseAutoscalerNodeGroup(nodes); ok {
+			info.NodeGroups = append(info.NodeGroups, group)
+		}
+	}
+	info.NodeGroupAutoDiscovery = containerFlagValues(container, "node-group-auto-discovery")
+
+	flag := func(name, defaultValue string) string {
+		if value, ok := info.Flags[name]; ok {
+			return value
+		}
+		return defaultValue
+	}
+	info.MaxNodesTotal, _ = strconv.Atoi(flag("max-nodes-total", "0"))
+	info.Expander = flag("expander", "random")
+	info.ScaleDownEnabled, _ = strconv.ParseBool(flag("scale-down-enabled", "true"))
+	info.ScaleDownDelayAfterAdd = flag("scale-down-delay-after-add", "10m0s")
+	info.ScaleDownUnneededTime = flag("scale-down-unneeded-time", "10m0s")
+	info.ScaleDownUtilizationThreshold = flag("scale-down-utilization-threshold", "0.5")
+	return info
+}
+
+// parseAutoscalerNodeGroup parses `<min>:<max>:<name>` value of the `--nodes`
+// flag.
+func parseAutoscalerNodeGroup(value string) (AutoscalerNodeGroup, bool) {
+	parts := strings.SplitN(value, ":", 3)
+	if len(parts) != 3 {
+		return AutoscalerNodeGroup{}, false
+	}
+	minSize, minErr := strconv.Atoi(parts[0])
+	maxSize, maxErr := strconv.Atoi(parts[1])
+	if minErr != nil || maxErr != nil {
+		return AutoscalerNodeGroup{}, false
+	}
+	return AutoscalerNodeGroup{Name: parts[2], Min: minSize, Max: maxSize}, true
+}
@@ -0,0 +1,81 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const clusterAutoscalerDeployments = `{"items": [
+  {"metadata": {"name": "coredns", "namespace": "kube-system"}, "spec": {"template": {"spec": {"containers": [{"name": "coredns"}]}}}},
+  {
+    "metadata": {"name": "cluster-autoscaler-aws", "namespace": "kube-system"},
+    "spec": {"template": {"spec": {"containers": [{
+      "name": "aws-cluster-autoscaler",
+      "image": "registry.k8s.io/autoscaling/cluster-autoscaler:v1.28.2",
+      "command": ["./cluster-autoscaler"],
+      "args": [
+        "--cloud-provider=aws",
+        "--nodes=1:10:workers-a",
+        "--nodes=0:5:workers-gpu",
+        "--max-nodes-total=20",
+        "--expander=least-waste",
+        "--scale-down-unneeded-time=5m",
+        "--skip-nodes-with-local-storage=false"
+      ]
+    }]}}}
+  }
+]}`
+
+func TestDetectClusterAutoscaler(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/deployments/kube-system.json": clusterAutoscalerDeployments,
+	})
+
+	info, err := DetectClusterAutoscaler(b)
+	require.NoError(t, err)
+	require.NotNil(t, info)
+	assert.Equal(t, "Deployment", info.Kind)
+	assert.Equal(t, "cluster-autoscaler-aws", info.Name)
+	assert.Equal(t, "registry.k8s.io/autoscaling/cluster-autoscaler:v1.28.2", info.Image)
+	assert.Equal(t, "aws", info.Flags["cloud-provider"])
+	assert.Equal(t, []AutoscalerNodeGroup{
+		{Name: "workers-a", Min: 1, Max: 10},
+		{Name: "workers-gpu", Min: 0, Max: 5},
+	}, info.NodeGroups)
+	assert.Equal(t, 20, info.MaxNodesTotal)
+	assert.Equal(t, "least-waste", info.Expander)
+	assert.True(t, info.ScaleDownEnabled)
+	assert.Equal(t, "5m", info.ScaleDownUnneededTime)
+	assert.Equal(t, "10m0s", info.ScaleDownDelayAfterAdd, "default value")
+	assert.Equal(t, "0.5", info.ScaleDownUtilizationThreshold, "default value")
+}
+
+func TestDetectClusterAutoscaler_Pod(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/pods/kube-system.json": `{"items": [{
+  "metadata": {"name": "autoscaler-7d9f", "labels": {"app.kubernetes.io/name": "cluster-autoscaler"}},
+  "spec": {"containers": [{"name": "autoscaler", "args": ["--node-group-auto-discovery=asg:tag=k8s.io/cluster-autoscaler/enabled", "--scale-down-enabled=false"]}]}
+}]}`,
+	})
+
+	info, err := DetectClusterAutoscaler(b)
+	require.NoError(t, err)
+	require.NotNil(t, info)
+	assert.Equal(t, "Pod", info.Kind)
+	assert.Equal(t, []string{"asg:tag=k8s.io/cluster-autoscaler/enabled"}, info.NodeGroupAutoDiscovery)
+	assert.False(t, info.ScaleDownEnabled)
+	assert.Equal(t, "random", info.Expander)
+}
+
+func TestDetectClusterAutoscaler_NotPresent(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/deployments/kube-system.json": `{"items": []}`,
+		"cluster-resources/pods/kube-system.json":        `{"items": []}`,
+	})
+
+	info, err := DetectClusterAutoscaler(b)
+	require.NoError(t, err)
+	assert.Nil(t, info)
+}
@@ -23,10 +23,12 @@ type Bundle interface {
 
 type bundle struct {
 	afero.Fs
+
+	layout Layout
 }
 
-func (bundle) Layout() Layout {
-	return defaultLayout{}
+func (b bundle) Layout() Layout {
+	return b.layout
 }
 
 // New creates bundle representation from given path. It supports reading extracted
@@ -77,7 +79,7 @@ func New(path string) (Bundle, error) {
 			return nil, fmt.Errorf("more than 1 directory in archive, cannot infer bundle directory")
 		}
 
-		return FromFs(fromDir(filepath.Join(tmpDir, entries[0].Name()))), nil
+		return fromDirWithEnvLayout(filepath.Join(tmpDir, entries[0].Name()))
 	default:
 		absPath, err := filepath.Abs(path)
 		if err != nil {
@@ -93,7 +95,7 @@ func New(path string) (Bundle, error) {
 			break
 		}
 
-		return FromFs(fromDir(absPath)), nil
+		return fromDirWithEnvLayout(absPath)
 	}
 
 	return nil, ErrUnknownBundleFormat
@@ -101,7 +103,62 @@ func New(path string) (Bundle, error) {
 
 // FromFs allows to create bundle form provided afero.Fs.
 func FromFs(fs afero.Fs) Bundle {
-	return bundle{fs}
+	return FromFsWithLayout(fs, defaultLayout{})
+}
+
+// FromFsWithLayout allows to create bundle from provided afero.Fs with custom
+// layout.
+func FromFsWithLayout(fs afero.Fs, layout Layout) Bundle {
+	return bundle{Fs: fs, layout: layout}
+}
+
+func fromDirWithEnvLayout(path string) (Bundle, error) {
+	fs := fromDir(path)
+	layout, err := LoadLayoutWithFallback(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := bundleRoot(fs, layout)
+	if err != nil {
+		return nil, err
+	}
+	if root != "" {
+		log.Printf("Using bundle data from %q directory ...", root)
+		fs = fromDir(filepath.Join(path, root))
+
+		// The config stored in the detected wrapper directory applies to
+		// the bundle data, a configured root prefix is kept.
+		if !hasRootPrefix(layout) {
+			if layout, err = LoadLayoutWithFallback(fs); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if IsClusterInfoDump(fs) {
+		log.Printf("Converting cluster-info dump from %q ...", path)
+		b, err := FromClusterInfoDump(fs)
+		if err != nil {
+			return nil, err
+		}
+		layout, err := WithSkipListsFromEnv(withoutPaths(layout))
+		if err != nil {
+			return nil, err
+		}
+		return FromFsWithLayout(b, layout), nil
+	}
+
+	if IsOpenShift(fs, layout) {
+		log.Printf("Detected bundle collected from OpenShift ...")
+		layout = WithOpenShiftResources(layout)
+	}
+
+	layout, err = WithSkipListsFromEnv(layout)
+	if err != nil {
+		return nil, err
+	}
+	return FromFsWithLayout(fs, layout), nil
 }
 
 func unarchiveToDirectory(archive, destDir string) error {
@@ -0,0 +1,63 @@
+package bundle
+
+import (
+	"fmt"
+
+	"github.com/spf13/afero"
+	"sigs.k8s.io/yaml"
+)
+
+// SpecInfo describes the troubleshoot spec and version that produced
+// the bundle.
+type SpecInfo struct {
+	APIVersion string `json:"apiVersion"`
+	// Kind is the kind of the spec, e.g. `SupportBundle`.
+	Kind string `json:"kind"`
+	// Name is the name of the spec, empty when troubleshoot didn't store it.
+	Name string `json:"name,omitempty"`
+	// Version is the version of troubleshoot, e.g. `0.85.0`.
+	Version string `json:"version,omitempty"`
+	// Source is the bundle file with the spec info.
+	Source string `json:"source"`
+}
+
+// bundleSpec is a subset of the version file that troubleshoot stores in
+// the bundle root.
+type bundleSpec struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Spec struct {
+		VersionNumber string `json:"versionNumber"`
+	} `json:"spec"`
+}
+
+// DetectBundleSpec returns the troubleshoot spec and version from the version
+// file embedded by troubleshoot in the bundle root. Nil is returned when
+// the bundle doesn't contain the file.
+func DetectBundleSpec(b Bundle) (*SpecInfo, error) {
+	for _, name := range collectionMetadataFiles() {
+		data, err := afero.ReadFile(b, name)
+		if isNotCollected(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		spec := bundleSpec{}
+		if err := yaml.Unmarshal(data, &spec); err != nil {
+			return nil, fmt.Errorf("failed to parse %q: %w", name, err)
+		}
+		return &SpecInfo{
+			APIVersion: spec.APIVersion,
+			Kind:       spec.Kind,
+			Name:       spec.Metadata.Name,
+			Version:    spec.Spec.VersionNumber,
+			Source:     name,
+		}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,46 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectBundleSpec(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"version.yaml": `apiVersion: troubleshoot.sh/v1beta2
+kind: SupportBundle
+metadata:
+  name: app-support-bundle
+  creationTimestamp: null
+spec:
+  versionNumber: 0.85.0
+`,
+	})
+
+	spec, err := DetectBundleSpec(b)
+	require.NoError(t, err)
+	assert.Equal(t, &SpecInfo{
+		APIVersion: "troubleshoot.sh/v1beta2",
+		Kind:       "SupportBundle",
+		Name:       "app-support-bundle",
+		Version:    "0.85.0",
+		Source:     "version.yaml",
+	}, spec)
+}
+
+func TestDetectBundleSpec_NotPresent(t *testing.T) {
+	spec, err := DetectBundleSpec(newTestBundle(t, map[string]string{
+		"cluster-resources/nodes.json": `{"items": []}`,
+	}))
+	require.NoError(t, err)
+	assert.Nil(t, spec)
+}
+
+func TestDetectBundleSpec_Invalid(t *testing.T) {
+	_, err := DetectBundleSpec(newTestBundle(t, map[string]string{
+		"version.yaml": "kind: [",
+	}))
+	assert.ErrorContains(t, err, "failed to parse")
+}
@@ -0,0 +1,28 @@
+package bundle
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBundle(t *testing.T, files map[string]string) Bundle {
+	t.Helper()
+
+	fs := afero.NewMemMapFs()
+	for path, data := range files {
+		require.NoError(t, afero.WriteReader(fs, path, bytes.NewBufferString(data)))
+	}
+	return FromFs(fs)
+}
+
+func jsonString(t *testing.T, s string) string {
+	t.Helper()
+
+	data, err := json.Marshal(s)
+	require.NoError(t, err)
+	return string(data)
+}
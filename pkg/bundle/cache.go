@@ -0,0 +1,114 @@
+package bundle
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/spf13/afero"
+)
+
+// decompressedCache caches decompressed content of bundle files.
+type decompressedCache interface {
+	// decompressed returns cached content of the path or stores the content
+	// returned by the decompress function.
+	decompressed(path string, decompress func() ([]byte, error)) ([]byte, error)
+}
+
+// DecompressedCacheFs is afero.Fs wrapper that keeps decompressed content of
+// recently read `.gz` files in memory, so that large compressed resources and
+// logs aren't decompressed on every request. The least recently used entries
+// are evicted when the total size of cached content exceeds the limit.
+type DecompressedCacheFs struct {
+	afero.Fs
+
+	maxBytes int64
+
+	mu      sync.Mutex
+	size    int64
+	recent  *list.List
+	entries map[string]*list.Element
+}
+
+type cacheEntry struct {
+	path string
+	data []byte
+}
+
+// NewDecompressedCacheFs wraps the fs with a cache of decompressed content
+// limited to maxBytes. Files larger than the limit are not cached.
+func NewDecompressedCacheFs(fs afero.Fs, maxBytes int64) *DecompressedCacheFs {
+	return &DecompressedCacheFs{
+		Fs:       fs,
+		maxBytes: maxBytes,
+		recent:   list.New(),
+		entries:  map[string]*list.Element{},
+	}
+}
+
+// Size returns the total size of cached content in bytes.
+func (c *DecompressedCacheFs) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+func (c *DecompressedCacheFs) decompressed(path string, decompress func() ([]byte, error)) ([]byte, error) {
+	if data, ok := c.get(path); ok {
+		return data, nil
+	}
+
+	// The content is decompressed without holding the lock, concurrent reads
+	// of the same file may decompress it more than once.
+	data, err := decompress()
+	if err != nil {
+		return nil, err
+	}
+	c.add(path, data)
+	return data, nil
+}
+
+func (c *DecompressedCacheFs) get(path string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[path]
+	if !ok {
+		return nil, false
+	}
+	c.recent.MoveToFront(element)
+	return element.Value.(*cacheEntry).data, true
+}
+
+func (c *DecompressedCacheFs) add(path string, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[path]; ok || int64(len(data)) > c.maxBytes {
+		return
+	}
+
+	c.entries[path] = c.recent.PushFront(&cacheEntry{path: path, data: data})
+	c.size += int64(len(data))
+	for c.size > c.maxBytes {
+		oldest := c.recent.Back()
+		entry := oldest.Value.(*cacheEntry)
+		c.recent.Remove(oldest)
+		delete(c.entries, entry.path)
+		c.size -= int64(len(entry.data))
+	}
+}
+
+// cacheOf returns the decompressed content cache of the fs, or of the fs
+// wrapped by the bundle. Nil is returned when the content isn't cached.
+func cacheOf(fs afero.Fs) decompressedCache {
+	for {
+		switch f := fs.(type) {
+		case decompressedCache:
+			return f
+		case bundle:
+			fs = f.Fs
+		default:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package bundle
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecompressedCacheFs_Eviction(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	for _, name := range []string{"a", "b", "c"} {
+		require.NoError(t, afero.WriteFile(fs, name+".json.gz", []byte(gzipString(t, name+"-0123456789")), 0o644))
+	}
+	require.NoError(t, afero.WriteFile(fs, "large.json.gz", []byte(gzipString(t, string(make([]byte, 100)))), 0o644))
+
+	cache := NewDecompressedCacheFs(fs, 30)
+	b := FromFs(cache)
+	read := func(name string) string {
+		data, err := readFile(b, name+".json.gz")
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	assert.Equal(t, "a-0123456789", read("a"))
+	read("b")
+	assert.Equal(t, int64(24), cache.Size())
+
+	// Cached content is served also when the file changes.
+	require.NoError(t, afero.WriteFile(fs, "a.json.gz", []byte(gzipString(t, "changed")), 0o644))
+	assert.Equal(t, "a-0123456789", read("a"))
+
+	// The least recently used `b` is evicted.
+	read("c")
+	assert.Equal(t, int64(24), cache.Size())
+	require.NoError(t, afero.WriteFile(fs, "b.json.gz", []byte(gzipString(t, "changed")), 0o644))
+	assert.Equal(t, "a-0123456789", read("a"))
+	assert.Equal(t, "changed", read("b"))
+
+	// Files over the limit are not cached.
+	assert.Len(t, read("large"), 100)
+	assert.LessOrEqual(t, cache.Size(), int64(30))
+}
+
+func TestDecompressedCacheFs_Concurrency(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	for i := 0; i < 10; i++ {
+		require.NoError(t, afero.WriteFile(fs, fmt.Sprintf("%d.json.gz", i), []byte(gzipString(t, fmt.Sprintf("file-%d", i))), 0o644))
+	}
+	cache := NewDecompressedCacheFs(fs, 30)
+	b := FromFs(cache)
+
+	wg := sync.WaitGroup{}
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				name := fmt.Sprintf("%d.json.gz", i%10)
+				data, err := readFile(b, name)
+				assert.NoError(t, err)
+				assert.Equal(t, fmt.Sprintf("file-%d", i%10), string(data))
+			}
+		}()
+	}
+	wg.Wait()
+	assert.LessOrEqual(t, cache.Size(), int64(30))
+}
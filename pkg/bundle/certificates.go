@@ -0,0 +1,167 @@
+package bundle
+
+import (
+	"bufio"
+	"bytes"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+const (
+	// CertExpiryWarningPeriod is the period before expiration in which
+	// certificates are flagged as expiring soon.
+	CertExpiryWarningPeriod = 30 * 24 * time.Hour
+
+	// kubeadmCertsExpiresLayout is the time format of the `kubeadm certs
+	// check-expiration` output, e.g. `Dec 30, 2024 23:36 UTC`.
+	kubeadmCertsExpiresLayout = "Jan 02, 2006 15:04 MST"
+
+	// maxCertExpiryFileSize limits size of files that are checked for
+	// the `kubeadm certs check-expiration` output.
+	maxCertExpiryFileSize = 1 << 20
+)
+
+// CertInfo describes expiration of a certificate.
+type CertInfo struct {
+	Name    string    `json:"name"`
+	Expires time.Time `json:"expires"`
+	// CertificateAuthority is the name of the CA that signed the certificate.
+	// It is empty for certificate authorities.
+	CertificateAuthority string `json:"certificateAuthority,omitempty"`
+	IsCA                 bool   `json:"isCA"`
+	ExternallyManaged    bool   `json:"externallyManaged"`
+	// Expired and ExpiresSoon are relative to the time when the bundle was
+	// collected.
+	Expired     bool `json:"expired"`
+	ExpiresSoon bool `json:"expiresSoon"`
+	// Source is the bundle file with the certificate info.
+	Source string `json:"source"`
+}
+
+// DetectCertificateExpiry returns expiration of certificates reported by
+// the `kubeadm certs check-expiration` output collected in the bundle, e.g. by
+// a `run` host collector. Certificates that expire within
+// CertExpiryWarningPeriod after the bundle collection are flagged as expiring
+// soon. Empty result is returned when no certificate info was collected.
+func DetectCertificateExpiry(b Bundle) ([]CertInfo, error) {
+	sources, err := findKubeadmCertsOutputs(b)
+	if err != nil {
+		return nil, err
+	}
+
+	now, err := DetectCollectionTime(b)
+	if err != nil {
+		return nil, err
+	}
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	result := []CertInfo{}
+	for _, source := range sources {
+		data, err := afero.ReadFile(b, source)
+		if err != nil {
+			return nil, err
+		}
+		for _, cert := range parseKubeadmCertsExpiration(data) {
+			cert.Source = source
+			cert.Expired = !cert.Expires.After(now)
+			cert.ExpiresSoon = !cert.Expired && cert.Expires.Before(now.Add(CertExpiryWarningPeriod))
+			result = append(result, cert)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Expires.Before(result[j].Expires)
+	})
+	return result, nil
+}
+
+// findKubeadmCertsOutputs returns files outside of the cluster resources and
+// pod logs directories that contain the `kubeadm certs check-expiration` output.
+func findKubeadmCertsOutputs(b Bundle) ([]string, error) {
+	var found []string
+	err := afero.Walk(b, ".", func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if path == b.Layout().ClusterResources() || path == b.Layout().PodLogs() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Size() > maxCertExpiryFileSize {
+			return nil
+		}
+
+		data, err := afero.ReadFile(b, path)
+		if err != nil {
+			return err
+		}
+		if bytes.Contains(data, []byte("RESIDUAL TIME")) && bytes.Contains(data, []byte("CERTIFICATE AUTHORITY")) {
+			found = append(found, path)
+		}
+		return nil
+	})
+	return found, err
+}
+
+// parseKubeadmCertsExpiration parses tables from the `kubeadm certs
+// check-expiration` output:
+//
+//	CERTIFICATE                EXPIRES                  RESIDUAL TIME   CERTIFICATE AUTHORITY   EXTERNALLY MANAGED
+//	admin.conf                 Dec 30, 2024 23:36 UTC   364d            ca                      no
+//
+//	CERTIFICATE AUTHORITY   EXPIRES                  RESIDUAL TIME   EXTERNALLY MANAGED
+//	ca                      Dec 28, 2033 23:36 UTC   9y              no
+//
+// Rows that cannot be parsed, e.g. missing certificates, are skipped.
+func parseKubeadmCertsExpiration(data []byte) []CertInfo {
+	columnSeparator := regexp.MustCompile(`\s{2,}`)
+
+	var result []CertInfo
+	var header []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			header = nil
+			continue
+		}
+
+		columns := columnSeparator.Split(line, -1)
+		if strings.HasPrefix(line, "CERTIFICATE") && strings.Contains(line, "EXPIRES") {
+			header = columns
+			continue
+		}
+		if header == nil || len(columns) != len(header) {
+			continue
+		}
+
+		cert := CertInfo{Name: columns[0], IsCA: header[0] == "CERTIFICATE AUTHORITY"}
+		valid := true
+		for i, name := range header[1:] {
+			value := columns[i+1]
+			switch name {
+			case "EXPIRES":
+				expires, err := time.Parse(kubeadmCertsExpiresLayout, value)
+				cert.Expires, valid = expires.UTC(), err == nil
+			case "CERTIFICATE AUTHORITY":
+				cert.CertificateAuthority = value
+			case "EXTERNALLY MANAGED":
+				cert.ExternallyManaged = value == "yes"
+			}
+		}
+		if valid {
+			result = append(result, cert)
+		}
+	}
+	return result
+}
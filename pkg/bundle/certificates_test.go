@@ -0,0 +1,68 @@
+package bundle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const kubeadmCertsCheckExpiration = `[check-expiration] Reading configuration from the cluster...
+[check-expiration] FYI: You can look at this config file with 'kubectl -n kube-system get cm kubeadm-config -o yaml'
+
+CERTIFICATE                EXPIRES                  RESIDUAL TIME   CERTIFICATE AUTHORITY   EXTERNALLY MANAGED
+admin.conf                 Mar 20, 2024 10:00 UTC   14d             ca                      no
+apiserver                  Mar 01, 2024 10:00 UTC   <invalid>       ca                      no
+apiserver-etcd-client      Jan 10, 2025 10:00 UTC   310d            etcd-ca                 no
+front-proxy-client         Jan 10, 2025 10:00 UTC   310d            front-proxy-ca          yes
+!MISSING! super-admin.conf
+
+CERTIFICATE AUTHORITY   EXPIRES                  RESIDUAL TIME   EXTERNALLY MANAGED
+ca                      Jan 08, 2034 10:00 UTC   9y              no
+`
+
+func TestDetectCertificateExpiry(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"version.yaml": "kind: SupportBundle\nmetadata:\n  creationTimestamp: \"2024-03-05T10:00:00Z\"\n",
+		"host-collectors/run-host/kubeadm-certs.txt": kubeadmCertsCheckExpiration,
+		"cluster-resources/nodes.json":               `{"items": []}`,
+	})
+
+	certs, err := DetectCertificateExpiry(b)
+	require.NoError(t, err)
+
+	source := "host-collectors/run-host/kubeadm-certs.txt"
+	assert.Equal(t, []CertInfo{
+		{
+			Name: "apiserver", Expires: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+			CertificateAuthority: "ca", Expired: true, Source: source,
+		},
+		{
+			Name: "admin.conf", Expires: time.Date(2024, 3, 20, 10, 0, 0, 0, time.UTC),
+			CertificateAuthority: "ca", ExpiresSoon: true, Source: source,
+		},
+		{
+			Name: "apiserver-etcd-client", Expires: time.Date(2025, 1, 10, 10, 0, 0, 0, time.UTC),
+			CertificateAuthority: "etcd-ca", Source: source,
+		},
+		{
+			Name: "front-proxy-client", Expires: time.Date(2025, 1, 10, 10, 0, 0, 0, time.UTC),
+			CertificateAuthority: "front-proxy-ca", ExternallyManaged: true, Source: source,
+		},
+		{
+			Name: "ca", Expires: time.Date(2034, 1, 8, 10, 0, 0, 0, time.UTC),
+			IsCA: true, Source: source,
+		},
+	}, certs)
+}
+
+func TestDetectCertificateExpiry_NotCollected(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/nodes.json": `{"items": []}`,
+	})
+
+	certs, err := DetectCertificateExpiry(b)
+	require.NoError(t, err)
+	assert.Empty(t, certs)
+}
@@ -0,0 +1,93 @@
+package bundle
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const chunkSuffixPattern = `^(.+)\.part(\d+)$`
+
+// SplitChunkPath parses path of a file chunk, e.g. `pods.json.part2`, into
+// the path of the logical file and the part number.
+func SplitChunkPath(path string) (string, int, bool) {
+	m := regexp.MustCompile(chunkSuffixPattern).FindStringSubmatch(path)
+	if m == nil {
+		return "", 0, false
+	}
+	part, err := strconv.Atoi(m[2])
+	if err != nil {
+		return "", 0, false
+	}
+	return m[1], part, true
+}
+
+// ChunkPaths returns paths of all `<path>.partN` chunks ordered by the part
+// number.
+func ChunkPaths(fs afero.Fs, path string) ([]string, error) {
+	matches, err := afero.Glob(fs, globEscape(path)+".part*")
+	if err != nil {
+		return nil, err
+	}
+
+	parts := map[string]int{}
+	chunks := []string{}
+	for _, match := range matches {
+		if base, part, ok := SplitChunkPath(match); ok && base == path {
+			parts[match] = part
+			chunks = append(chunks, match)
+		}
+	}
+	sort.Slice(chunks, func(i, j int) bool { return parts[chunks[i]] < parts[chunks[j]] })
+	return chunks, nil
+}
+
+// readChunks concatenates all chunks of the file. False is returned when
+// the file wasn't chunked.
+func readChunks(fs afero.Fs, path string) ([]byte, bool, error) {
+	chunks, err := ChunkPaths(fs, path)
+	if err != nil || len(chunks) == 0 {
+		return nil, false, err
+	}
+
+	buf := &bytes.Buffer{}
+	for _, chunk := range chunks {
+		data, err := afero.ReadFile(fs, chunk)
+		if err != nil {
+			return nil, false, err
+		}
+		buf.Write(data)
+	}
+	return buf.Bytes(), true, nil
+}
+
+// LoadResourcesFromChunks loads resources from a file that was split by size
+// into `<path>.part1`, `<path>.part2`, ... files. The chunks are concatenated
+// in order of the part number and parsed as a single file.
+func LoadResourcesFromChunks(fs afero.Fs, path string) (*unstructured.UnstructuredList, error) {
+	data, ok, err := readChunks(fs, path)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("no chunks found for %q", path)
+	}
+
+	data, err = decompress(data, path)
+	if err != nil {
+		return nil, err
+	}
+	return parseResources(data, path)
+}
+
+// globEscape escapes glob meta characters in the path.
+func globEscape(path string) string {
+	dir, file := filepath.Split(path)
+	return dir + regexp.MustCompile(`([*?\[\\])`).ReplaceAllString(file, `\$1`)
+}
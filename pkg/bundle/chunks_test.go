@@ -0,0 +1,50 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func chunkedPodsBundle(t *testing.T) Bundle {
+	t.Helper()
+
+	return newTestBundle(t, map[string]string{
+		"cluster-resources/pods/default.json.part2":  `{"metadata": {"name": "b"}},`,
+		"cluster-resources/pods/default.json.part1":  `{"kind": "PodList", "apiVersion": "v1", "items": [{"metadata": {"name": "a"}},`,
+		"cluster-resources/pods/default.json.part10": `{"metadata": {"name": "c"}}]}`,
+	})
+}
+
+func TestSplitChunkPath(t *testing.T) {
+	base, part, ok := SplitChunkPath("pods/default.json.part12")
+	require.True(t, ok)
+	assert.Equal(t, "pods/default.json", base)
+	assert.Equal(t, 12, part)
+
+	_, _, ok = SplitChunkPath("pods/default.json")
+	assert.False(t, ok)
+}
+
+func TestLoadResourcesFromChunks(t *testing.T) {
+	b := chunkedPodsBundle(t)
+
+	list, err := LoadResourcesFromChunks(b, "cluster-resources/pods/default.json")
+	require.NoError(t, err)
+	require.Len(t, list.Items, 3)
+	assert.Equal(t, []string{"a", "b", "c"}, []string{
+		list.Items[0].GetName(), list.Items[1].GetName(), list.Items[2].GetName(),
+	})
+
+	list, err = LoadResourcesFromFile(b, "cluster-resources/pods/default.json")
+	require.NoError(t, err, "chunks are loaded when the file doesn't exist")
+	assert.Len(t, list.Items, 3)
+
+	list, err = loadNamespacedResources(b, "pods")
+	require.NoError(t, err)
+	assert.Len(t, list.Items, 3, "chunks are loaded once")
+
+	_, err = LoadResourcesFromChunks(b, "cluster-resources/pods/other.json")
+	assert.Error(t, err)
+}
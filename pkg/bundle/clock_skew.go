@@ -0,0 +1,81 @@
+package bundle
+
+import (
+	"sort"
+	"time"
+)
+
+// NodeClockOffset is the estimated clock difference of a node.
+type NodeClockOffset struct {
+	Name string `json:"name"`
+	// LastHeartbeatTime is the latest `lastHeartbeatTime` of the node
+	// conditions.
+	LastHeartbeatTime time.Time `json:"lastHeartbeatTime"`
+	// Offset is the difference between the node heartbeat and the reference
+	// time. Positive offset means that the node clock is ahead.
+	Offset time.Duration `json:"offset"`
+}
+
+// SkewReport is the estimated clock skew between nodes.
+type SkewReport struct {
+	// Reference is the median of the latest heartbeat times of all nodes.
+	Reference time.Time         `json:"reference"`
+	Nodes     []NodeClockOffset `json:"nodes"`
+	// MaxSkew is the difference between the latest and the earliest node
+	// heartbeat.
+	MaxSkew time.Duration `json:"maxSkew"`
+}
+
+// DetectClockSkew estimates clock differences between nodes. The kubelet
+// stamps `lastHeartbeatTime` of the node conditions with its own clock when it
+// updates the node status, so heartbeats of nodes with synchronized clocks,
+// collected at the same time, are close to each other. The latest heartbeat
+// of each node is compared to the median of all nodes. This is a heuristic:
+// the kubelet updates the status, when nothing changes, only every few
+// minutes, so offsets smaller than the node status report frequency are not
+// meaningful and large offsets can also be caused by nodes that stopped
+// reporting. Nodes without heartbeats are skipped. Nil is returned when nodes
+// were not collected or none of them reported a heartbeat.
+func DetectClockSkew(b Bundle) (*SkewReport, error) {
+	nodes, err := loadNodes(b)
+	if isNotCollected(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SkewReport{Nodes: []NodeClockOffset{}}
+	for i := range nodes {
+		var heartbeat time.Time
+		for _, c := range nodes[i].Status.Conditions {
+			if c.LastHeartbeatTime.After(heartbeat) {
+				heartbeat = c.LastHeartbeatTime.UTC()
+			}
+		}
+		if !heartbeat.IsZero() {
+			report.Nodes = append(report.Nodes, NodeClockOffset{Name: nodes[i].GetName(), LastHeartbeatTime: heartbeat})
+		}
+	}
+	if len(report.Nodes) == 0 {
+		return nil, nil
+	}
+
+	heartbeats := make([]time.Time, 0, len(report.Nodes))
+	for _, node := range report.Nodes {
+		heartbeats = append(heartbeats, node.LastHeartbeatTime)
+	}
+	sort.Slice(heartbeats, func(i, j int) bool { return heartbeats[i].Before(heartbeats[j]) })
+	report.Reference = heartbeats[len(heartbeats)/2]
+	if len(heartbeats)%2 == 0 {
+		low := heartbeats[len(heartbeats)/2-1]
+		report.Reference = low.Add(report.Reference.Sub(low) / 2)
+	}
+	report.MaxSkew = heartbeats[len(heartbeats)-1].Sub(heartbeats[0])
+
+	for i := range report.Nodes {
+		report.Nodes[i].Offset = report.Nodes[i].LastHeartbeatTime.Sub(report.Reference)
+	}
+	sort.Slice(report.Nodes, func(i, j int) bool { return report.Nodes[i].Name < report.Nodes[j].Name })
+	return report, nil
+}
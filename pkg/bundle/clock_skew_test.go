@@ -0,0 +1,49 @@
+package bundle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectClockSkew(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/nodes.json": `{"items": [
+  {"metadata": {"name": "worker-2"}, "status": {"conditions": [
+    {"type": "MemoryPressure", "status": "False", "lastHeartbeatTime": "2024-01-01T10:00:05Z"},
+    {"type": "Ready", "status": "True", "lastHeartbeatTime": "2024-01-01T10:00:10Z"}
+  ]}},
+  {"metadata": {"name": "worker-1"}, "status": {"conditions": [
+    {"type": "Ready", "status": "True", "lastHeartbeatTime": "2024-01-01T10:00:00Z"}
+  ]}},
+  {"metadata": {"name": "worker-3"}, "status": {"conditions": [
+    {"type": "Ready", "status": "True", "lastHeartbeatTime": "2024-01-01T10:07:00Z"}
+  ]}},
+  {"metadata": {"name": "worker-4"}}
+]}`,
+	})
+
+	report, err := DetectClockSkew(b)
+	require.NoError(t, err)
+	require.NotNil(t, report)
+
+	assert.Equal(t, time.Date(2024, 1, 1, 10, 0, 10, 0, time.UTC), report.Reference)
+	assert.Equal(t, 7*time.Minute, report.MaxSkew)
+	require.Len(t, report.Nodes, 3)
+	assert.Equal(t, "worker-1", report.Nodes[0].Name)
+	assert.Equal(t, -10*time.Second, report.Nodes[0].Offset)
+	assert.Equal(t, time.Duration(0), report.Nodes[1].Offset)
+	assert.Equal(t, 6*time.Minute+50*time.Second, report.Nodes[2].Offset)
+}
+
+func TestDetectClockSkew_NoHeartbeats(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/nodes.json": `{"items": [{"metadata": {"name": "worker-1"}}]}`,
+	})
+
+	report, err := DetectClockSkew(b)
+	require.NoError(t, err)
+	assert.Nil(t, report)
+}
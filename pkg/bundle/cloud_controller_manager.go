@@ -0,0 +1,62 @@
+package bundle
+
+import (
+	"strings"
+)
+
+const cloudControllerManagerName = "cloud-controller-manager"
+
+// CCMInfo contains configuration of the external cloud-controller-manager.
+type CCMInfo struct {
+	// Kind is the kind of the resource the configuration comes from,
+	// `Deployment`, `DaemonSet` or `Pod` for static pods.
+	Kind  string `json:"kind"`
+	Name  string `json:"name"`
+	Image string `json:"image"`
+	// Flags are all flags passed to the cloud-controller-manager.
+	Flags map[string]string `json:"flags"`
+
+	CloudProvider string `json:"cloudProvider"`
+	CloudConfig   string `json:"cloudConfig,omitempty"`
+	ClusterName   string `json:"clusterName,omitempty"`
+	// Controllers are the controllers enabled by the `--controllers` flag,
+	// e.g. `*` or `-route`.
+	Controllers          []string `json:"controllers,omitempty"`
+	ConfigureCloudRoutes bool     `json:"configureCloudRoutes"`
+}
+
+// DetectCloudControllerManager returns configuration of the external
+// cloud-controller-manager running as a deployment, daemonset or static pod
+// in the `kube-system` namespace. Nil is returned when the cluster uses
+// in-tree cloud provider or no cloud provider at all.
+func DetectCloudControllerManager(b Bundle) (*CCMInfo, error) {
+	workload, err := findKubeSystemWorkload(b, isCloudControllerManager, "deployments", "daemonsets", "pods")
+	if err != nil || workload == nil {
+		return nil, err
+	}
+
+	info := &CCMInfo{Kind: workload.Kind, Name: workload.Name, Flags: map[string]string{}}
+	container := findContainer(workload.Containers, cloudControllerManagerName)
+	if container == nil {
+		return info, nil
+	}
+
+	info.Image = container.Image
+	info.Flags = containerFlags(container)
+	info.CloudProvider = info.Flags["cloud-provider"]
+	info.CloudConfig = info.Flags["cloud-config"]
+	info.ClusterName = info.Flags["cluster-name"]
+	if controllers := info.Flags["controllers"]; controllers != "" {
+		info.Controllers = strings.Split(controllers, ",")
+	}
+	// Cloud routes are configured by default.
+	info.ConfigureCloudRoutes = info.Flags["configure-cloud-routes"] != "false"
+	return info, nil
+}
+
+func isCloudControllerManager(name string, labels map[string]string) bool {
+	return strings.Contains(name, cloudControllerManagerName) ||
+		labels["component"] == cloudControllerManagerName ||
+		strings.HasSuffix(labels["app.kubernetes.io/name"], cloudControllerManagerName) ||
+		strings.HasSuffix(labels["k8s-app"], cloudControllerManagerName)
+}
@@ -0,0 +1,76 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const externalCCMDaemonSets = `{"items": [
+  {"metadata": {"name": "kube-proxy", "namespace": "kube-system"}, "spec": {"template": {"spec": {"containers": [{"name": "kube-proxy"}]}}}},
+  {
+    "metadata": {"name": "aws-cloud-controller-manager", "namespace": "kube-system", "labels": {"k8s-app": "aws-cloud-controller-manager"}},
+    "spec": {"template": {"spec": {"containers": [{
+      "name": "aws-cloud-controller-manager",
+      "image": "registry.k8s.io/provider-aws/cloud-controller-manager:v1.28.1",
+      "args": [
+        "--v=2",
+        "--cloud-provider=aws",
+        "--cloud-config=/etc/kubernetes/cloud.conf",
+        "--cluster-name=prod",
+        "--controllers=*,-route",
+        "--configure-cloud-routes=false"
+      ]
+    }]}}}
+  }
+]}`
+
+func TestDetectCloudControllerManager(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/deployments/kube-system.json": `{"items": []}`,
+		"cluster-resources/daemonsets/kube-system.json":  externalCCMDaemonSets,
+	})
+
+	info, err := DetectCloudControllerManager(b)
+	require.NoError(t, err)
+	require.NotNil(t, info)
+	assert.Equal(t, "DaemonSet", info.Kind)
+	assert.Equal(t, "aws-cloud-controller-manager", info.Name)
+	assert.Equal(t, "registry.k8s.io/provider-aws/cloud-controller-manager:v1.28.1", info.Image)
+	assert.Equal(t, "aws", info.CloudProvider)
+	assert.Equal(t, "/etc/kubernetes/cloud.conf", info.CloudConfig)
+	assert.Equal(t, "prod", info.ClusterName)
+	assert.Equal(t, []string{"*", "-route"}, info.Controllers)
+	assert.False(t, info.ConfigureCloudRoutes)
+	assert.Equal(t, "2", info.Flags["v"])
+}
+
+func TestDetectCloudControllerManager_StaticPod(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/pods/kube-system.json": `{"items": [{
+  "metadata": {"name": "cloud-controller-manager-cp-1", "labels": {"component": "cloud-controller-manager"}},
+  "spec": {"containers": [{"name": "cloud-controller-manager", "command": ["cloud-controller-manager", "--cloud-provider=openstack"]}]}
+}]}`,
+	})
+
+	info, err := DetectCloudControllerManager(b)
+	require.NoError(t, err)
+	require.NotNil(t, info)
+	assert.Equal(t, "Pod", info.Kind)
+	assert.Equal(t, "openstack", info.CloudProvider)
+	assert.True(t, info.ConfigureCloudRoutes)
+}
+
+func TestDetectCloudControllerManager_InTree(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/pods/kube-system.json": `{"items": [{
+  "metadata": {"name": "kube-controller-manager-cp-1", "labels": {"component": "kube-controller-manager"}},
+  "spec": {"containers": [{"name": "kube-controller-manager", "command": ["kube-controller-manager", "--cloud-provider=aws"]}]}
+}]}`,
+	})
+
+	info, err := DetectCloudControllerManager(b)
+	require.NoError(t, err)
+	assert.Nil(t, info)
+}
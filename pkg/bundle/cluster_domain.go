@@ -0,0 +1,71 @@
+package bundle
+
+import (
+	"bufio"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// DefaultClusterDomain is the cluster DNS domain used by kubelet and
+	// CoreDNS unless configured otherwise.
+	DefaultClusterDomain = "cluster.local"
+
+	kubeletConfigMapName = "kubelet-config"
+	kubeletConfigKey     = "kubelet"
+)
+
+// DetectClusterDomain returns the cluster DNS domain from the `clusterDomain`
+// field of the kubelet configuration stored by kubeadm in the `kubelet-config`
+// ConfigMap or from the zone of the `kubernetes` plugin in the CoreDNS
+// Corefile. When neither is present in the bundle, DefaultClusterDomain is
+// returned and defaulted is true.
+func DetectClusterDomain(b Bundle) (domain string, defaulted bool, err error) {
+	cm, err := loadConfigMap(b, "kube-system", kubeletConfigMapName)
+	if err != nil {
+		return "", false, err
+	}
+	if cm != nil {
+		config := struct {
+			ClusterDomain string `json:"clusterDomain"`
+		}{}
+		if err := yaml.Unmarshal([]byte(cm.Data[kubeletConfigKey]), &config); err == nil && config.ClusterDomain != "" {
+			return strings.TrimSuffix(config.ClusterDomain, "."), false, nil
+		}
+	}
+
+	corefile, err := DetectCoreDNSConfig(b)
+	if err != nil {
+		return "", false, err
+	}
+	if zone := corefileKubernetesZone(corefile); zone != "" {
+		return zone, false, nil
+	}
+
+	return DefaultClusterDomain, true, nil
+}
+
+// corefileKubernetesZone returns the first forward zone of the `kubernetes`
+// plugin, e.g. `cluster.local` for `kubernetes cluster.local in-addr.arpa {`.
+// Reverse zones are skipped.
+func corefileKubernetesZone(corefile string) string {
+	scanner := bufio.NewScanner(strings.NewReader(corefile))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] != "kubernetes" {
+			continue
+		}
+		for _, zone := range fields[1:] {
+			if zone == "{" || strings.HasPrefix(zone, "#") {
+				break
+			}
+			zone = strings.TrimSuffix(zone, ".")
+			if strings.HasSuffix(zone, "in-addr.arpa") || strings.HasSuffix(zone, "ip6.arpa") {
+				continue
+			}
+			return zone
+		}
+	}
+	return ""
+}
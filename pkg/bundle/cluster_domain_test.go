@@ -0,0 +1,48 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectClusterDomain_KubeletConfig(t *testing.T) {
+	kubeletConfig := "apiVersion: kubelet.config.k8s.io/v1beta1\nkind: KubeletConfiguration\nclusterDomain: corp.internal\n"
+	b := newTestBundle(t, map[string]string{
+		"configmaps/kube-system/kubelet-config.json": `{"name": "kubelet-config", "namespace": "kube-system", ` +
+			`"data": {"kubelet": ` + jsonString(t, kubeletConfig) + `}}`,
+		"configmaps/kube-system/coredns.json": `{"name": "coredns", "namespace": "kube-system", "data": {"Corefile": ` +
+			jsonString(t, corefile) + `}}`,
+	})
+
+	domain, defaulted, err := DetectClusterDomain(b)
+	require.NoError(t, err)
+	assert.Equal(t, "corp.internal", domain)
+	assert.False(t, defaulted)
+}
+
+func TestDetectClusterDomain_Corefile(t *testing.T) {
+	corefile := ".:53 {\n    kubernetes in-addr.arpa example.test. ip6.arpa {\n       pods insecure\n    }\n}\n"
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/configmaps/kube-system.json": `{"items": [
+			{"metadata": {"name": "coredns"}, "data": {"Corefile": ` + jsonString(t, corefile) + `}}
+		]}`,
+	})
+
+	domain, defaulted, err := DetectClusterDomain(b)
+	require.NoError(t, err)
+	assert.Equal(t, "example.test", domain)
+	assert.False(t, defaulted)
+}
+
+func TestDetectClusterDomain_Default(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/pods/default.json": `{"items": []}`,
+	})
+
+	domain, defaulted, err := DetectClusterDomain(b)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultClusterDomain, domain)
+	assert.True(t, defaulted)
+}
@@ -0,0 +1,163 @@
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// clusterInfoDumpNodesFile is the file with cluster scoped nodes in the
+// `kubectl cluster-info dump --output-directory` output.
+const clusterInfoDumpNodesFile = "nodes.json"
+
+// clusterInfoDumpResource describes a per namespace file from the dump.
+type clusterInfoDumpResource struct {
+	// dir is the directory under cluster resources, where troubleshoot
+	// stores the same resources.
+	dir string
+	gvk schema.GroupVersionKind
+}
+
+// clusterInfoDumpResources maps per namespace files of the dump, stored as
+// `<namespace>/<file>`, to the cluster resources layout.
+func clusterInfoDumpResources() map[string]clusterInfoDumpResource {
+	return map[string]clusterInfoDumpResource{
+		"events.json":                  {"events", schema.GroupVersionKind{Version: "v1", Kind: "Event"}},
+		"services.json":                {"services", schema.GroupVersionKind{Version: "v1", Kind: "Service"}},
+		"pods.json":                    {"pods", schema.GroupVersionKind{Version: "v1", Kind: "Pod"}},
+		"replication-controllers.json": {"replicationcontrollers", schema.GroupVersionKind{Version: "v1", Kind: "ReplicationController"}},
+		"daemonsets.json":              {"daemonsets", schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "DaemonSet"}},
+		"deployments.json":             {"deployments", schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}},
+		"replicasets.json":             {"replicasets", schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"}},
+	}
+}
+
+// IsClusterInfoDump checks if the filesystem contains output of
+// `kubectl cluster-info dump --output-directory` instead of a support bundle.
+func IsClusterInfoDump(fs afero.Fs) bool {
+	hasNodes, _ := afero.Exists(fs, clusterInfoDumpNodesFile)
+	hasClusterResources, _ := afero.DirExists(fs, defaultLayout{}.ClusterResources())
+	return hasNodes && !hasClusterResources
+}
+
+// FromClusterInfoDump creates bundle from the `kubectl cluster-info dump`
+// output. The dump stores resources per namespace, e.g. `default/pods.json`,
+// and container logs as `<namespace>/<pod>/<container>/logs.txt`. The files
+// are converted in memory to the default bundle layout, namespaces are created
+// from the dumped namespace directories and kind is added to items that were
+// dumped without it.
+func FromClusterInfoDump(dump afero.Fs) (Bundle, error) {
+	fs := afero.NewMemMapFs()
+	l := defaultLayout{}
+
+	nodesPath := filepath.Join(l.ClusterResources(), "nodes.json")
+	if err := convertClusterInfoDumpList(dump, fs, clusterInfoDumpNodesFile, nodesPath,
+		schema.GroupVersionKind{Version: "v1", Kind: "Node"}); err != nil {
+		return nil, err
+	}
+
+	entries, err := afero.ReadDir(dump, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := []string{}
+	for _, entry := range entries {
+		// Hidden directories, e.g. with the layout config, are not namespaces.
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		namespace := entry.Name()
+		namespaces = append(namespaces, namespace)
+
+		for file, resource := range clusterInfoDumpResources() {
+			src := filepath.Join(namespace, file)
+			if ok, _ := afero.Exists(dump, src); !ok {
+				continue
+			}
+			dst := filepath.Join(l.ClusterResources(), resource.dir, namespace+".json")
+			if err := convertClusterInfoDumpList(dump, fs, src, dst, resource.gvk); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := convertClusterInfoDumpLogs(dump, fs, namespace, l.PodLogs()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writeClusterInfoDumpNamespaces(fs, filepath.Join(l.ClusterResources(), "namespaces.json"), namespaces); err != nil {
+		return nil, err
+	}
+
+	return FromFsWithLayout(afero.NewReadOnlyFs(fs), l), nil
+}
+
+func convertClusterInfoDumpList(dump, fs afero.Fs, src, dst string, gvk schema.GroupVersionKind) error {
+	list, err := LoadResourcesFromFile(dump, src)
+	if err != nil {
+		return fmt.Errorf("failed to load resources from %q: %w", src, err)
+	}
+
+	for i := range list.Items {
+		if list.Items[i].GetKind() == "" {
+			list.Items[i].SetGroupVersionKind(gvk)
+		}
+	}
+
+	return writeList(fs, dst, list)
+}
+
+// convertClusterInfoDumpLogs copies logs stored in the dump per container to
+// the pod logs directory.
+func convertClusterInfoDumpLogs(dump, fs afero.Fs, namespace, podLogs string) error {
+	logs, err := afero.Glob(dump, filepath.Join(namespace, "*", "*", "logs.txt"))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range logs {
+		parts := strings.Split(filepath.ToSlash(path), "/")
+		pod, container := parts[1], parts[2]
+
+		data, err := afero.ReadFile(dump, path)
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(podLogs, namespace, fmt.Sprintf("%s-%s.log", pod, container))
+		if err := afero.WriteFile(fs, dst, data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeClusterInfoDumpNamespaces(fs afero.Fs, path string, namespaces []string) error {
+	sort.Strings(namespaces)
+
+	list := &unstructured.UnstructuredList{}
+	for _, name := range namespaces {
+		ns := unstructured.Unstructured{}
+		ns.SetGroupVersionKind(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"})
+		ns.SetName(name)
+		list.Items = append(list.Items, ns)
+	}
+	return writeList(fs, path, list)
+}
+
+func writeList(fs afero.Fs, path string, list *unstructured.UnstructuredList) error {
+	list.SetAPIVersion("v1")
+	list.SetKind("List")
+
+	data, err := json.Marshal(list)
+	if err != nil {
+		return fmt.Errorf("failed to serialize %q: %w", path, err)
+	}
+	return afero.WriteFile(fs, path, data, 0o644)
+}
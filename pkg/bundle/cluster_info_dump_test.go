@@ -0,0 +1,95 @@
+package bundle
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clusterInfoDumpFiles is modeled on `kubectl cluster-info dump --output-directory`.
+func clusterInfoDumpFiles() map[string]string {
+	return map[string]string{
+		"nodes.json": `{"kind": "NodeList", "apiVersion": "v1", "metadata": {"resourceVersion": "1"}, "items": [
+			{"metadata": {"name": "node-1"}, "status": {"nodeInfo": {"kubeletVersion": "v1.28.2"}}}
+		]}`,
+		"kube-system/pods.json": `{"kind": "PodList", "apiVersion": "v1", "metadata": {}, "items": [
+			{"metadata": {"name": "coredns-1", "namespace": "kube-system"}, "status": {"phase": "Running"}}
+		]}`,
+		"kube-system/deployments.json": `{"kind": "DeploymentList", "apiVersion": "apps/v1", "metadata": {}, "items": [
+			{"metadata": {"name": "coredns", "namespace": "kube-system"}}
+		]}`,
+		"kube-system/events.json":                  `{"kind": "EventList", "apiVersion": "v1", "metadata": {}, "items": []}`,
+		"kube-system/replication-controllers.json": `{"kind": "ReplicationControllerList", "apiVersion": "v1", "metadata": {}, "items": []}`,
+		"kube-system/coredns-1/coredns/logs.txt":   "[INFO] plugin/reload: Running configuration",
+		"default/services.json": `{"kind": "ServiceList", "apiVersion": "v1", "metadata": {}, "items": [
+			{"metadata": {"name": "kubernetes", "namespace": "default"}}
+		]}`,
+	}
+}
+
+func TestIsClusterInfoDump(t *testing.T) {
+	assert.True(t, IsClusterInfoDump(newTestBundle(t, clusterInfoDumpFiles())))
+	assert.False(t, IsClusterInfoDump(newTestBundle(t, map[string]string{
+		"cluster-resources/nodes.json": `{"items": []}`,
+	})))
+}
+
+func TestFromClusterInfoDump(t *testing.T) {
+	b, err := FromClusterInfoDump(newTestBundle(t, clusterInfoDumpFiles()))
+	require.NoError(t, err)
+
+	nodes, err := ListNodeInfo(b)
+	require.NoError(t, err)
+	require.Len(t, nodes, 1)
+	assert.Equal(t, "v1.28.2", nodes[0].KubeletVersion)
+
+	pods, err := LoadResourcesFromFile(b, "cluster-resources/pods/kube-system.json")
+	require.NoError(t, err)
+	require.Len(t, pods.Items, 1)
+	assert.Equal(t, "Pod", pods.Items[0].GetKind(), "kind is added to dumped items")
+
+	deployments, err := LoadResourcesFromFile(b, "cluster-resources/deployments/kube-system.json")
+	require.NoError(t, err)
+	assert.Equal(t, "apps/v1", deployments.Items[0].GetAPIVersion())
+
+	namespaces, err := LoadResourcesFromFile(b, "cluster-resources/namespaces.json")
+	require.NoError(t, err)
+	require.Len(t, namespaces.Items, 2)
+	assert.Equal(t, "default", namespaces.Items[0].GetName())
+	assert.Equal(t, "Namespace", namespaces.Items[0].GetKind())
+
+	logs, err := afero.ReadFile(b, "pod-logs/kube-system/coredns-1-coredns.log")
+	require.NoError(t, err)
+	assert.Equal(t, "[INFO] plugin/reload: Running configuration", string(logs))
+
+	summary, err := SummarizeWorkloads(b)
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.Deployments)
+}
+
+func TestNew_ClusterInfoDumpConfig(t *testing.T) {
+	setupHome(t)
+	dir := t.TempDir()
+	for path, data := range clusterInfoDumpFiles() {
+		writeConfig(t, filepath.Join(dir, path), data)
+	}
+	writeConfig(t, filepath.Join(dir, "."+ConfigDirName, ConfigFileName), strings.Join([]string{
+		"clusterResources: resources",
+		"skipResources: [events]",
+		"remoteLogURLTemplate: https://logs.example.com/{namespace}/{pod}/{container}",
+	}, "\n"))
+
+	b, err := New(dir)
+	require.NoError(t, err)
+	assert.Equal(t, defaultLayout{}.ClusterResources(), b.Layout().ClusterResources(), "dump is converted to the default paths")
+	assert.Equal(t, []string{"events"}, b.Layout().SkipResources())
+	assert.Equal(t, "https://logs.example.com/default/app/app", RemoteLogURL(b.Layout(), "default", "app", "app"))
+
+	list, err := LoadResources(b, "cluster-resources/namespaces")
+	require.NoError(t, err)
+	assert.Len(t, list.Items, 2, "the config directory is not a namespace")
+}
@@ -0,0 +1,91 @@
+package bundle
+
+import (
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// clusterNameNodeKeys are node labels and annotations with the cluster name
+// set by cluster provisioners.
+func clusterNameNodeKeys() []string {
+	return []string{
+		// Cluster API
+		"cluster.x-k8s.io/cluster-name",
+		// eksctl
+		"alpha.eksctl.io/cluster-name",
+	}
+}
+
+// DetectClusterName returns name of the cluster from which was the bundle
+// collected. The name is read from the kubeadm `ClusterConfiguration` or from
+// node labels and annotations set by the cluster provisioner. Empty string is
+// returned when the name cannot be determined.
+func DetectClusterName(b Bundle) (string, error) {
+	cm, err := loadConfigMap(b, "kube-system", "kubeadm-config")
+	if err != nil {
+		return "", err
+	}
+	if cm != nil {
+		cfg := struct {
+			ClusterName string `json:"clusterName"`
+		}{}
+		if err := yaml.Unmarshal([]byte(cm.Data["ClusterConfiguration"]), &cfg); err == nil && cfg.ClusterName != "" {
+			return cfg.ClusterName, nil
+		}
+	}
+
+	nodes, err := loadNodes(b)
+	if isNotCollected(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	for i := range nodes {
+		if name := clusterNameFromNode(&nodes[i]); name != "" {
+			return name, nil
+		}
+	}
+	return "", nil
+}
+
+func clusterNameFromNode(node *corev1.Node) string {
+	for _, key := range clusterNameNodeKeys() {
+		if name := node.Labels[key]; name != "" {
+			return name
+		}
+		if name := node.Annotations[key]; name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// DetectClusterVersion returns the k8s version of the cluster from which was
+// the bundle collected, e.g. `v1.27.3`. Empty string is returned when
+// the version wasn't collected.
+func DetectClusterVersion(b Bundle) (string, error) {
+	data, _, err := OpenResource(b, filepath.Join(b.Layout().ClusterInfo(), "cluster_version"))
+	if isNotCollected(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	info := struct {
+		Info struct {
+			GitVersion string `json:"gitVersion"`
+		} `json:"info"`
+		VersionString string `json:"string"`
+	}{}
+	if err := yaml.Unmarshal(data, &info); err != nil {
+		return "", err
+	}
+	if info.VersionString != "" {
+		return info.VersionString, nil
+	}
+	return info.Info.GitVersion, nil
+}
@@ -0,0 +1,46 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectClusterName(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"configmaps/kube-system/kubeadm-config.json": `{"name": "kubeadm-config", "namespace": "kube-system", ` +
+			`"data": {"ClusterConfiguration": "kind: ClusterConfiguration\nclusterName: prod-eu\n"}}`,
+	})
+	name, err := DetectClusterName(b)
+	require.NoError(t, err)
+	assert.Equal(t, "prod-eu", name)
+}
+
+func TestDetectClusterName_NodeAnnotation(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/nodes.json": `{"items": [
+  {"metadata": {"name": "cp-0", "annotations": {"cluster.x-k8s.io/cluster-name": "capi-workload"}}}
+]}`,
+	})
+	name, err := DetectClusterName(b)
+	require.NoError(t, err)
+	assert.Equal(t, "capi-workload", name)
+
+	name, err = DetectClusterName(newTestBundle(t, map[string]string{}))
+	require.NoError(t, err)
+	assert.Empty(t, name)
+}
+
+func TestDetectClusterVersion(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-info/cluster_version.json": `{"info": {"gitVersion": "v1.27.3"}}`,
+	})
+	version, err := DetectClusterVersion(b)
+	require.NoError(t, err)
+	assert.Equal(t, "v1.27.3", version)
+
+	version, err = DetectClusterVersion(newTestBundle(t, map[string]string{}))
+	require.NoError(t, err)
+	assert.Empty(t, version)
+}
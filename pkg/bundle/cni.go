@@ -0,0 +1,103 @@
+package bundle
+
+import (
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// CNICalico identifies Calico CNI.
+	CNICalico = "calico"
+	// CNICilium identifies Cilium CNI.
+	CNICilium = "cilium"
+)
+
+// CNISettings contains network settings of a CNI plugin.
+type CNISettings struct {
+	// MTU of the pod network, zero value means that MTU is auto-detected by
+	// the CNI or wasn't configured.
+	MTU int `json:"mtu,omitempty"`
+	// Backend is the data plane mode, e.g. `bird` or `vxlan` for Calico and
+	// `vxlan`, `geneve` or `native` for Cilium.
+	Backend string `json:"backend,omitempty"`
+}
+
+// CNIConfig contains settings of the detected CNI plugins.
+type CNIConfig struct {
+	// ByCNI contains settings indexed by CNI, e.g. CNICalico.
+	ByCNI map[string]CNISettings `json:"byCNI"`
+}
+
+// cniConfigMap describes where a CNI stores its configuration.
+type cniConfigMap struct {
+	name  string
+	parse func(*corev1.ConfigMap) CNISettings
+}
+
+func knownCNIConfigMaps() map[string]cniConfigMap {
+	return map[string]cniConfigMap{
+		CNICalico: {name: "calico-config", parse: parseCalicoConfig},
+		CNICilium: {name: "cilium-config", parse: parseCiliumConfig},
+	}
+}
+
+// DetectCNIConfig reads MTU and backend settings from the configuration of
+// known CNI plugins stored in ConfigMaps in the `kube-system` namespace. Nil is
+// returned when the bundle doesn't contain configuration of any known CNI.
+func DetectCNIConfig(b Bundle) (*CNIConfig, error) {
+	cfg := &CNIConfig{ByCNI: map[string]CNISettings{}}
+	for cni, source := range knownCNIConfigMaps() {
+		cm, err := loadConfigMap(b, "kube-system", source.name)
+		if err != nil {
+			return nil, err
+		}
+		if cm != nil {
+			cfg.ByCNI[cni] = source.parse(cm)
+		}
+	}
+
+	if len(cfg.ByCNI) == 0 {
+		return nil, nil
+	}
+	return cfg, nil
+}
+
+func parseCalicoConfig(cm *corev1.ConfigMap) CNISettings {
+	return CNISettings{
+		MTU:     parseMTU(cm.Data["veth_mtu"]),
+		Backend: cm.Data["calico_backend"],
+	}
+}
+
+func parseCiliumConfig(cm *corev1.ConfigMap) CNISettings {
+	settings := CNISettings{MTU: parseMTU(cm.Data["mtu"])}
+
+	// Cilium 1.14 replaced the `tunnel` option with `routing-mode` and
+	// `tunnel-protocol`.
+	switch cm.Data["routing-mode"] {
+	case "native":
+		settings.Backend = "native"
+	case "tunnel":
+		settings.Backend = cm.Data["tunnel-protocol"]
+		if settings.Backend == "" {
+			settings.Backend = "vxlan"
+		}
+	default:
+		settings.Backend = cm.Data["tunnel"]
+		if settings.Backend == "disabled" {
+			settings.Backend = "native"
+		}
+	}
+	return settings
+}
+
+// parseMTU returns MTU from the config value, invalid values are reported as
+// not configured.
+func parseMTU(value string) int {
+	mtu, err := strconv.Atoi(value)
+	if err != nil {
+		return 0
+	}
+	return mtu
+}
@@ -0,0 +1,55 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectCNIConfig_Calico(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"configmaps/kube-system/calico-config.json": `{
+			"name": "calico-config", "namespace": "kube-system",
+			"data": {"veth_mtu": "1440", "calico_backend": "bird", "typha_service_name": "none"}
+		}`,
+	})
+
+	cfg, err := DetectCNIConfig(b)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+	assert.Equal(t, map[string]CNISettings{CNICalico: {MTU: 1440, Backend: "bird"}}, cfg.ByCNI)
+}
+
+func TestDetectCNIConfig_Cilium(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/configmaps/kube-system.json": `{"items": [{
+			"metadata": {"name": "cilium-config", "namespace": "kube-system"},
+			"data": {"routing-mode": "tunnel", "tunnel-protocol": "geneve", "mtu": "auto"}
+		}]}`,
+	})
+
+	cfg, err := DetectCNIConfig(b)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+	assert.Equal(t, map[string]CNISettings{CNICilium: {Backend: "geneve"}}, cfg.ByCNI)
+}
+
+func TestDetectCNIConfig_CiliumLegacyTunnel(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"configmaps/kube-system/cilium-config.json": `{
+			"name": "cilium-config", "namespace": "kube-system",
+			"data": {"tunnel": "disabled", "mtu": "9000"}
+		}`,
+	})
+
+	cfg, err := DetectCNIConfig(b)
+	require.NoError(t, err)
+	assert.Equal(t, CNISettings{MTU: 9000, Backend: "native"}, cfg.ByCNI[CNICilium])
+}
+
+func TestDetectCNIConfig_Unknown(t *testing.T) {
+	cfg, err := DetectCNIConfig(newTestBundle(t, nil))
+	require.NoError(t, err)
+	assert.Nil(t, cfg)
+}
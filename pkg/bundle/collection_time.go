@@ -0,0 +1,76 @@
+package bundle
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/afero"
+	"sigs.k8s.io/yaml"
+)
+
+// collectionMetadataFiles are files written by troubleshoot at the bundle root
+// that describe the bundle collection.
+func collectionMetadataFiles() []string {
+	return []string{"version.yaml", "version.txt"}
+}
+
+// collectionMetadata is a subset of the bundle metadata file with fields that
+// could contain the collection time.
+type collectionMetadata struct {
+	CollectedAt string `json:"collectedAt"`
+	Metadata    struct {
+		CreationTimestamp string `json:"creationTimestamp"`
+	} `json:"metadata"`
+	Spec struct {
+		CollectedAt string `json:"collectedAt"`
+	} `json:"spec"`
+}
+
+// DetectCollectionTime returns the time when the bundle was collected. The time
+// is read from the bundle metadata file, when it contains a timestamp, or
+// the newest modification time of files that are written by every collection
+// is used. Zero time is returned when the time cannot be determined.
+func DetectCollectionTime(b Bundle) (time.Time, error) {
+	for _, name := range collectionMetadataFiles() {
+		data, err := afero.ReadFile(b, name)
+		if isNotCollected(err) {
+			continue
+		}
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		metadata := collectionMetadata{}
+		if err := yaml.Unmarshal(data, &metadata); err != nil {
+			continue
+		}
+		for _, value := range []string{metadata.CollectedAt, metadata.Spec.CollectedAt, metadata.Metadata.CreationTimestamp} {
+			if t, err := time.Parse(time.RFC3339, value); err == nil {
+				return t, nil
+			}
+		}
+	}
+
+	return newestModTime(b, append(collectionMetadataFiles(),
+		filepath.Join(b.Layout().ClusterInfo(), "cluster_version.json"),
+		filepath.Join(b.Layout().ClusterResources(), "namespaces.json"),
+		filepath.Join(b.Layout().ClusterResources(), "nodes.json"),
+	))
+}
+
+func newestModTime(b Bundle, paths []string) (time.Time, error) {
+	newest := time.Time{}
+	for _, path := range paths {
+		fi, err := b.Stat(path)
+		if isNotCollected(err) {
+			continue
+		}
+		if err != nil {
+			return time.Time{}, err
+		}
+		if fi.ModTime().After(newest) {
+			newest = fi.ModTime()
+		}
+	}
+	return newest, nil
+}
@@ -0,0 +1,45 @@
+package bundle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectCollectionTime_Metadata(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"version.yaml": `apiVersion: troubleshoot.sh/v1beta2
+kind: SupportBundle
+metadata:
+  creationTimestamp: "2024-03-05T10:15:30Z"
+spec:
+  versionNumber: 0.85.0
+`,
+	})
+
+	collected, err := DetectCollectionTime(b)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 5, 10, 15, 30, 0, time.UTC), collected.UTC())
+}
+
+func TestDetectCollectionTime_ModTime(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"version.yaml":                 "kind: SupportBundle\nspec:\n  versionNumber: 0.85.0\n",
+		"cluster-resources/nodes.json": `{"items": []}`,
+	})
+	newest := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, b.Chtimes("version.yaml", newest.Add(-time.Hour), newest.Add(-time.Hour)))
+	require.NoError(t, b.Chtimes("cluster-resources/nodes.json", newest, newest))
+
+	collected, err := DetectCollectionTime(b)
+	require.NoError(t, err)
+	assert.True(t, newest.Equal(collected))
+}
+
+func TestDetectCollectionTime_Unknown(t *testing.T) {
+	collected, err := DetectCollectionTime(newTestBundle(t, nil))
+	require.NoError(t, err)
+	assert.True(t, collected.IsZero())
+}
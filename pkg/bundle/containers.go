@@ -0,0 +1,53 @@
+package bundle
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ContainerView joins a container from the pod spec with its status.
+type ContainerView struct {
+	Name  string `json:"name"`
+	Image string `json:"image"`
+	// Init is true for init containers.
+	Init bool `json:"init"`
+	// Reported is false when the pod status doesn't contain status of
+	// the container, e.g. for pods that were not scheduled yet. The status
+	// fields are empty in that case.
+	Reported     bool                  `json:"reported"`
+	ImageID      string                `json:"imageID,omitempty"`
+	Ready        bool                  `json:"ready"`
+	RestartCount int32                 `json:"restartCount"`
+	State        corev1.ContainerState `json:"state"`
+	LastState    corev1.ContainerState `json:"lastState"`
+}
+
+// PodContainerViews returns init containers followed by containers from
+// the pod spec joined with the matching statuses.
+func PodContainerViews(pod *corev1.Pod) []ContainerView {
+	views := make([]ContainerView, 0, len(pod.Spec.InitContainers)+len(pod.Spec.Containers))
+	views = appendContainerViews(views, pod.Spec.InitContainers, pod.Status.InitContainerStatuses, true)
+	return appendContainerViews(views, pod.Spec.Containers, pod.Status.ContainerStatuses, false)
+}
+
+func appendContainerViews(
+	views []ContainerView, containers []corev1.Container, statuses []corev1.ContainerStatus, init bool,
+) []ContainerView {
+	byName := make(map[string]*corev1.ContainerStatus, len(statuses))
+	for i := range statuses {
+		byName[statuses[i].Name] = &statuses[i]
+	}
+
+	for i := range containers {
+		view := ContainerView{Name: containers[i].Name, Image: containers[i].Image, Init: init}
+		if status, ok := byName[view.Name]; ok {
+			view.Reported = true
+			view.ImageID = status.ImageID
+			view.Ready = status.Ready
+			view.RestartCount = status.RestartCount
+			view.State = status.State
+			view.LastState = status.LastTerminationState
+		}
+		views = append(views, view)
+	}
+	return views
+}
@@ -0,0 +1,53 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestPodContainerViews(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "migrate", Image: "app:1.0"}},
+			Containers: []corev1.Container{
+				{Name: "app", Image: "app:1.0"},
+				{Name: "sidecar", Image: "proxy:2.3"},
+			},
+		},
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "migrate",
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Completed"}},
+			}},
+			// The sidecar status is not reported.
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "app",
+				ImageID:      "docker.io/library/app@sha256:abc",
+				Ready:        true,
+				RestartCount: 2,
+				State:        corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				LastTerminationState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137},
+				},
+			}},
+		},
+	}
+
+	assert.Equal(t, []ContainerView{
+		{
+			Name: "migrate", Image: "app:1.0", Init: true, Reported: true,
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Completed"}},
+		},
+		{
+			Name: "app", Image: "app:1.0", Reported: true, ImageID: "docker.io/library/app@sha256:abc",
+			Ready: true, RestartCount: 2,
+			State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			LastState: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137},
+			},
+		},
+		{Name: "sidecar", Image: "proxy:2.3"},
+	}, PodContainerViews(pod))
+}
@@ -0,0 +1,69 @@
+package bundle
+
+import (
+	"strconv"
+	"strings"
+)
+
+const controllerManagerComponent = "kube-controller-manager"
+
+// DetectControllerManagerFlags returns all flags of the kube-controller-manager
+// static pod. Flags passed as `--flag value` are supported and repeated flags
+// contain the last value. Nil is returned when the bundle doesn't contain
+// the controller manager pod, e.g. in managed clusters.
+func DetectControllerManagerFlags(b Bundle) (map[string]string, error) {
+	flags, err := controlPlaneFlags(b, controllerManagerComponent)
+	if isNotCollected(err) {
+		return nil, nil
+	}
+	return flags, err
+}
+
+// PodNetworkConfig contains pod network settings of the kube-controller-manager.
+type PodNetworkConfig struct {
+	// ClusterCIDRs are the pod CIDRs from the `--cluster-cidr` flag, two for
+	// dual-stack clusters.
+	ClusterCIDRs []string `json:"clusterCIDRs,omitempty"`
+	// AllocateNodeCIDRs is set when the controller manager allocates pod
+	// CIDRs of nodes.
+	AllocateNodeCIDRs bool `json:"allocateNodeCIDRs"`
+	// NodeCIDRMaskSizeIPv4 and NodeCIDRMaskSizeIPv6 are prefix lengths of
+	// node pod CIDRs, defaulted to 24 and 64.
+	NodeCIDRMaskSizeIPv4 int `json:"nodeCIDRMaskSizeIPv4"`
+	NodeCIDRMaskSizeIPv6 int `json:"nodeCIDRMaskSizeIPv6"`
+}
+
+// DetectPodNetworkConfig returns pod network settings from the
+// kube-controller-manager flags. Nil is returned when the bundle doesn't
+// contain the controller manager pod.
+func DetectPodNetworkConfig(b Bundle) (*PodNetworkConfig, error) {
+	flags, err := DetectControllerManagerFlags(b)
+	if err != nil || flags == nil {
+		return nil, err
+	}
+
+	cfg := &PodNetworkConfig{
+		AllocateNodeCIDRs:    flags["allocate-node-cidrs"] == "true",
+		NodeCIDRMaskSizeIPv4: 24,
+		NodeCIDRMaskSizeIPv6: 64,
+	}
+	if cidrs := flags["cluster-cidr"]; cidrs != "" {
+		cfg.ClusterCIDRs = strings.Split(cidrs, ",")
+	}
+
+	// The generic flag applies to single-stack clusters.
+	if size, err := strconv.Atoi(flags["node-cidr-mask-size"]); err == nil {
+		if len(cfg.ClusterCIDRs) == 1 && strings.Contains(cfg.ClusterCIDRs[0], ":") {
+			cfg.NodeCIDRMaskSizeIPv6 = size
+		} else {
+			cfg.NodeCIDRMaskSizeIPv4 = size
+		}
+	}
+	if size, err := strconv.Atoi(flags["node-cidr-mask-size-ipv4"]); err == nil {
+		cfg.NodeCIDRMaskSizeIPv4 = size
+	}
+	if size, err := strconv.Atoi(flags["node-cidr-mask-size-ipv6"]); err == nil {
+		cfg.NodeCIDRMaskSizeIPv6 = size
+	}
+	return cfg, nil
+}
@@ -0,0 +1,63 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const controllerManagerPods = `{"items": [{
+  "metadata": {"name": "kube-controller-manager-cp-1", "namespace": "kube-system", "labels": {"component": "kube-controller-manager"}},
+  "spec": {"containers": [{
+    "name": "kube-controller-manager",
+    "command": [
+      "kube-controller-manager",
+      "--allocate-node-cidrs=true",
+      "--cluster-cidr", "10.244.0.0/16,fd00:10:244::/56",
+      "--node-cidr-mask-size-ipv6=80",
+      "--controllers=*,bootstrapsigner",
+      "--controllers=*,bootstrapsigner,tokencleaner",
+      "--profiling",
+      "--v", "2"
+    ]
+  }]}
+}]}`
+
+func TestDetectControllerManagerFlags(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/pods/kube-system.json": controllerManagerPods,
+	})
+
+	flags, err := DetectControllerManagerFlags(b)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"allocate-node-cidrs":      "true",
+		"cluster-cidr":             "10.244.0.0/16,fd00:10:244::/56",
+		"node-cidr-mask-size-ipv6": "80",
+		"controllers":              "*,bootstrapsigner,tokencleaner",
+		"profiling":                "true",
+		"v":                        "2",
+	}, flags)
+
+	cfg, err := DetectPodNetworkConfig(b)
+	require.NoError(t, err)
+	assert.Equal(t, &PodNetworkConfig{
+		ClusterCIDRs:         []string{"10.244.0.0/16", "fd00:10:244::/56"},
+		AllocateNodeCIDRs:    true,
+		NodeCIDRMaskSizeIPv4: 24,
+		NodeCIDRMaskSizeIPv6: 80,
+	}, cfg)
+}
+
+func TestDetectControllerManagerFlags_NotPresent(t *testing.T) {
+	flags, err := DetectControllerManagerFlags(newTestBundle(t, map[string]string{}))
+	require.NoError(t, err)
+	assert.Nil(t, flags)
+
+	cfg, err := DetectPodNetworkConfig(newTestBundle(t, map[string]string{
+		"cluster-resources/pods/kube-system.json": `{"items": []}`,
+	}))
+	require.NoError(t, err)
+	assert.Nil(t, cfg)
+}
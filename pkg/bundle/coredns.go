@@ -0,0 +1,62 @@
+package bundle
+
+import (
+	"path/filepath"
+
+	"github.com/spf13/afero"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	coreDNSConfigMapName = "coredns"
+	coreDNSCorefileKey   = "Corefile"
+)
+
+// DetectCoreDNSConfig returns the CoreDNS Corefile from the `coredns` ConfigMap
+// in the `kube-system` namespace. Empty value is returned when the ConfigMap
+// wasn't collected or the cluster doesn't use CoreDNS.
+func DetectCoreDNSConfig(b Bundle) (string, error) {
+	cm, err := loadConfigMap(b, "kube-system", coreDNSConfigMapName)
+	if err != nil || cm == nil {
+		return "", err
+	}
+	return cm.Data[coreDNSCorefileKey], nil
+}
+
+// loadConfigMap loads ConfigMap collected either by the configmap collector or
+// by the cluster resources collector. Nil is returned when the ConfigMap is not
+// present in the bundle.
+func loadConfigMap(b Bundle, namespace, name string) (*corev1.ConfigMap, error) {
+	path := filepath.Join(b.Layout().ConfigMaps(), namespace, name+".json")
+	if exists, _ := afero.Exists(b, path); exists {
+		u, err := LoadConfigMap(b, path)
+		if err != nil {
+			return nil, err
+		}
+		cm := &corev1.ConfigMap{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, cm); err != nil {
+			return nil, err
+		}
+		return cm, nil
+	}
+
+	list, err := loadClusterResources(b, filepath.Join("configmaps", namespace))
+	if isNotCollected(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cms, err := convertList[corev1.ConfigMap](list)
+	if err != nil {
+		return nil, err
+	}
+	for i := range cms {
+		if cms[i].GetName() == name {
+			return &cms[i], nil
+		}
+	}
+	return nil, nil
+}
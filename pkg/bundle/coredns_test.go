@@ -0,0 +1,50 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const corefile = `.:53 {
+    errors
+    health
+    kubernetes cluster.local in-addr.arpa ip6.arpa {
+       pods insecure
+       fallthrough in-addr.arpa ip6.arpa
+    }
+    forward . /etc/resolv.conf
+    cache 30
+}
+`
+
+func TestDetectCoreDNSConfig(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"configmaps/kube-system/coredns.json": `{"name": "coredns", "namespace": "kube-system", "data": {"Corefile": ` +
+			jsonString(t, corefile) + `}}`,
+	})
+
+	config, err := DetectCoreDNSConfig(b)
+	require.NoError(t, err)
+	assert.Equal(t, corefile, config)
+}
+
+func TestDetectCoreDNSConfig_ClusterResources(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/configmaps/kube-system.json": `{"items": [
+			{"metadata": {"name": "kube-proxy"}, "data": {"config.conf": ""}},
+			{"metadata": {"name": "coredns"}, "data": {"Corefile": ` + jsonString(t, corefile) + `}}
+		]}`,
+	})
+
+	config, err := DetectCoreDNSConfig(b)
+	require.NoError(t, err)
+	assert.Equal(t, corefile, config)
+}
+
+func TestDetectCoreDNSConfig_Missing(t *testing.T) {
+	config, err := DetectCoreDNSConfig(newTestBundle(t, nil))
+	require.NoError(t, err)
+	assert.Empty(t, config)
+}
@@ -0,0 +1,209 @@
+package bundle
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Deprecation describes a resource stored in the bundle with an apiVersion
+// that is deprecated or removed in the target k8s version.
+type Deprecation struct {
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	APIVersion string `json:"apiVersion"`
+	// Replacement is the apiVersion that should be used instead. Empty when
+	// the API was removed without a replacement.
+	Replacement string `json:"replacement,omitempty"`
+	// Removed is true when the apiVersion isn't served by the target version.
+	Removed   bool   `json:"removed"`
+	RemovedIn string `json:"removedIn"`
+}
+
+// deprecatedAPI is an entry of the deprecation table.
+type deprecatedAPI struct {
+	apiVersion  string
+	kinds       []string
+	replacement string
+	// deprecatedIn and removedIn are `<major>.<minor>` k8s versions.
+	deprecatedIn string
+	removedIn    string
+}
+
+// deprecatedAPIs returns built-in table of deprecated k8s APIs, based on
+// https://kubernetes.io/docs/reference/using-api/deprecation-guide/.
+func deprecatedAPIs() []deprecatedAPI {
+	workloads := []string{"Deployment", "DaemonSet", "ReplicaSet", "StatefulSet"}
+	return []deprecatedAPI{
+		{"extensions/v1beta1", workloads, "apps/v1", "1.9", "1.16"},
+		{"extensions/v1beta1", []string{"NetworkPolicy"}, "networking.k8s.io/v1", "1.9", "1.16"},
+		{"extensions/v1beta1", []string{"PodSecurityPolicy"}, "policy/v1beta1", "1.11", "1.16"},
+		{"extensions/v1beta1", []string{"Ingress"}, "networking.k8s.io/v1", "1.14", "1.22"},
+		{"apps/v1beta1", workloads, "apps/v1", "1.9", "1.16"},
+		{"apps/v1beta2", workloads, "apps/v1", "1.9", "1.16"},
+		{"networking.k8s.io/v1beta1", []string{"Ingress", "IngressClass"}, "networking.k8s.io/v1", "1.19", "1.22"},
+		{"apiextensions.k8s.io/v1beta1", []string{"CustomResourceDefinition"}, "apiextensions.k8s.io/v1", "1.16", "1.22"},
+		{"apiregistration.k8s.io/v1beta1", []string{"APIService"}, "apiregistration.k8s.io/v1", "1.19", "1.22"},
+		{
+			"admissionregistration.k8s.io/v1beta1",
+			[]string{"MutatingWebhookConfiguration", "ValidatingWebhookConfiguration"},
+			"admissionregistration.k8s.io/v1", "1.16", "1.22",
+		},
+		{
+			"rbac.authorization.k8s.io/v1beta1",
+			[]string{"ClusterRole", "ClusterRoleBinding", "Role", "RoleBinding"},
+			"rbac.authorization.k8s.io/v1", "1.17", "1.22",
+		},
+		{"scheduling.k8s.io/v1beta1", []string{"PriorityClass"}, "scheduling.k8s.io/v1", "1.14", "1.22"},
+		{
+			"storage.k8s.io/v1beta1",
+			[]string{"CSIDriver", "CSINode", "StorageClass", "VolumeAttachment"},
+			"storage.k8s.io/v1", "1.19", "1.22",
+		},
+		{"storage.k8s.io/v1beta1", []string{"CSIStorageCapacity"}, "storage.k8s.io/v1", "1.24", "1.27"},
+		{"certificates.k8s.io/v1beta1", []string{"CertificateSigningRequest"}, "certificates.k8s.io/v1", "1.19", "1.22"},
+		{"coordination.k8s.io/v1beta1", []string{"Lease"}, "coordination.k8s.io/v1", "1.19", "1.22"},
+		{"batch/v1beta1", []string{"CronJob"}, "batch/v1", "1.21", "1.25"},
+		{"discovery.k8s.io/v1beta1", []string{"EndpointSlice"}, "discovery.k8s.io/v1", "1.21", "1.25"},
+		{"events.k8s.io/v1beta1", []string{"Event"}, "events.k8s.io/v1", "1.19", "1.25"},
+		{"autoscaling/v2beta1", []string{"HorizontalPodAutoscaler"}, "autoscaling/v2", "1.22", "1.25"},
+		{"autoscaling/v2beta2", []string{"HorizontalPodAutoscaler"}, "autoscaling/v2", "1.23", "1.26"},
+		{"policy/v1beta1", []string{"PodDisruptionBudget"}, "policy/v1", "1.21", "1.25"},
+		// PodSecurityPolicy is replaced by the Pod Security Admission.
+		{"policy/v1beta1", []string{"PodSecurityPolicy"}, "", "1.21", "1.25"},
+		{"node.k8s.io/v1beta1", []string{"RuntimeClass"}, "node.k8s.io/v1", "1.20", "1.25"},
+		{
+			"flowcontrol.apiserver.k8s.io/v1beta1",
+			[]string{"FlowSchema", "PriorityLevelConfiguration"},
+			"flowcontrol.apiserver.k8s.io/v1", "1.23", "1.26",
+		},
+		{
+			"flowcontrol.apiserver.k8s.io/v1beta2",
+			[]string{"FlowSchema", "PriorityLevelConfiguration"},
+			"flowcontrol.apiserver.k8s.io/v1", "1.26", "1.29",
+		},
+		{
+			"flowcontrol.apiserver.k8s.io/v1beta3",
+			[]string{"FlowSchema", "PriorityLevelConfiguration"},
+			"flowcontrol.apiserver.k8s.io/v1", "1.29", "1.32",
+		},
+	}
+}
+
+// DetectDeprecatedAPIs reports resources from cluster resources stored with
+// apiVersion that is deprecated or removed in the target k8s version, e.g.
+// `1.25` or `v1.25.3`. The resources are stored in the bundle with the
+// apiVersion served by the cluster, so the result shows which APIs have to be
+// migrated before an upgrade.
+func DetectDeprecatedAPIs(b Bundle, targetVersion string) ([]Deprecation, error) {
+	target, err := semver.NewVersion(targetVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target version %q: %w", targetVersion, err)
+	}
+
+	items, err := loadAllClusterResources(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Deprecation
+	for i := range items {
+		api, ok := findDeprecatedAPI(items[i].GetAPIVersion(), items[i].GetKind())
+		if !ok || !versionAtLeast(target, api.deprecatedIn) {
+			continue
+		}
+		result = append(result, Deprecation{
+			Namespace:   items[i].GetNamespace(),
+			Name:        items[i].GetName(),
+			Kind:        items[i].GetKind(),
+			APIVersion:  api.apiVersion,
+			Replacement: api.replacement,
+			Removed:     versionAtLeast(target, api.removedIn),
+			RemovedIn:   api.removedIn,
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		x, y := result[i], result[j]
+		if x.APIVersion != y.APIVersion {
+			return x.APIVersion < y.APIVersion
+		}
+		if x.Kind != y.Kind {
+			return x.Kind < y.Kind
+		}
+		if x.Namespace != y.Namespace {
+			return x.Namespace < y.Namespace
+		}
+		return x.Name < y.Name
+	})
+	return result, nil
+}
+
+func findDeprecatedAPI(apiVersion, kind string) (deprecatedAPI, bool) {
+	for _, api := range deprecatedAPIs() {
+		if api.apiVersion != apiVersion {
+			continue
+		}
+		for _, k := range api.kinds {
+			if k == kind {
+				return api, true
+			}
+		}
+	}
+	return deprecatedAPI{}, false
+}
+
+// versionAtLeast checks if the version is same or newer than the
+// `<major>.<minor>` version, ignoring patch and pre-release parts.
+func versionAtLeast(v *semver.Version, minor string) bool {
+	m := semver.MustParse(minor)
+	if v.Major() != m.Major() {
+		return v.Major() > m.Major()
+	}
+	return v.Minor() >= m.Minor()
+}
+
+// loadAllClusterResources loads resources from all files in the cluster
+// resources directory. Files that don't contain resources, e.g. `auth-cani-list`
+// outputs, and files with collection errors are skipped.
+func loadAllClusterResources(b Bundle) ([]unstructured.Unstructured, error) {
+	var items []unstructured.Unstructured
+	seen := map[string]bool{}
+	err := afero.Walk(b, b.Layout().ClusterResources(), func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || strings.Contains(info.Name(), "-errors.") {
+			return nil
+		}
+		// Chunks are loaded together by the name of the chunked file.
+		if base, _, ok := SplitChunkPath(path); ok {
+			path = base
+		}
+		if seen[path] {
+			return nil
+		}
+		seen[path] = true
+
+		list, err := LoadResourcesFromFile(b, path)
+		if err != nil {
+			// Not a resource list.
+			return nil
+		}
+		items = append(items, list.Items...)
+		return nil
+	})
+	if isNotCollected(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cluster resources: %w", err)
+	}
+	return items, nil
+}
@@ -0,0 +1,71 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func deprecatedAPIsBundle(t *testing.T) Bundle {
+	t.Helper()
+	return newTestBundle(t, map[string]string{
+		"cluster-resources/deployments/default.json": `{"items": [
+  {"apiVersion": "extensions/v1beta1", "kind": "Deployment", "metadata": {"name": "legacy", "namespace": "default"}},
+  {"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "current", "namespace": "default"}}
+]}`,
+		"cluster-resources/ingress/web.json": `{"items": [
+  {"apiVersion": "extensions/v1beta1", "kind": "Ingress", "metadata": {"name": "web", "namespace": "web"}}
+]}`,
+		"cluster-resources/cronjobs/batch.json": `{"items": [
+  {"apiVersion": "batch/v1beta1", "kind": "CronJob", "metadata": {"name": "backup", "namespace": "batch"}}
+]}`,
+		"cluster-resources/custom-resource-definitions.json": `{"items": [
+  {"apiVersion": "apiextensions.k8s.io/v1beta1", "kind": "CustomResourceDefinition", "metadata": {"name": "foos.example.com"}}
+]}`,
+		"cluster-resources/pods/default-errors.json":    `["failed to list pods"]`,
+		"cluster-resources/auth-cani-list/default.json": `not a resource list`,
+	})
+}
+
+func TestDetectDeprecatedAPIs(t *testing.T) {
+	b := deprecatedAPIsBundle(t)
+
+	deprecations, err := DetectDeprecatedAPIs(b, "v1.22.4")
+	require.NoError(t, err)
+	assert.Equal(t, []Deprecation{
+		{
+			Name: "foos.example.com", Kind: "CustomResourceDefinition", APIVersion: "apiextensions.k8s.io/v1beta1",
+			Replacement: "apiextensions.k8s.io/v1", Removed: true, RemovedIn: "1.22",
+		},
+		{
+			Namespace: "batch", Name: "backup", Kind: "CronJob", APIVersion: "batch/v1beta1",
+			Replacement: "batch/v1", Removed: false, RemovedIn: "1.25",
+		},
+		{
+			Namespace: "default", Name: "legacy", Kind: "Deployment", APIVersion: "extensions/v1beta1",
+			Replacement: "apps/v1", Removed: true, RemovedIn: "1.16",
+		},
+		{
+			Namespace: "web", Name: "web", Kind: "Ingress", APIVersion: "extensions/v1beta1",
+			Replacement: "networking.k8s.io/v1", Removed: true, RemovedIn: "1.22",
+		},
+	}, deprecations)
+}
+
+func TestDetectDeprecatedAPIs_OlderTarget(t *testing.T) {
+	b := deprecatedAPIsBundle(t)
+
+	deprecations, err := DetectDeprecatedAPIs(b, "1.15")
+	require.NoError(t, err)
+	require.Len(t, deprecations, 2)
+	for _, d := range deprecations {
+		assert.Equal(t, "extensions/v1beta1", d.APIVersion)
+		assert.False(t, d.Removed, d.Name)
+	}
+}
+
+func TestDetectDeprecatedAPIs_InvalidVersion(t *testing.T) {
+	_, err := DetectDeprecatedAPIs(newTestBundle(t, map[string]string{}), "latest")
+	assert.Error(t, err)
+}
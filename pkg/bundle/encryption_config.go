@@ -0,0 +1,124 @@
+package bundle
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+	"sigs.k8s.io/yaml"
+)
+
+// identityEncryptionProvider stores resources without encryption.
+const identityEncryptionProvider = "identity"
+
+// EncryptionInfo describes encryption of resources at rest configured for
+// the kube-apiserver.
+type EncryptionInfo struct {
+	// ConfigPath is the value of the `--encryption-provider-config` flag.
+	ConfigPath string `json:"configPath"`
+	// ConfigFile is the bundle file with the collected config, empty when
+	// the config wasn't collected.
+	ConfigFile string `json:"configFile,omitempty"`
+	// Rules are the encryption rules of the collected config.
+	Rules []EncryptionRule `json:"rules,omitempty"`
+}
+
+// EncryptionRule lists providers used for a group of resources.
+type EncryptionRule struct {
+	Resources []string `json:"resources"`
+	// Providers are names of the providers in the configured order, e.g.
+	// `aescbc` or `kms:<name>` for KMS providers. Keys are not included.
+	Providers []string `json:"providers"`
+	// Encrypted is set when the first provider, which encrypts newly written
+	// resources, is not `identity`.
+	Encrypted bool `json:"encrypted"`
+}
+
+// encryptionConfiguration is a subset of the EncryptionConfiguration of
+// the `apiserver.config.k8s.io` group.
+type encryptionConfiguration struct {
+	Kind      string `json:"kind"`
+	Resources []struct {
+		Resources []string         `json:"resources"`
+		Providers []map[string]any `json:"providers"`
+	} `json:"resources"`
+}
+
+// DetectEncryptionConfig returns the encryption at rest configuration from
+// the `--encryption-provider-config` flag of the kube-apiserver. The config
+// is looked up as a bundle file with path ending with the flag value, e.g.
+// `host-collectors/etc/kubernetes/enc/config.yaml`, collected by a host
+// collector. Nil is returned when the kube-apiserver pod wasn't collected or
+// the encryption isn't configured.
+func DetectEncryptionConfig(b Bundle) (*EncryptionInfo, error) {
+	pod, err := findKubeApiserverPod(b)
+	if isNotCollected(err) {
+		return nil, nil
+	}
+	if err != nil || pod == nil {
+		return nil, err
+	}
+
+	info := &EncryptionInfo{ConfigPath: apiServerFlag(pod, "encryption-provider-config")}
+	if info.ConfigPath == "" {
+		return nil, nil
+	}
+
+	info.ConfigFile, err = findCollectedHostFile(b, info.ConfigPath)
+	if err != nil || info.ConfigFile == "" {
+		return info, err
+	}
+	data, err := afero.ReadFile(b, info.ConfigFile)
+	if err != nil {
+		return nil, err
+	}
+	if info.Rules, err = parseEncryptionConfig(data); err != nil {
+		return nil, fmt.Errorf("failed to parse encryption config %q: %w", info.ConfigFile, err)
+	}
+	return info, nil
+}
+
+// findCollectedHostFile returns the first bundle file with path ending with
+// the host path. Empty string is returned when there isn't any.
+func findCollectedHostFile(b Bundle, hostPath string) (string, error) {
+	suffix := "/" + strings.TrimPrefix(filepath.ToSlash(hostPath), "/")
+	var found string
+	err := afero.Walk(b, ".", func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if found == "" && !info.IsDir() && strings.HasSuffix("/"+filepath.ToSlash(path), suffix) {
+			found = path
+		}
+		return nil
+	})
+	return found, err
+}
+
+func parseEncryptionConfig(data []byte) ([]EncryptionRule, error) {
+	config := encryptionConfiguration{}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	if config.Kind != "EncryptionConfiguration" {
+		return nil, fmt.Errorf("unexpected kind %q", config.Kind)
+	}
+
+	rules := make([]EncryptionRule, 0, len(config.Resources))
+	for _, resource := range config.Resources {
+		rule := EncryptionRule{Resources: resource.Resources, Providers: []string{}}
+		for _, provider := range resource.Providers {
+			for name, settings := range provider {
+				if cfg, ok := settings.(map[string]any); ok && name == "kms" && cfg["name"] != nil {
+					name = fmt.Sprintf("kms:%v", cfg["name"])
+				}
+				rule.Providers = append(rule.Providers, name)
+			}
+		}
+		rule.Encrypted = len(rule.Providers) > 0 && rule.Providers[0] != identityEncryptionProvider
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
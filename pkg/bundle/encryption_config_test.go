@@ -0,0 +1,72 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encryptionTestAPIServerPod(flags string) string {
+	return `{"items": [{
+  "metadata": {"name": "kube-apiserver-cp-1", "labels": {"component": "kube-apiserver"}},
+  "spec": {"containers": [{"name": "kube-apiserver", "command": ["kube-apiserver"` + flags + `]}]}
+}]}`
+}
+
+func TestDetectEncryptionConfig(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/pods/kube-system.json": encryptionTestAPIServerPod(
+			`, "--encryption-provider-config=/etc/kubernetes/enc/config.yaml"`),
+		"host-collectors/copy-from-host/cp-1/etc/kubernetes/enc/config.yaml": `apiVersion: apiserver.config.k8s.io/v1
+kind: EncryptionConfiguration
+resources:
+  - resources: [secrets, configmaps]
+    providers:
+      - kms:
+          apiVersion: v2
+          name: vault
+          endpoint: unix:///var/run/kms.sock
+      - aescbc:
+          keys:
+            - name: key1
+              secret: c2VjcmV0IGlzIHNlY3VyZQ==
+      - identity: {}
+  - resources: [events]
+    providers:
+      - identity: {}
+`,
+	})
+
+	info, err := DetectEncryptionConfig(b)
+	require.NoError(t, err)
+	assert.Equal(t, &EncryptionInfo{
+		ConfigPath: "/etc/kubernetes/enc/config.yaml",
+		ConfigFile: "host-collectors/copy-from-host/cp-1/etc/kubernetes/enc/config.yaml",
+		Rules: []EncryptionRule{
+			{Resources: []string{"secrets", "configmaps"}, Providers: []string{"kms:vault", "aescbc", "identity"}, Encrypted: true},
+			{Resources: []string{"events"}, Providers: []string{"identity"}, Encrypted: false},
+		},
+	}, info)
+}
+
+func TestDetectEncryptionConfig_NotCollected(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/pods/kube-system.json": encryptionTestAPIServerPod(
+			`, "--encryption-provider-config", "/etc/kubernetes/enc/config.yaml"`),
+	})
+
+	info, err := DetectEncryptionConfig(b)
+	require.NoError(t, err)
+	assert.Equal(t, &EncryptionInfo{ConfigPath: "/etc/kubernetes/enc/config.yaml"}, info)
+}
+
+func TestDetectEncryptionConfig_NotConfigured(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/pods/kube-system.json": encryptionTestAPIServerPod(""),
+	})
+
+	info, err := DetectEncryptionConfig(b)
+	require.NoError(t, err)
+	assert.Nil(t, info)
+}
@@ -0,0 +1,73 @@
+package bundle
+
+import (
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// eventTimeFields are fields of `v1` and `events.k8s.io/v1` Events with the
+// time when the event occurred.
+func eventTimeFields() [][]string {
+	return [][]string{
+		{"lastTimestamp"},
+		{"eventTime"},
+		{"series", "lastObservedTime"},
+		{"deprecatedLastTimestamp"},
+		{"firstTimestamp"},
+		{"deprecatedFirstTimestamp"},
+		{"metadata", "creationTimestamp"},
+	}
+}
+
+// EventTime returns the time when the last occurrence of the `v1` or
+// `events.k8s.io/v1` Event was observed. Zero time is returned when the event
+// doesn't contain any timestamp.
+func EventTime(event *unstructured.Unstructured) time.Time {
+	var latest time.Time
+	for _, field := range eventTimeFields() {
+		value, _, _ := unstructured.NestedString(event.Object, field...)
+		if t, err := time.Parse(time.RFC3339Nano, value); err == nil && t.After(latest) {
+			latest = t
+		}
+	}
+	return latest
+}
+
+// LoadAllEvents returns events from all namespaces sorted by the time of
+// the last occurrence, the most recent first. Both `v1` and
+// `events.k8s.io/v1` Events are supported. Empty list is returned when events
+// were not collected.
+func LoadAllEvents(b Bundle) (*unstructured.UnstructuredList, error) {
+	list, err := loadNamespacedResources(b, "events")
+	if isNotCollected(err) {
+		list, err = &unstructured.UnstructuredList{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	type timedEvent struct {
+		event unstructured.Unstructured
+		time  time.Time
+	}
+	events := make([]timedEvent, 0, len(list.Items))
+	for i := range list.Items {
+		if list.Items[i].GetKind() == "" {
+			list.Items[i].SetAPIVersion("v1")
+			list.Items[i].SetKind("Event")
+		}
+		events = append(events, timedEvent{list.Items[i], EventTime(&list.Items[i])})
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].time.After(events[j].time)
+	})
+	for i := range events {
+		list.Items[i] = events[i].event
+	}
+
+	list.SetAPIVersion("v1")
+	list.SetKind("List")
+	return list, nil
+}
@@ -0,0 +1,43 @@
+package bundle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadAllEvents(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/events/default.json": `{"items": [
+  {"metadata": {"name": "web.1", "namespace": "default"}, "reason": "Pulled", "lastTimestamp": "2024-01-01T10:00:00Z"},
+  {
+    "apiVersion": "events.k8s.io/v1", "kind": "Event",
+    "metadata": {"name": "web.2", "namespace": "default"}, "reason": "BackOff",
+    "eventTime": "2024-01-01T10:01:00.000000Z", "series": {"count": 5, "lastObservedTime": "2024-01-01T10:30:00.000000Z"}
+  }
+]}`,
+		"cluster-resources/events/kube-system.json": `{"items": [
+  {"apiVersion": "v1", "kind": "Event", "metadata": {"name": "dns.1", "namespace": "kube-system"}, "reason": "Started", "eventTime": "2024-01-01T10:15:00.123456Z"},
+  {"apiVersion": "events.k8s.io/v1", "kind": "Event", "metadata": {"name": "dns.2", "namespace": "kube-system", "creationTimestamp": "2024-01-01T09:00:00Z"}}
+]}`,
+	})
+
+	list, err := LoadAllEvents(b)
+	require.NoError(t, err)
+
+	names := []string{}
+	for i := range list.Items {
+		names = append(names, list.Items[i].GetName())
+	}
+	assert.Equal(t, []string{"web.2", "dns.1", "web.1", "dns.2"}, names)
+	assert.Equal(t, "Event", list.Items[2].GetKind(), "kind is set for events without GVK")
+	assert.Equal(t, time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC), EventTime(&list.Items[0]))
+}
+
+func TestLoadAllEvents_NotCollected(t *testing.T) {
+	list, err := LoadAllEvents(newTestBundle(t, map[string]string{}))
+	require.NoError(t, err)
+	assert.Empty(t, list.Items)
+}
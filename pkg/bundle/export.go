@@ -0,0 +1,211 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// ExportTransform modifies content of the bundle file at given path before it
+// is exported.
+type ExportTransform func(path string, data []byte) ([]byte, error)
+
+// RedactPattern returns transform replacing all matches of the pattern with
+// the troubleshoot redaction mask. When the pattern contains a subexpression
+// only the first subexpression match is replaced, e.g. the value in
+// `password: (\S+)`.
+func RedactPattern(pattern *regexp.Regexp) ExportTransform {
+	return func(_ string, data []byte) ([]byte, error) {
+		if pattern.NumSubexp() == 0 {
+			return pattern.ReplaceAll(data, []byte(redactionMask)), nil
+		}
+		return pattern.ReplaceAllFunc(data, func(match []byte) []byte {
+			m := pattern.FindSubmatchIndex(match)
+			if m[2] < 0 {
+				return match
+			}
+			return append(append(append([]byte{}, match[:m[2]]...), redactionMask...), match[m[3]:]...)
+		}), nil
+	}
+}
+
+// ExportOptions configures the ExportBundle.
+type ExportOptions struct {
+	// SkipPaths are glob patterns of bundle paths, e.g. `secrets` or
+	// `pod-logs/kube-system/*`, that are not exported. A matched directory is
+	// skipped with its whole content.
+	SkipPaths []string
+	// Transforms are applied to each exported file in the given order.
+	Transforms []ExportTransform
+	// Tarball is a name of the `tar.gz` archive written to the destination
+	// instead of the bundle files, e.g. `support-bundle.tar.gz`. The files are
+	// stored in the archive under a directory named by the archive, as
+	// troubleshoot does, so the archive can be served by troubleshoot-live.
+	Tarball string
+}
+
+func (o ExportOptions) isSkipped(p string) (bool, error) {
+	p = filepath.ToSlash(p)
+	for _, pattern := range o.SkipPaths {
+		matched, err := path.Match(strings.TrimSuffix(filepath.ToSlash(pattern), "/"), p)
+		if err != nil {
+			return false, fmt.Errorf("invalid skip path pattern %q: %w", pattern, err)
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// exportWriter writes exported files to the destination.
+type exportWriter interface {
+	writeFile(path string, data []byte, info fs.FileInfo) error
+	close() error
+}
+
+// ExportBundle writes a copy of the bundle to the destination filesystem, e.g.
+// for sharing bundle with removed or redacted data. Files matching the skip
+// paths are not written and content of other files is modified by the
+// configured transforms. Gzip compressed files are transformed decompressed.
+func ExportBundle(b Bundle, dst afero.Fs, opts ExportOptions) error {
+	var w exportWriter = fsExportWriter{fs: dst}
+	if opts.Tarball != "" {
+		tw, err := newTarExportWriter(dst, opts.Tarball)
+		if err != nil {
+			return err
+		}
+		w = tw
+	}
+
+	err := afero.Walk(b, ".", func(p string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == "." {
+			return nil
+		}
+
+		skipped, err := opts.isSkipped(p)
+		switch {
+		case err != nil:
+			return err
+		case skipped && info.IsDir():
+			return filepath.SkipDir
+		case skipped, info.IsDir():
+			return nil
+		}
+
+		return exportFile(b, w, opts.Transforms, p, info)
+	})
+	if err != nil {
+		_ = w.close()
+		return err
+	}
+	return w.close()
+}
+
+// exportFile writes transformed file to the export writer. Compressed files
+// are transformed decompressed, so the transforms see the actual content, and
+// compressed again.
+func exportFile(b Bundle, w exportWriter, transforms []ExportTransform, p string, info fs.FileInfo) error {
+	data, err := readFile(b, p)
+	if err != nil {
+		return err
+	}
+	for _, transform := range transforms {
+		if data, err = transform(p, data); err != nil {
+			return fmt.Errorf("failed to transform %q: %w", p, err)
+		}
+	}
+	if strings.HasSuffix(p, ".gz") {
+		if data, err = compress(data); err != nil {
+			return fmt.Errorf("failed to compress %q: %w", p, err)
+		}
+	}
+	if err := w.writeFile(p, data, info); err != nil {
+		return fmt.Errorf("failed to export %q: %w", p, err)
+	}
+	return nil
+}
+
+func compress(data []byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	if _, err := gz.Write(data); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type fsExportWriter struct {
+	fs afero.Fs
+}
+
+func (w fsExportWriter) writeFile(p string, data []byte, info fs.FileInfo) error {
+	if err := w.fs.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	return afero.WriteFile(w.fs, p, data, info.Mode().Perm())
+}
+
+func (fsExportWriter) close() error {
+	return nil
+}
+
+type tarExportWriter struct {
+	file afero.File
+	gz   *gzip.Writer
+	tw   *tar.Writer
+	dir  string
+}
+
+func newTarExportWriter(dst afero.Fs, name string) (*tarExportWriter, error) {
+	f, err := dst.Create(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %q: %w", name, err)
+	}
+	gz := gzip.NewWriter(f)
+	return &tarExportWriter{
+		file: f,
+		gz:   gz,
+		tw:   tar.NewWriter(gz),
+		dir:  strings.TrimSuffix(filepath.Base(name), ".tar.gz"),
+	}, nil
+}
+
+func (w *tarExportWriter) writeFile(p string, data []byte, info fs.FileInfo) error {
+	if err := w.tw.WriteHeader(&tar.Header{
+		Name:    path.Join(w.dir, filepath.ToSlash(p)),
+		Mode:    int64(info.Mode().Perm()),
+		Size:    int64(len(data)),
+		ModTime: info.ModTime(),
+	}); err != nil {
+		return err
+	}
+	_, err := w.tw.Write(data)
+	return err
+}
+
+func (w *tarExportWriter) close() error {
+	tarErr := w.tw.Close()
+	gzErr := w.gz.Close()
+	fileErr := w.file.Close()
+	for _, err := range []error{tarErr, gzErr, fileErr} {
+		if err != nil {
+			return fmt.Errorf("failed to write archive: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"regexp"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportTestBundle(t *testing.T) Bundle {
+	t.Helper()
+	return newTestBundle(t, map[string]string{
+		"cluster-resources/pods/default.json":    `{"items": []}`,
+		"configmaps/default/app.json":            `{"data": {"db": "password: s3cr3t"}}`,
+		"secrets/default/token.json":             `{"data": {"token": "dG9rZW4="}}`,
+		"pod-logs/kube-system/etcd-0-etcd.log":   "etcd logs",
+		"pod-logs/default/app-0-app.log":         "login password: hunter2",
+		"cluster-info/cluster_version.json":      `{"string": "v1.27.3"}`,
+		"host-collectors/run-host/uptime-output": "up 3 days",
+	})
+}
+
+func exportTestOptions() ExportOptions {
+	return ExportOptions{
+		SkipPaths:  []string{"secrets", "pod-logs/kube-system/*"},
+		Transforms: []ExportTransform{RedactPattern(regexp.MustCompile(`password: ([^\s"]+)`))},
+	}
+}
+
+func TestExportBundle(t *testing.T) {
+	dst := afero.NewMemMapFs()
+	require.NoError(t, ExportBundle(exportTestBundle(t), dst, exportTestOptions()))
+
+	for _, path := range []string{"secrets/default/token.json", "pod-logs/kube-system/etcd-0-etcd.log"} {
+		exists, err := afero.Exists(dst, path)
+		require.NoError(t, err)
+		assert.False(t, exists, path)
+	}
+
+	data, err := afero.ReadFile(dst, "pod-logs/default/app-0-app.log")
+	require.NoError(t, err)
+	assert.Equal(t, "login password: ***HIDDEN***", string(data))
+
+	data, err = afero.ReadFile(dst, "configmaps/default/app.json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"data": {"db": "password: ***HIDDEN***"}}`, string(data))
+
+	data, err = afero.ReadFile(dst, "cluster-info/cluster_version.json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"string": "v1.27.3"}`, string(data))
+}
+
+func TestExportBundle_Tarball(t *testing.T) {
+	opts := exportTestOptions()
+	opts.Tarball = "support-bundle.tar.gz"
+
+	dst := afero.NewMemMapFs()
+	require.NoError(t, ExportBundle(exportTestBundle(t), dst, opts))
+
+	f, err := dst.Open("support-bundle.tar.gz")
+	require.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[h.Name] = string(data)
+	}
+
+	assert.Equal(t, map[string]string{
+		"support-bundle/cluster-resources/pods/default.json":    `{"items": []}`,
+		"support-bundle/configmaps/default/app.json":            `{"data": {"db": "password: ***HIDDEN***"}}`,
+		"support-bundle/pod-logs/default/app-0-app.log":         "login password: ***HIDDEN***",
+		"support-bundle/cluster-info/cluster_version.json":      `{"string": "v1.27.3"}`,
+		"support-bundle/host-collectors/run-host/uptime-output": "up 3 days",
+	}, files)
+}
+
+func TestExportBundle_Compressed(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/secrets/default.json.gz": gzipString(t, `{"data": "password: s3cr3t"}`),
+	})
+
+	dst := afero.NewMemMapFs()
+	require.NoError(t, ExportBundle(b, dst, exportTestOptions()))
+
+	data, err := afero.ReadFile(dst, "cluster-resources/secrets/default.json.gz")
+	require.NoError(t, err)
+	data, err = decompress(data, "default.json.gz")
+	require.NoError(t, err)
+	assert.Equal(t, `{"data": "password: ***HIDDEN***"}`, string(data))
+}
+
+func TestExportBundle_InvalidSkipPath(t *testing.T) {
+	err := ExportBundle(exportTestBundle(t), afero.NewMemMapFs(), ExportOptions{SkipPaths: []string{"["}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,200 @@
+package bundle
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// findControlPlanePod returns the static pod of given control plane component,
+// e.g. `kube-scheduler`, from the `kube-system` namespace. Nil is returned when
+// the pod is not present, e.g. in bundles from managed clusters.
+func findControlPlanePod(b Bundle, component string) (*corev1.Pod, error) {
+	path := filepath.Join(b.Layout().ClusterResources(), "pods", "kube-system")
+	list, err := LoadResources(b, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pods from %q: %w", path, err)
+	}
+
+	pods, err := convertList[corev1.Pod](list)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range pods {
+		if isControlPlanePod(&pods[i], component) {
+			return &pods[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+func isControlPlanePod(pod *corev1.Pod, component string) bool {
+	if !strings.HasPrefix(pod.GetName(), component+"-") {
+		return false
+	}
+	return pod.GetLabels()["component"] == component
+}
+
+// controlPlaneFlags returns flags of the container named after the control
+// plane component. Nil is returned when the component pod is not present.
+func controlPlaneFlags(b Bundle, component string) (map[string]string, error) {
+	pod, err := findControlPlanePod(b, component)
+	if err != nil || pod == nil {
+		return nil, err
+	}
+
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == component {
+			return containerFlags(&pod.Spec.Containers[i]), nil
+		}
+	}
+	return map[string]string{}, nil
+}
+
+// containerFlag is a flag passed to the container.
+type containerFlag struct {
+	name  string
+	value string
+}
+
+// parseContainerFlags parses `--flag=value` and `--flag value` arguments from
+// the container command and args in order. Flags without value, e.g.
+// `--profiling`, are set to `true`.
+func parseContainerFlags(c *corev1.Container) []containerFlag {
+	args := append(append([]string{}, c.Command...), c.Args...)
+
+	var flags []containerFlag
+	for i := 0; i < len(args); i++ {
+		if !strings.HasPrefix(args[i], "--") || args[i] == "--" {
+			continue
+		}
+		name, value, ok := strings.Cut(strings.TrimPrefix(args[i], "--"), "=")
+		if !ok {
+			value = "true"
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+				value = args[i+1]
+				i++
+			}
+		}
+		flags = append(flags, containerFlag{name: name, value: value})
+	}
+	return flags
+}
+
+// containerFlags returns flags of the container indexed by name. Repeated
+// flags contain the last value, see containerFlagValues for all values.
+func containerFlags(c *corev1.Container) map[string]string {
+	flags := map[string]string{}
+	for _, flag := range parseContainerFlags(c) {
+		flags[flag.name] = flag.value
+	}
+	return flags
+}
+
+// containerFlagValues returns values of all occurrences of a repeated flag
+// from the container command and args.
+func containerFlagValues(c *corev1.Container, name string) []string {
+	var values []string
+	for _, flag := range parseContainerFlags(c) {
+		if flag.name == name {
+			values = append(values, flag.value)
+		}
+	}
+	return values
+}
+
+// kubeSystemWorkload is a deployment, daemonset or pod from the `kube-system`
+// namespace with containers of its pod template.
+type kubeSystemWorkload struct {
+	// Kind is `Deployment`, `DaemonSet` or `Pod`.
+	Kind       string
+	Name       string
+	Containers []corev1.Container
+}
+
+// findKubeSystemWorkload returns the first `kube-system` workload accepted by
+// match. The resources directories, e.g. `deployments` or `pods`, are searched
+// in given order and the directories that weren't collected are skipped. Nil
+// is returned when no workload matches.
+func findKubeSystemWorkload(
+	b Bundle, match func(name string, labels map[string]string) bool, dirs ...string,
+) (*kubeSystemWorkload, error) {
+	for _, dir := range dirs {
+		list, err := LoadResources(b, filepath.Join(b.Layout().ClusterResources(), dir, "kube-system"))
+		if isNotCollected(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load kube-system %s: %w", dir, err)
+		}
+
+		workloads, err := convertKubeSystemWorkloads(dir, list.Items)
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			if match(list.Items[i].GetName(), list.Items[i].GetLabels()) {
+				return &workloads[i], nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func convertKubeSystemWorkloads(dir string, items []unstructured.Unstructured) ([]kubeSystemWorkload, error) {
+	list := &unstructured.UnstructuredList{Items: items}
+	workloads := make([]kubeSystemWorkload, 0, len(items))
+	switch dir {
+	case "deployments":
+		deployments, err := convertList[appsv1.Deployment](list)
+		if err != nil {
+			return nil, err
+		}
+		for i := range deployments {
+			workloads = append(workloads, kubeSystemWorkload{
+				Kind: "Deployment", Name: deployments[i].Name, Containers: deployments[i].Spec.Template.Spec.Containers,
+			})
+		}
+	case "daemonsets":
+		daemonSets, err := convertList[appsv1.DaemonSet](list)
+		if err != nil {
+			return nil, err
+		}
+		for i := range daemonSets {
+			workloads = append(workloads, kubeSystemWorkload{
+				Kind: "DaemonSet", Name: daemonSets[i].Name, Containers: daemonSets[i].Spec.Template.Spec.Containers,
+			})
+		}
+	case "pods":
+		pods, err := convertList[corev1.Pod](list)
+		if err != nil {
+			return nil, err
+		}
+		for i := range pods {
+			workloads = append(workloads, kubeSystemWorkload{Kind: "Pod", Name: pods[i].Name, Containers: pods[i].Spec.Containers})
+		}
+	default:
+		return nil, fmt.Errorf("unsupported workload resources %q", dir)
+	}
+	return workloads, nil
+}
+
+// findContainer returns the container whose name or image contains given
+// name, or the first container.
+func findContainer(containers []corev1.Container, name string) *corev1.Container {
+	for i := range containers {
+		if strings.Contains(containers[i].Name, name) || strings.Contains(containers[i].Image, name) {
+			return &containers[i]
+		}
+	}
+	if len(containers) > 0 {
+		return &containers[0]
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package bundle
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ImageUsage describes a container that uses an image.
+type ImageUsage struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Init      bool   `json:"init"`
+	// ImageID is the resolved image digest reported in the container status.
+	ImageID string `json:"imageID,omitempty"`
+	// PullError is the `ImagePullBackOff` or `ErrImagePull` reason when
+	// the image couldn't be pulled.
+	PullError string `json:"pullError,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// isImagePullError checks if the container waiting reason is caused by image
+// pull failure.
+func isImagePullError(reason string) bool {
+	switch reason {
+	case "ImagePullBackOff", "ErrImagePull", "ErrImageNeverPull", "InvalidImageName":
+		return true
+	}
+	return false
+}
+
+// ListImages returns images used by pod containers across all namespaces,
+// indexed by the image from the pod spec. Empty result is returned when pods
+// were not collected.
+func ListImages(b Bundle) (map[string][]ImageUsage, error) {
+	list, err := loadNamespacedResources(b, "pods")
+	if isNotCollected(err) {
+		return map[string][]ImageUsage{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	pods, err := convertList[corev1.Pod](list)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string][]ImageUsage{}
+	for i := range pods {
+		for _, view := range PodContainerViews(&pods[i]) {
+			usage := ImageUsage{
+				Namespace: pods[i].Namespace,
+				Pod:       pods[i].Name,
+				Container: view.Name,
+				Init:      view.Init,
+				ImageID:   view.ImageID,
+			}
+			if waiting := view.State.Waiting; waiting != nil && isImagePullError(waiting.Reason) {
+				usage.PullError, usage.Message = waiting.Reason, waiting.Message
+			}
+			result[view.Image] = append(result[view.Image], usage)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,65 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const imagesPods = `{"items": [
+  {
+    "metadata": {"name": "web-0", "namespace": "default"},
+    "spec": {
+      "initContainers": [{"name": "init", "image": "busybox:1.36"}],
+      "containers": [{"name": "app", "image": "registry.example.com/web:1.2"}]
+    },
+    "status": {
+      "initContainerStatuses": [{"name": "init", "imageID": "docker.io/library/busybox@sha256:111"}],
+      "containerStatuses": [{"name": "app", "imageID": "registry.example.com/web@sha256:222"}]
+    }
+  },
+  {
+    "metadata": {"name": "web-1", "namespace": "default"},
+    "spec": {"containers": [{"name": "app", "image": "registry.example.com/web:1.3"}]},
+    "status": {"containerStatuses": [{
+      "name": "app",
+      "state": {"waiting": {"reason": "ImagePullBackOff", "message": "Back-off pulling image \"registry.example.com/web:1.3\""}}
+    }]}
+  },
+  {
+    "metadata": {"name": "debug", "namespace": "default"},
+    "spec": {"containers": [{"name": "shell", "image": "busybox:1.36"}]},
+    "status": {"containerStatuses": [{"name": "shell", "state": {"waiting": {"reason": "ErrImagePull", "message": "rate limited"}}}]}
+  }
+]}`
+
+func TestListImages(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/pods/default.json": imagesPods,
+	})
+
+	images, err := ListImages(b)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]ImageUsage{
+		"busybox:1.36": {
+			{Namespace: "default", Pod: "web-0", Container: "init", Init: true, ImageID: "docker.io/library/busybox@sha256:111"},
+			{Namespace: "default", Pod: "debug", Container: "shell", PullError: "ErrImagePull", Message: "rate limited"},
+		},
+		"registry.example.com/web:1.2": {
+			{Namespace: "default", Pod: "web-0", Container: "app", ImageID: "registry.example.com/web@sha256:222"},
+		},
+		"registry.example.com/web:1.3": {
+			{
+				Namespace: "default", Pod: "web-1", Container: "app",
+				PullError: "ImagePullBackOff", Message: `Back-off pulling image "registry.example.com/web:1.3"`,
+			},
+		},
+	}, images)
+}
+
+func TestListImages_NotCollected(t *testing.T) {
+	images, err := ListImages(newTestBundle(t, map[string]string{}))
+	require.NoError(t, err)
+	assert.Empty(t, images)
+}
@@ -1,5 +1,7 @@
 package bundle
 
+import "path/filepath"
+
 // Layout defines paths under which are particular resources stored.
 type Layout interface {
 	ClusterInfo() string
@@ -7,6 +9,44 @@ type Layout interface {
 	PodLogs() string
 	ConfigMaps() string
 	Secrets() string
+	// Analysis returns path of the file with analyzer results.
+	Analysis() string
+
+	// SkipResources returns names of files from cluster resources that are
+	// not imported. The names can be `filepath.Match` patterns, e.g.
+	// `resources-*.json`.
+	SkipResources() []string
+	// SkipResourceMatches checks if the base name of the file matches any of
+	// the SkipResources patterns.
+	SkipResourceMatches(name string) bool
+	// SkipDirs returns names of cluster resources directories that are
+	// not imported.
+	SkipDirs() []string
+}
+
+// DefaultSkipResources returns names of cluster resources files that are not
+// imported by default.
+func DefaultSkipResources() []string {
+	return []string{
+		// crds are imported during a separate step
+		"custom-resource-definitions.json",
+		"pod-disruption-budgets-info.json",
+		// api-resources from the discovery client
+		"resources.json",
+		// api-groups from the discovery client
+		"groups.json",
+		// namespaces are imported as first resource in a separate step
+		"namespaces.json",
+	}
+}
+
+// DefaultSkipDirs returns names of cluster resources directories that are not
+// imported by default.
+func DefaultSkipDirs() []string {
+	return []string{
+		"auth-cani-list",
+		"pod-disruption-budgets",
+	}
 }
 
 type defaultLayout struct{}
@@ -30,3 +70,31 @@ func (defaultLayout) ConfigMaps() string {
 func (defaultLayout) Secrets() string {
 	return "secrets"
 }
+
+func (defaultLayout) Analysis() string {
+	return "analysis.json"
+}
+
+func (defaultLayout) SkipResources() []string {
+	return DefaultSkipResources()
+}
+
+func (l defaultLayout) SkipResourceMatches(name string) bool {
+	return matchesSkipPattern(l.SkipResources(), name)
+}
+
+func (defaultLayout) SkipDirs() []string {
+	return DefaultSkipDirs()
+}
+
+// matchesSkipPattern checks if the base name matches any of the patterns.
+// Invalid patterns are compared literally.
+func matchesSkipPattern(patterns []string, name string) bool {
+	name = filepath.Base(name)
+	for _, pattern := range patterns {
+		if ok, err := filepath.Match(pattern, name); ok || (err != nil && pattern == name) {
+			return true
+		}
+	}
+	return false
+}
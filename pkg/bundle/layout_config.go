@@ -0,0 +1,328 @@
+package bundle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/afero"
+	"sigs.k8s.io/yaml"
+)
+
+// ConfigDirName is the name of the directory with troubleshoot-live
+// configuration. Downstream distributions can change it to use own directory,
+// e.g. `$XDG_CONFIG_HOME/<name>` and `~/.<name>`.
+var ConfigDirName = "troubleshoot-live" //nolint:gochecknoglobals // Allows rebranding without code changes.
+
+// ConfigFileName is the name of the layout config file in the config directory.
+const ConfigFileName = "config.yaml"
+
+// JSONConfigFileName is the name of the layout config file in JSON, which is
+// used when the config directory doesn't contain ConfigFileName.
+const JSONConfigFileName = "config.json"
+
+// LayoutConfig overrides paths and skip lists of the default layout. Empty
+// values are inherited from the default layout.
+type LayoutConfig struct {
+	// RootPrefix is the directory of the extracted bundle with the bundle
+	// data, e.g. `support-bundle-2024-01-01`. When empty, a single wrapper
+	// directory is detected automatically.
+	RootPrefix string `json:"rootPrefix,omitempty"`
+
+	ClusterInfo      string `json:"clusterInfo,omitempty"`
+	ClusterResources string `json:"clusterResources,omitempty"`
+	PodLogs          string `json:"podLogs,omitempty"`
+	ConfigMaps       string `json:"configMaps,omitempty"`
+	Secrets          string `json:"secrets,omitempty"`
+	Analysis         string `json:"analysis,omitempty"`
+
+	SkipResources []string `json:"skipResources,omitempty"`
+	SkipDirs      []string `json:"skipDirs,omitempty"`
+
+	// RemoteLogURLTemplate is URL of logs for pods without logs in the bundle,
+	// e.g. `https://logs.example.com/{namespace}/{pod}/{container}`.
+	RemoteLogURLTemplate string `json:"remoteLogURLTemplate,omitempty"`
+}
+
+// Validate checks that all paths are relative, clean paths inside the bundle
+// root. Absolute paths, `..` segments and empty path components are rejected.
+func (c *LayoutConfig) Validate() error {
+	paths := []struct {
+		field string
+		path  string
+	}{
+		{"rootPrefix", c.RootPrefix},
+		{"clusterInfo", c.ClusterInfo},
+		{"clusterResources", c.ClusterResources},
+		{"podLogs", c.PodLogs},
+		{"configMaps", c.ConfigMaps},
+		{"secrets", c.Secrets},
+		{"analysis", c.Analysis},
+	}
+	for _, p := range paths {
+		if err := validateLayoutPath(p.path); err != nil {
+			return fmt.Errorf("invalid %s %q: %w", p.field, p.path, err)
+		}
+	}
+	return nil
+}
+
+func validateLayoutPath(path string) error {
+	switch {
+	case path == "":
+		return nil
+	case filepath.IsAbs(path) || strings.HasPrefix(path, "/"):
+		return errors.New("must be a relative path inside the bundle")
+	case slices.Contains(strings.Split(filepath.ToSlash(path), "/"), ".."):
+		return errors.New("must not contain \"..\" path segments")
+	case filepath.Clean(path) != path:
+		return fmt.Errorf("must be a clean path, e.g. %q", filepath.Clean(path))
+	}
+	return nil
+}
+
+type configLayout struct {
+	cfg LayoutConfig
+}
+
+func valueOrDefault[T string | []string](value, defaultValue T) T {
+	if len(value) == 0 {
+		return defaultValue
+	}
+	return value
+}
+
+func (l configLayout) ClusterInfo() string {
+	return valueOrDefault(l.cfg.ClusterInfo, defaultLayout{}.ClusterInfo())
+}
+
+func (l configLayout) ClusterResources() string {
+	return valueOrDefault(l.cfg.ClusterResources, defaultLayout{}.ClusterResources())
+}
+
+func (l configLayout) PodLogs() string {
+	return valueOrDefault(l.cfg.PodLogs, defaultLayout{}.PodLogs())
+}
+
+func (l configLayout) ConfigMaps() string {
+	return valueOrDefault(l.cfg.ConfigMaps, defaultLayout{}.ConfigMaps())
+}
+
+func (l configLayout) Secrets() string {
+	return valueOrDefault(l.cfg.Secrets, defaultLayout{}.Secrets())
+}
+
+func (l configLayout) Analysis() string {
+	return valueOrDefault(l.cfg.Analysis, defaultLayout{}.Analysis())
+}
+
+func (l configLayout) SkipResources() []string {
+	return valueOrDefault(l.cfg.SkipResources, defaultLayout{}.SkipResources())
+}
+
+func (l configLayout) SkipResourceMatches(name string) bool {
+	return matchesSkipPattern(l.SkipResources(), name)
+}
+
+func (l configLayout) SkipDirs() []string {
+	return valueOrDefault(l.cfg.SkipDirs, defaultLayout{}.SkipDirs())
+}
+
+func (l configLayout) RootPrefix() string {
+	return l.cfg.RootPrefix
+}
+
+// RemoteLogURL returns URL of container logs stored outside of the bundle
+// created from the layout config `remoteLogURLTemplate`. The `{namespace}`,
+// `{pod}` and `{container}` placeholders are replaced with escaped values.
+// Empty string is returned when the layout doesn't configure remote logs.
+func RemoteLogURL(l Layout, namespace, pod, container string) string {
+	switch l := l.(type) {
+	case configLayout:
+		if l.cfg.RemoteLogURLTemplate == "" {
+			return ""
+		}
+		return strings.NewReplacer(
+			"{namespace}", url.PathEscape(namespace),
+			"{pod}", url.PathEscape(pod),
+			"{container}", url.PathEscape(container),
+		).Replace(l.cfg.RemoteLogURLTemplate)
+	case envSkipListsLayout:
+		return RemoteLogURL(l.Layout, namespace, pod, container)
+	case openShiftLayout:
+		return RemoteLogURL(l.Layout, namespace, pod, container)
+	}
+	return ""
+}
+
+// withoutPaths returns layout with skip lists and remote logs of the config
+// layout and paths of the default layout. It is used for bundles converted to
+// the default layout, e.g. from a cluster-info dump.
+func withoutPaths(l Layout) Layout {
+	cl, ok := l.(configLayout)
+	if !ok {
+		return defaultLayout{}
+	}
+	return configLayout{cfg: LayoutConfig{
+		SkipResources:        cl.cfg.SkipResources,
+		SkipDirs:             cl.cfg.SkipDirs,
+		RemoteLogURLTemplate: cl.cfg.RemoteLogURLTemplate,
+	}}
+}
+
+// LoadLayoutFromConfig creates layout from the config file at given path.
+// Files with the `.json` extension are decoded as JSON, any other files as
+// YAML.
+func LoadLayoutFromConfig(fs afero.Fs, path string) (Layout, error) {
+	cfg, err := loadLayoutConfig(fs, path)
+	if err != nil {
+		return nil, err
+	}
+	return configLayout{cfg: *cfg}, nil
+}
+
+// LoadLayoutFromHome creates layout from the config file in user's config
+// directory. The `$XDG_CONFIG_HOME/<ConfigDirName>/config.yaml` file takes
+// precedence over the legacy `~/.<ConfigDirName>/config.yaml`. In each
+// directory `config.json` is used when `config.yaml` doesn't exist. The
+// default layout is returned when none of the files exists.
+func LoadLayoutFromHome() (Layout, error) {
+	cfg, err := loadLayoutConfigFromHome()
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		return defaultLayout{}, nil
+	}
+	return configLayout{cfg: *cfg}, nil
+}
+
+// LoadLayoutWithFallback creates layout by merging all existing configs. Each
+// value is taken from the config stored in the bundle directory at
+// `.<ConfigDirName>/config.yaml` or `config.json`, then from the TSLIVE_PATH_* environment
+// variables and then from the config in user's home directory. Values that
+// aren't set by any config are inherited from the default layout.
+func LoadLayoutWithFallback(b afero.Fs) (Layout, error) {
+	bundleCfg, err := loadFirstLayoutConfig(b, configFilePaths("."+ConfigDirName))
+	if err != nil {
+		return nil, err
+	}
+
+	homeCfg, err := loadLayoutConfigFromHome()
+	if err != nil {
+		return nil, err
+	}
+
+	envCfg := layoutConfigFromEnv()
+	if envCfg != nil {
+		if err := envCfg.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid layout from environment: %w", err)
+		}
+	}
+
+	cfg := mergeLayoutConfigs(bundleCfg, envCfg, homeCfg)
+	if cfg == nil {
+		return defaultLayout{}, nil
+	}
+	return configLayout{cfg: *cfg}, nil
+}
+
+// mergeLayoutConfigs merges configs ordered by precedence. Empty values are
+// inherited from the following configs, nil configs are ignored. Nil is
+// returned when all configs are nil.
+func mergeLayoutConfigs(configs ...*LayoutConfig) *LayoutConfig {
+	var merged *LayoutConfig
+	for i := len(configs) - 1; i >= 0; i-- {
+		cfg := configs[i]
+		if cfg == nil {
+			continue
+		}
+		if merged == nil {
+			merged = &LayoutConfig{}
+		}
+		merged.RootPrefix = valueOrDefault(cfg.RootPrefix, merged.RootPrefix)
+		merged.ClusterInfo = valueOrDefault(cfg.ClusterInfo, merged.ClusterInfo)
+		merged.ClusterResources = valueOrDefault(cfg.ClusterResources, merged.ClusterResources)
+		merged.PodLogs = valueOrDefault(cfg.PodLogs, merged.PodLogs)
+		merged.ConfigMaps = valueOrDefault(cfg.ConfigMaps, merged.ConfigMaps)
+		merged.Secrets = valueOrDefault(cfg.Secrets, merged.Secrets)
+		merged.Analysis = valueOrDefault(cfg.Analysis, merged.Analysis)
+		merged.SkipResources = valueOrDefault(cfg.SkipResources, merged.SkipResources)
+		merged.SkipDirs = valueOrDefault(cfg.SkipDirs, merged.SkipDirs)
+		merged.RemoteLogURLTemplate = valueOrDefault(cfg.RemoteLogURLTemplate, merged.RemoteLogURLTemplate)
+	}
+	return merged
+}
+
+// configFilePaths returns paths of the config files in the directory ordered
+// by precedence.
+func configFilePaths(dir string) []string {
+	return []string{filepath.Join(dir, ConfigFileName), filepath.Join(dir, JSONConfigFileName)}
+}
+
+// homeConfigPaths returns candidate paths of the config file in user's home
+// ordered by precedence. Paths in the home directory are omitted when the
+// home directory is unknown, e.g. when `$HOME` isn't set.
+func homeConfigPaths() []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = ""
+	}
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" && home != "" {
+		configHome = filepath.Join(home, ".config")
+	}
+
+	paths := []string{}
+	if configHome != "" {
+		paths = append(paths, configFilePaths(filepath.Join(configHome, ConfigDirName))...)
+	}
+	if home != "" {
+		paths = append(paths, configFilePaths(filepath.Join(home, "."+ConfigDirName))...)
+	}
+	return paths
+}
+
+func loadLayoutConfigFromHome() (*LayoutConfig, error) {
+	return loadFirstLayoutConfig(afero.NewOsFs(), homeConfigPaths())
+}
+
+// loadFirstLayoutConfig loads the first existing config file. Nil is returned
+// when none of the files exists.
+func loadFirstLayoutConfig(b afero.Fs, paths []string) (*LayoutConfig, error) {
+	for _, path := range paths {
+		cfg, err := loadLayoutConfig(b, path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		return cfg, err
+	}
+	return nil, nil
+}
+
+func loadLayoutConfig(fs afero.Fs, path string) (*LayoutConfig, error) {
+	data, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &LayoutConfig{}
+	unmarshal := func(data []byte, v any) error { return yaml.Unmarshal(data, v) }
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		unmarshal = json.Unmarshal
+	}
+	if err := unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse layout config %q: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid layout config %q: %w", path, err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,201 @@
+package bundle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, path, data string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+}
+
+func setupHome(t *testing.T) string {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	return home
+}
+
+func TestLoadLayoutFromConfig(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "config.yaml", []byte("podLogs: logs\nskipDirs: [events]\n"), 0o600))
+
+	l, err := LoadLayoutFromConfig(fs, "config.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "logs", l.PodLogs())
+	assert.Equal(t, []string{"events"}, l.SkipDirs())
+	assert.Equal(t, "cluster-resources", l.ClusterResources())
+	assert.Equal(t, DefaultSkipResources(), l.SkipResources())
+}
+
+func TestLoadLayoutFromConfig_JSON(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "config.yaml", []byte("podLogs: logs\nskipDirs: [events]\n"), 0o600))
+	require.NoError(t, afero.WriteFile(fs, "config.json", []byte(`{"podLogs": "logs", "skipDirs": ["events"]}`), 0o600))
+
+	fromYAML, err := LoadLayoutFromConfig(fs, "config.yaml")
+	require.NoError(t, err)
+	fromJSON, err := LoadLayoutFromConfig(fs, "config.json")
+	require.NoError(t, err)
+	assert.Equal(t, fromYAML, fromJSON)
+
+	require.NoError(t, afero.WriteFile(fs, "config.json", []byte("podLogs: logs\n"), 0o600))
+	_, err = LoadLayoutFromConfig(fs, "config.json")
+	assert.ErrorContains(t, err, `failed to parse layout config "config.json"`)
+}
+
+func TestLoadLayoutFromConfig_InvalidPath(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "config.yaml", []byte("clusterResources: /etc/passwd\n"), 0o600))
+
+	_, err := LoadLayoutFromConfig(fs, "config.yaml")
+	assert.ErrorContains(t, err, `invalid clusterResources "/etc/passwd": must be a relative path inside the bundle`)
+}
+
+func TestLayoutConfig_Validate(t *testing.T) {
+	for _, cfg := range []LayoutConfig{
+		{},
+		{RootPrefix: "support-bundle", PodLogs: "logs/pods", Analysis: "analysis.json"},
+		{ClusterResources: "..resources"},
+	} {
+		assert.NoError(t, cfg.Validate(), "%+v", cfg)
+	}
+
+	for expected, cfg := range map[string]LayoutConfig{
+		`invalid podLogs "/var/log"`:         {PodLogs: "/var/log"},
+		`invalid secrets "../secrets"`:       {Secrets: "../secrets"},
+		`invalid configMaps "a/../../b"`:     {ConfigMaps: "a/../../b"},
+		`invalid clusterInfo "info//nested"`: {ClusterInfo: "info//nested"},
+		`invalid rootPrefix "./bundle"`:      {RootPrefix: "./bundle"},
+		`invalid analysis "analysis/"`:       {Analysis: "analysis/"},
+	} {
+		assert.ErrorContains(t, cfg.Validate(), expected)
+	}
+}
+
+func TestLoadLayoutFromHome(t *testing.T) {
+	home := setupHome(t)
+
+	l, err := LoadLayoutFromHome()
+	require.NoError(t, err)
+	assert.Equal(t, "pod-logs", l.PodLogs(), "default layout without config")
+
+	writeConfig(t, filepath.Join(home, ".troubleshoot-live", "config.yaml"), "podLogs: legacy")
+	l, err = LoadLayoutFromHome()
+	require.NoError(t, err)
+	assert.Equal(t, "legacy", l.PodLogs())
+
+	writeConfig(t, filepath.Join(home, ".config", "troubleshoot-live", "config.yaml"), "podLogs: xdg")
+	l, err = LoadLayoutFromHome()
+	require.NoError(t, err)
+	assert.Equal(t, "xdg", l.PodLogs(), "XDG config takes precedence")
+}
+
+func TestLoadLayoutFromHome_ConfigDirName(t *testing.T) {
+	home := setupHome(t)
+	xdgHome := filepath.Join(home, "xdg")
+	t.Setenv("XDG_CONFIG_HOME", xdgHome)
+
+	original := ConfigDirName
+	ConfigDirName = "my-distro"
+	t.Cleanup(func() { ConfigDirName = original })
+
+	writeConfig(t, filepath.Join(home, ".troubleshoot-live", "config.yaml"), "podLogs: ignored")
+	writeConfig(t, filepath.Join(home, ".my-distro", "config.yaml"), "podLogs: legacy")
+	l, err := LoadLayoutFromHome()
+	require.NoError(t, err)
+	assert.Equal(t, "legacy", l.PodLogs())
+
+	writeConfig(t, filepath.Join(xdgHome, "my-distro", "config.yaml"), "podLogs: xdg")
+	l, err = LoadLayoutFromHome()
+	require.NoError(t, err)
+	assert.Equal(t, "xdg", l.PodLogs())
+}
+
+func TestLoadLayoutWithFallback(t *testing.T) {
+	home := setupHome(t)
+	writeConfig(t, filepath.Join(home, ".troubleshoot-live", "config.yaml"), "podLogs: home")
+
+	fs := afero.NewMemMapFs()
+	l, err := LoadLayoutWithFallback(fs)
+	require.NoError(t, err)
+	assert.Equal(t, "home", l.PodLogs())
+
+	t.Setenv(EnvPathPodLogs, "env")
+	l, err = LoadLayoutWithFallback(fs)
+	require.NoError(t, err)
+	assert.Equal(t, "env", l.PodLogs(), "environment takes precedence over home config")
+
+	require.NoError(t, afero.WriteFile(fs, ".troubleshoot-live/config.yaml", []byte("clusterInfo: info"), 0o600))
+	l, err = LoadLayoutWithFallback(fs)
+	require.NoError(t, err)
+	assert.Equal(t, "info", l.ClusterInfo())
+	assert.Equal(t, "env", l.PodLogs(), "values missing in bundle config are inherited")
+	assert.Equal(t, "secrets", l.Secrets())
+
+	require.NoError(t, afero.WriteFile(fs, ".troubleshoot-live/config.yaml", []byte("podLogs: bundle"), 0o600))
+	l, err = LoadLayoutWithFallback(fs)
+	require.NoError(t, err)
+	assert.Equal(t, "bundle", l.PodLogs(), "bundle config takes precedence")
+
+	require.NoError(t, afero.WriteFile(fs, ".troubleshoot-live/config.yaml", []byte("podLogs: [invalid"), 0o600))
+	_, err = LoadLayoutWithFallback(fs)
+	assert.ErrorContains(t, err, "failed to parse layout config")
+}
+
+func TestLoadLayoutWithFallback_JSON(t *testing.T) {
+	home := setupHome(t)
+	writeConfig(t, filepath.Join(home, ".troubleshoot-live", "config.json"), `{"podLogs": "home"}`)
+
+	fs := afero.NewMemMapFs()
+	l, err := LoadLayoutWithFallback(fs)
+	require.NoError(t, err)
+	assert.Equal(t, "home", l.PodLogs())
+
+	require.NoError(t, afero.WriteFile(fs, ".troubleshoot-live/config.json", []byte(`{"secrets": "bundle"}`), 0o600))
+	l, err = LoadLayoutWithFallback(fs)
+	require.NoError(t, err)
+	assert.Equal(t, "bundle", l.Secrets())
+
+	require.NoError(t, afero.WriteFile(fs, ".troubleshoot-live/config.yaml", []byte("secrets: yaml"), 0o600))
+	l, err = LoadLayoutWithFallback(fs)
+	require.NoError(t, err)
+	assert.Equal(t, "yaml", l.Secrets(), "config.yaml takes precedence over config.json")
+}
+
+func TestLoadLayoutWithFallback_NoHome(t *testing.T) {
+	t.Setenv("HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	l, err := LoadLayoutWithFallback(afero.NewMemMapFs())
+	require.NoError(t, err)
+	assert.Equal(t, defaultLayout{}, l)
+
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	writeConfig(t, filepath.Join(configHome, "troubleshoot-live", "config.yaml"), "podLogs: xdg")
+	l, err = LoadLayoutWithFallback(afero.NewMemMapFs())
+	require.NoError(t, err)
+	assert.Equal(t, "xdg", l.PodLogs())
+}
+
+func TestMergeLayoutConfigs(t *testing.T) {
+	assert.Nil(t, mergeLayoutConfigs(nil, nil))
+
+	merged := mergeLayoutConfigs(
+		&LayoutConfig{ClusterInfo: "bundle", SkipDirs: []string{}},
+		nil,
+		&LayoutConfig{ClusterInfo: "home", PodLogs: "home", SkipDirs: []string{"events"}},
+	)
+	assert.Equal(t, &LayoutConfig{ClusterInfo: "bundle", PodLogs: "home", SkipDirs: []string{"events"}}, merged)
+}
@@ -0,0 +1,141 @@
+package bundle
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// EnvSkipResources contains comma separated list of cluster resources
+	// files that should not be imported.
+	EnvSkipResources = "TSLIVE_SKIP_RESOURCES"
+	// EnvSkipDirs contains comma separated list of cluster resources
+	// directories that should not be imported.
+	EnvSkipDirs = "TSLIVE_SKIP_DIRS"
+	// EnvSkipMode controls whether the skip lists from environment are
+	// appended to the configured lists (`append`, default) or replace them
+	// (`replace`).
+	EnvSkipMode = "TSLIVE_SKIP_MODE"
+)
+
+// Environment variables overriding paths of the layout.
+const (
+	EnvRootPrefix           = "TSLIVE_ROOT_PREFIX"
+	EnvPathClusterInfo      = "TSLIVE_PATH_CLUSTER_INFO"
+	EnvPathClusterResources = "TSLIVE_PATH_CLUSTER_RESOURCES"
+	EnvPathPodLogs          = "TSLIVE_PATH_POD_LOGS"
+	EnvPathConfigMaps       = "TSLIVE_PATH_CONFIGMAPS"
+	EnvPathSecrets          = "TSLIVE_PATH_SECRETS"
+	EnvPathAnalysis         = "TSLIVE_PATH_ANALYSIS"
+	EnvRemoteLogURLTemplate = "TSLIVE_REMOTE_LOG_URL_TEMPLATE"
+)
+
+const (
+	skipModeAppend  = "append"
+	skipModeReplace = "replace"
+)
+
+type envSkipListsLayout struct {
+	Layout
+
+	skipResources []string
+	skipDirs      []string
+}
+
+func (l envSkipListsLayout) SkipResources() []string {
+	return l.skipResources
+}
+
+func (l envSkipListsLayout) SkipResourceMatches(name string) bool {
+	return matchesSkipPattern(l.skipResources, name)
+}
+
+func (l envSkipListsLayout) SkipDirs() []string {
+	return l.skipDirs
+}
+
+// WithSkipListsFromEnv returns layout with skip lists extended or replaced by
+// values from the EnvSkipResources and EnvSkipDirs environment variables.
+// Empty variables are ignored.
+func WithSkipListsFromEnv(l Layout) (Layout, error) {
+	mode := os.Getenv(EnvSkipMode)
+	switch mode {
+	case "":
+		mode = skipModeAppend
+	case skipModeAppend, skipModeReplace:
+	default:
+		return nil, fmt.Errorf("invalid %s value %q, expected %q or %q", EnvSkipMode, mode, skipModeAppend, skipModeReplace)
+	}
+
+	return envSkipListsLayout{
+		Layout:        l,
+		skipResources: mergeSkipList(l.SkipResources(), listFromEnv(EnvSkipResources), mode),
+		skipDirs:      mergeSkipList(l.SkipDirs(), listFromEnv(EnvSkipDirs), mode),
+	}, nil
+}
+
+func mergeSkipList(configured, fromEnv []string, mode string) []string {
+	if len(fromEnv) == 0 {
+		return configured
+	}
+	if mode == skipModeReplace {
+		return fromEnv
+	}
+	return append(append([]string{}, configured...), fromEnv...)
+}
+
+// listFromEnv parses comma separated list from environment variable.
+func listFromEnv(name string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(name), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// LoadLayoutFromEnv creates layout from the environment variables. Paths are
+// overridden by the TSLIVE_PATH_* variables, e.g. EnvPathPodLogs, and the skip
+// lists are changed like by WithSkipListsFromEnv. Values that are not set are
+// inherited from the default layout.
+func LoadLayoutFromEnv() (Layout, error) {
+	cfg := layoutConfigFromEnv()
+	if cfg == nil {
+		cfg = &LayoutConfig{}
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid layout from environment: %w", err)
+	}
+	return WithSkipListsFromEnv(configLayout{cfg: *cfg})
+}
+
+// layoutConfigFromEnv returns layout config with paths from the environment
+// variables. Nil is returned when none of the variables is set. Skip lists
+// are not included, they are applied to any layout by WithSkipListsFromEnv.
+func layoutConfigFromEnv() *LayoutConfig {
+	cfg := &LayoutConfig{}
+	fields := map[string]*string{
+		EnvRootPrefix:           &cfg.RootPrefix,
+		EnvPathClusterInfo:      &cfg.ClusterInfo,
+		EnvPathClusterResources: &cfg.ClusterResources,
+		EnvPathPodLogs:          &cfg.PodLogs,
+		EnvPathConfigMaps:       &cfg.ConfigMaps,
+		EnvPathSecrets:          &cfg.Secrets,
+		EnvPathAnalysis:         &cfg.Analysis,
+		EnvRemoteLogURLTemplate: &cfg.RemoteLogURLTemplate,
+	}
+
+	found := false
+	for name, field := range fields {
+		if value := strings.TrimSpace(os.Getenv(name)); value != "" {
+			*field = value
+			found = true
+		}
+	}
+	if !found {
+		return nil
+	}
+	return cfg
+}
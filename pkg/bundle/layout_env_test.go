@@ -0,0 +1,80 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithSkipListsFromEnv_Append(t *testing.T) {
+	t.Setenv(EnvSkipResources, "leases.json, ,events.json")
+	t.Setenv(EnvSkipDirs, "")
+
+	l, err := WithSkipListsFromEnv(defaultLayout{})
+	require.NoError(t, err)
+	assert.Equal(t, append(DefaultSkipResources(), "leases.json", "events.json"), l.SkipResources())
+	assert.Equal(t, DefaultSkipDirs(), l.SkipDirs())
+	assert.Equal(t, "cluster-resources", l.ClusterResources())
+}
+
+func TestWithSkipListsFromEnv_Replace(t *testing.T) {
+	t.Setenv(EnvSkipDirs, "events")
+	t.Setenv(EnvSkipMode, "replace")
+
+	l, err := WithSkipListsFromEnv(defaultLayout{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"events"}, l.SkipDirs())
+	assert.Equal(t, DefaultSkipResources(), l.SkipResources(), "empty variable is ignored")
+}
+
+func TestWithSkipListsFromEnv_InvalidMode(t *testing.T) {
+	t.Setenv(EnvSkipMode, "merge")
+
+	_, err := WithSkipListsFromEnv(defaultLayout{})
+	assert.ErrorContains(t, err, EnvSkipMode)
+}
+
+func TestLoadLayoutFromEnv(t *testing.T) {
+	t.Setenv(EnvPathPodLogs, "logs")
+	t.Setenv(EnvPathSecrets, " ")
+	t.Setenv(EnvSkipDirs, "events")
+
+	l, err := LoadLayoutFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "logs", l.PodLogs())
+	assert.Equal(t, "secrets", l.Secrets(), "empty variable is ignored")
+	assert.Equal(t, "cluster-resources", l.ClusterResources())
+	assert.Equal(t, append(DefaultSkipDirs(), "events"), l.SkipDirs())
+}
+
+func TestLayoutConfigFromEnv_NotSet(t *testing.T) {
+	t.Setenv(EnvSkipDirs, "events")
+
+	assert.Nil(t, layoutConfigFromEnv(), "skip lists alone don't select the environment layout")
+}
+
+func TestLoadLayoutFromEnv_InvalidPath(t *testing.T) {
+	t.Setenv(EnvPathClusterResources, "../other")
+
+	_, err := LoadLayoutFromEnv()
+	assert.ErrorContains(t, err, `invalid clusterResources "../other"`)
+
+	_, err = LoadLayoutWithFallback(afero.NewMemMapFs())
+	assert.ErrorContains(t, err, "invalid layout from environment")
+}
+
+func TestSkipResourceMatches(t *testing.T) {
+	t.Setenv(EnvSkipResources, "resources-v*.json,[invalid")
+
+	l, err := WithSkipListsFromEnv(configLayout{cfg: LayoutConfig{SkipResources: []string{"leases.json"}}})
+	require.NoError(t, err)
+	assert.True(t, l.SkipResourceMatches("leases.json"))
+	assert.True(t, l.SkipResourceMatches("cluster-resources/resources-v2.json"), "base name is matched")
+	assert.True(t, l.SkipResourceMatches("[invalid"), "invalid pattern is compared literally")
+	assert.False(t, l.SkipResourceMatches("resources.json"), "configured list replaces defaults")
+	assert.False(t, l.SkipResourceMatches("pods.json"))
+
+	assert.True(t, defaultLayout{}.SkipResourceMatches("groups.json"))
+}
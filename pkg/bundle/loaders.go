@@ -0,0 +1,119 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// loadClusterResources loads resources from the cluster resources directory
+// for given name without extension, e.g. `nodes` or `pods/kube-system`.
+func loadClusterResources(b Bundle, name string) (*unstructured.UnstructuredList, error) {
+	path := filepath.Join(b.Layout().ClusterResources(), name)
+	list, err := LoadResources(b, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load resources from %q: %w", path, err)
+	}
+	return list, nil
+}
+
+// loadNamespacedResources loads resources from all per namespace files stored
+// in given cluster resources directory, e.g. `pods/default.json`. Files with
+// collection errors are skipped. If the directory doesn't exist the returned
+// error wraps fs.ErrNotExist.
+func loadNamespacedResources(b Bundle, dir string) (*unstructured.UnstructuredList, error) {
+	path := filepath.Join(b.Layout().ClusterResources(), dir)
+	entries, err := afero.ReadDir(b, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %q: %w", path, err)
+	}
+
+	names := make([]string, 0, len(entries))
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.Contains(entry.Name(), "-errors.") {
+			continue
+		}
+		name := entry.Name()
+		// Chunks are loaded together by the name of the chunked file.
+		if base, _, ok := SplitChunkPath(name); ok {
+			name = base
+		}
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	result := &unstructured.UnstructuredList{}
+	for _, name := range names {
+		list, err := LoadResourcesFromFile(b, filepath.Join(path, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load resources from %q: %w", filepath.Join(path, name), err)
+		}
+		result.Items = append(result.Items, list.Items...)
+	}
+	return result, nil
+}
+
+// isNotCollected checks if the error was caused by missing data in the bundle.
+func isNotCollected(err error) bool {
+	return errors.Is(err, fs.ErrNotExist)
+}
+
+// convertList converts all items from the list to the typed objects.
+func convertList[T any](list *unstructured.UnstructuredList) ([]T, error) {
+	items := make([]T, 0, len(list.Items))
+	for i := range list.Items {
+		var item T
+		if err := runtime.DefaultUnstructuredConverter.
+			FromUnstructured(list.Items[i].UnstructuredContent(), &item); err != nil {
+			return nil, fmt.Errorf("failed to convert %q: %w", list.Items[i].GetName(), err)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// loadOptionalNamespaced loads resources stored per namespace in the
+// directory. Nil is returned when the resources were not collected.
+func loadOptionalNamespaced[T any](b Bundle, dir string) ([]T, error) {
+	list, err := loadNamespacedResources(b, dir)
+	if isNotCollected(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return convertList[T](list)
+}
+
+// loadOptionalCluster loads cluster scoped resources stored in the file with
+// given name. Nil is returned when the resources were not collected.
+func loadOptionalCluster[T any](b Bundle, name string) ([]T, error) {
+	list, err := loadClusterResources(b, name)
+	if isNotCollected(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return convertList[T](list)
+}
+
+func loadNodes(b Bundle) ([]corev1.Node, error) {
+	list, err := loadClusterResources(b, "nodes")
+	if err != nil {
+		return nil, err
+	}
+	return convertList[corev1.Node](list)
+}
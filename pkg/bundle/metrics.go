@@ -0,0 +1,199 @@
+package bundle
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// MetricsGroupVersion is the API group version of the resource metrics
+	// API served by metrics-server.
+	MetricsGroupVersion = "metrics.k8s.io/v1beta1"
+
+	// capturedMetricsDir is the bundle directory with captured resource
+	// metrics. The metrics API responses are stored as `nodes.json` and
+	// `pods.json`, e.g. the output of
+	// `kubectl get --raw /apis/metrics.k8s.io/v1beta1/nodes`. The output of
+	// `kubectl top nodes` and `kubectl top pods -A --containers` can be stored
+	// as `top-nodes.txt` and `top-pods.txt` instead.
+	capturedMetricsDir = "metrics"
+
+	// capturedMetricsWindow is the window reported for metrics converted
+	// from the `kubectl top` output, which doesn't contain it.
+	capturedMetricsWindow = "30s"
+)
+
+// LoadNodeMetrics returns NodeMetrics of the `metrics.k8s.io` API captured in
+// the bundle sorted by name. Empty list is returned when the metrics weren't
+// collected.
+func LoadNodeMetrics(b Bundle) (*unstructured.UnstructuredList, error) {
+	return loadMetrics(b, "nodes", "top-nodes.txt", parseTopNodes)
+}
+
+// LoadPodMetrics returns PodMetrics of the `metrics.k8s.io` API captured in
+// the bundle sorted by namespace and name. Empty list is returned when the
+// metrics weren't collected.
+func LoadPodMetrics(b Bundle) (*unstructured.UnstructuredList, error) {
+	return loadMetrics(b, "pods", "top-pods.txt", parseTopPods)
+}
+
+func loadMetrics(
+	b Bundle, resource, topFile string, parseTop func([]byte, string) ([]unstructured.Unstructured, error),
+) (*unstructured.UnstructuredList, error) {
+	list, err := LoadResources(b, filepath.Join(capturedMetricsDir, resource))
+	if isNotCollected(err) {
+		list, err = loadTopOutput(b, filepath.Join(capturedMetricsDir, topFile), parseTop)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(list.Items, func(i, j int) bool {
+		if list.Items[i].GetNamespace() != list.Items[j].GetNamespace() {
+			return list.Items[i].GetNamespace() < list.Items[j].GetNamespace()
+		}
+		return list.Items[i].GetName() < list.Items[j].GetName()
+	})
+	return list, nil
+}
+
+func loadTopOutput(
+	b Bundle, path string, parse func([]byte, string) ([]unstructured.Unstructured, error),
+) (*unstructured.UnstructuredList, error) {
+	list := &unstructured.UnstructuredList{}
+	data, err := afero.ReadFile(b, path)
+	if isNotCollected(err) {
+		return list, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	collectedAt, err := DetectCollectionTime(b)
+	if err != nil {
+		return nil, err
+	}
+	if list.Items, err = parse(data, collectedAt.UTC().Format(time.RFC3339)); err != nil {
+		return nil, fmt.Errorf("failed to parse %q: %w", path, err)
+	}
+	return list, nil
+}
+
+// topRows parses the `kubectl top` table output to rows indexed by the
+// column header.
+func topRows(data []byte) ([]map[string]string, error) {
+	var header []string
+	var rows []map[string]string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if header == nil {
+			header = fields
+			continue
+		}
+		if len(fields) != len(header) {
+			return nil, fmt.Errorf("row %q doesn't match header %q", scanner.Text(), header)
+		}
+		row := map[string]string{}
+		for i, name := range header {
+			row[name] = fields[i]
+		}
+		rows = append(rows, row)
+	}
+	return rows, scanner.Err()
+}
+
+// topUsage converts the CPU and memory columns to the metrics API usage.
+func topUsage(row map[string]string) (map[string]any, error) {
+	usage := map[string]any{}
+	for column, name := range map[string]string{"CPU(cores)": "cpu", "MEMORY(bytes)": "memory"} {
+		q, err := resource.ParseQuantity(row[column])
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %q: %w", column, row[column], err)
+		}
+		usage[name] = q.String()
+	}
+	return usage, nil
+}
+
+func newMetricsObject(kind, namespace, name, timestamp string) unstructured.Unstructured {
+	u := unstructured.Unstructured{Object: map[string]any{
+		"timestamp": timestamp,
+		"window":    capturedMetricsWindow,
+	}}
+	u.SetAPIVersion(MetricsGroupVersion)
+	u.SetKind(kind)
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	return u
+}
+
+// parseTopNodes parses the `kubectl top nodes` output.
+func parseTopNodes(data []byte, timestamp string) ([]unstructured.Unstructured, error) {
+	rows, err := topRows(data)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]unstructured.Unstructured, 0, len(rows))
+	for _, row := range rows {
+		usage, err := topUsage(row)
+		if err != nil {
+			return nil, err
+		}
+		node := newMetricsObject("NodeMetrics", "", row["NAME"], timestamp)
+		node.Object["usage"] = usage
+		items = append(items, node)
+	}
+	return items, nil
+}
+
+// parseTopPods parses the `kubectl top pods` output. With the `--containers`
+// flag the output contains usage per container in the `POD` and `NAME`
+// columns, otherwise the pod usage is reported as a single container with
+// the pod name. Pods of the output without the `NAMESPACE` column are in the
+// `default` namespace.
+func parseTopPods(data []byte, timestamp string) ([]unstructured.Unstructured, error) {
+	rows, err := topRows(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []unstructured.Unstructured
+	var containers [][]any
+	index := map[string]int{}
+	for _, row := range rows {
+		usage, err := topUsage(row)
+		if err != nil {
+			return nil, err
+		}
+		namespace := valueOrDefault(row["NAMESPACE"], "default")
+		pod, container := row["NAME"], row["NAME"]
+		if name, ok := row["POD"]; ok {
+			pod = name
+		}
+
+		key := namespace + "/" + pod
+		if _, ok := index[key]; !ok {
+			index[key] = len(items)
+			items = append(items, newMetricsObject("PodMetrics", namespace, pod, timestamp))
+			containers = append(containers, nil)
+		}
+		i := index[key]
+		containers[i] = append(containers[i], map[string]any{"name": container, "usage": usage})
+		items[i].Object["containers"] = containers[i]
+	}
+	return items, nil
+}
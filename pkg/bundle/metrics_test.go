@@ -0,0 +1,78 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestLoadNodeMetrics_TopOutput(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"version.yaml": "collectedAt: 2024-01-01T10:00:00Z\n",
+		"metrics/top-nodes.txt": `NAME       CPU(cores)   CPU%   MEMORY(bytes)   MEMORY%
+worker-1   250m         12%    1024Mi          26%
+control    1            50%    2Gi             52%
+`,
+	})
+
+	list, err := LoadNodeMetrics(b)
+	require.NoError(t, err)
+	require.Len(t, list.Items, 2)
+
+	node := list.Items[1].Object
+	assert.Equal(t, "NodeMetrics", list.Items[1].GetKind())
+	assert.Equal(t, "worker-1", list.Items[1].GetName())
+	assert.Equal(t, "2024-01-01T10:00:00Z", node["timestamp"])
+	cpu, _, _ := unstructured.NestedString(node, "usage", "cpu")
+	memory, _, _ := unstructured.NestedString(node, "usage", "memory")
+	assert.Equal(t, "250m", cpu)
+	assert.Equal(t, "1Gi", memory)
+}
+
+func TestLoadPodMetrics_TopOutput(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"metrics/top-pods.txt": `NAMESPACE     POD       NAME      CPU(cores)   MEMORY(bytes)
+kube-system   coredns   coredns   3m           15Mi
+default       web       app       10m          64Mi
+default       web       sidecar   1m           8Mi
+`,
+	})
+
+	list, err := LoadPodMetrics(b)
+	require.NoError(t, err)
+	require.Len(t, list.Items, 2)
+	assert.Equal(t, "default", list.Items[0].GetNamespace())
+	assert.Equal(t, "web", list.Items[0].GetName())
+
+	containers, _, _ := unstructured.NestedSlice(list.Items[0].Object, "containers")
+	require.Len(t, containers, 2)
+	name, _, _ := unstructured.NestedString(containers[1].(map[string]any), "name")
+	assert.Equal(t, "sidecar", name)
+}
+
+func TestLoadPodMetrics_MetricsAPI(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"metrics/pods.json": `{"kind": "PodMetricsList", "apiVersion": "metrics.k8s.io/v1beta1", "items": [
+			{"metadata": {"name": "web", "namespace": "default"}, "timestamp": "2024-01-01T10:00:00Z", "window": "15s",
+			 "containers": [{"name": "app", "usage": {"cpu": "10m", "memory": "64Mi"}}]}
+		]}`,
+		"metrics/top-pods.txt": "NAME CPU(cores) MEMORY(bytes)\nother 1m 1Mi\n",
+	})
+
+	list, err := LoadPodMetrics(b)
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "web", list.Items[0].GetName())
+}
+
+func TestLoadNodeMetrics_NotCollected(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/nodes.json": `{"items": []}`,
+	})
+
+	list, err := LoadNodeMetrics(b)
+	require.NoError(t, err)
+	assert.Empty(t, list.Items)
+}
@@ -0,0 +1,91 @@
+package bundle
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// DetectCollectedNamespaces compares namespaces listed in the cluster
+// resources `namespaces.json` with the namespaces that have resource files
+// in the namespaced cluster resources directories, e.g. `pods/<namespace>.json`.
+// Namespaces with files in every directory are returned as collected and
+// namespaces missing files in any directory as partial, e.g. namespaces that
+// were not selected by the collector spec. Absence of resources in partial
+// namespaces doesn't mean they didn't exist in the cluster. When namespaces
+// weren't collected, namespaces with files are returned as collected.
+func DetectCollectedNamespaces(b Bundle) ([]string, []string, error) {
+	dirs, files, err := namespacedResourceFiles(b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var namespaces []string
+	list, err := loadClusterResources(b, "namespaces")
+	switch {
+	case isNotCollected(err):
+		for namespace := range files {
+			namespaces = append(namespaces, namespace)
+		}
+	case err != nil:
+		return nil, nil, err
+	default:
+		for i := range list.Items {
+			namespaces = append(namespaces, list.Items[i].GetName())
+		}
+	}
+	sort.Strings(namespaces)
+
+	var collected, partial []string
+	for _, namespace := range namespaces {
+		if len(dirs) > 0 && files[namespace] == len(dirs) {
+			collected = append(collected, namespace)
+		} else {
+			partial = append(partial, namespace)
+		}
+	}
+	return collected, partial, nil
+}
+
+// namespacedResourceFiles returns the namespaced cluster resources directories
+// and the number of those directories with a file per namespace.
+func namespacedResourceFiles(b Bundle) ([]string, map[string]int, error) {
+	entries, err := afero.ReadDir(b, b.Layout().ClusterResources())
+	if isNotCollected(err) {
+		return nil, map[string]int{}, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read cluster resources: %w", err)
+	}
+
+	var dirs []string
+	files := map[string]int{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dirEntries, err := afero.ReadDir(b, filepath.Join(b.Layout().ClusterResources(), entry.Name()))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		seen := map[string]bool{}
+		for _, file := range dirEntries {
+			// Namespace names cannot contain dots, the rest of name are
+			// extensions and chunk suffixes.
+			namespace, _, _ := strings.Cut(file.Name(), ".")
+			if file.IsDir() || strings.HasSuffix(namespace, "-errors") || seen[namespace] {
+				continue
+			}
+			seen[namespace] = true
+			files[namespace]++
+		}
+		if len(seen) > 0 {
+			dirs = append(dirs, entry.Name())
+		}
+	}
+	return dirs, files, nil
+}
@@ -0,0 +1,43 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectCollectedNamespaces(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/namespaces.json": `{"items": [
+  {"metadata": {"name": "default"}},
+  {"metadata": {"name": "kube-system"}},
+  {"metadata": {"name": "monitoring"}},
+  {"metadata": {"name": "team-a"}}
+]}`,
+		"cluster-resources/pods/default.json":           `{"items": []}`,
+		"cluster-resources/pods/kube-system.json.part1": `{"items": []}`,
+		"cluster-resources/pods/kube-system.json.part2": `{"items": []}`,
+		"cluster-resources/pods/monitoring.json":        `{"items": []}`,
+		"cluster-resources/pods/team-a-errors.json":     `["forbidden"]`,
+		"cluster-resources/services/default.json":       `{"items": []}`,
+		"cluster-resources/services/kube-system.json":   `{"items": []}`,
+		"cluster-resources/nodes.json":                  `{"items": []}`,
+	})
+
+	collected, partial, err := DetectCollectedNamespaces(b)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default", "kube-system"}, collected)
+	assert.Equal(t, []string{"monitoring", "team-a"}, partial)
+}
+
+func TestDetectCollectedNamespaces_NamespacesNotCollected(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/pods/default.json": `{"items": []}`,
+	})
+
+	collected, partial, err := DetectCollectedNamespaces(b)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default"}, collected)
+	assert.Empty(t, partial)
+}
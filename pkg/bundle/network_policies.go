@@ -0,0 +1,137 @@
+package bundle
+
+import (
+	"sort"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NetworkPolicyInfo contains summary of a single NetworkPolicy.
+type NetworkPolicyInfo struct {
+	Name        string                    `json:"name"`
+	PodSelector string                    `json:"podSelector"`
+	PolicyTypes []networkingv1.PolicyType `json:"policyTypes"`
+	// DefaultDenyIngress is set for policies that select all pods and don't
+	// allow any ingress traffic.
+	DefaultDenyIngress bool `json:"defaultDenyIngress"`
+	// DefaultDenyEgress is set for policies that select all pods and don't
+	// allow any egress traffic.
+	DefaultDenyEgress bool `json:"defaultDenyEgress"`
+}
+
+// NetworkPolicyCoverageReport groups namespaces by network isolation.
+type NetworkPolicyCoverageReport struct {
+	// DefaultDeny are namespaces with a default deny ingress or egress policy.
+	DefaultDeny []string `json:"defaultDeny"`
+	// Partial are namespaces with policies that select only some pods or
+	// allow some traffic for all pods.
+	Partial []string `json:"partial"`
+	// None are namespaces without any policy.
+	None []string `json:"none"`
+}
+
+// ListNetworkPolicies returns network policies from the bundle grouped by
+// namespace. Empty result is returned when no policies were collected.
+func ListNetworkPolicies(b Bundle) (map[string][]NetworkPolicyInfo, error) {
+	list, err := loadNamespacedResources(b, "network-policy")
+	if isNotCollected(err) {
+		return map[string][]NetworkPolicyInfo{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err := convertList[networkingv1.NetworkPolicy](list)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string][]NetworkPolicyInfo{}
+	for i := range policies {
+		p := &policies[i]
+		policyTypes := effectivePolicyTypes(&p.Spec)
+		selectsAll := len(p.Spec.PodSelector.MatchLabels) == 0 && len(p.Spec.PodSelector.MatchExpressions) == 0
+		result[p.GetNamespace()] = append(result[p.GetNamespace()], NetworkPolicyInfo{
+			Name:        p.GetName(),
+			PodSelector: metav1.FormatLabelSelector(&p.Spec.PodSelector),
+			PolicyTypes: policyTypes,
+			DefaultDenyIngress: selectsAll && len(p.Spec.Ingress) == 0 &&
+				hasPolicyType(policyTypes, networkingv1.PolicyTypeIngress),
+			DefaultDenyEgress: selectsAll && len(p.Spec.Egress) == 0 &&
+				hasPolicyType(policyTypes, networkingv1.PolicyTypeEgress),
+		})
+	}
+	return result, nil
+}
+
+// NetworkPolicyCoverage reports which namespaces have a default deny policy,
+// only partial policies or no policies at all. Namespaces without policies are
+// reported only when the namespaces were collected.
+func NetworkPolicyCoverage(b Bundle) (*NetworkPolicyCoverageReport, error) {
+	policies, err := ListNetworkPolicies(b)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := map[string]bool{}
+	list, err := loadClusterResources(b, "namespaces")
+	switch {
+	case err == nil:
+		for i := range list.Items {
+			namespaces[list.Items[i].GetName()] = true
+		}
+	case !isNotCollected(err):
+		return nil, err
+	}
+	for namespace := range policies {
+		namespaces[namespace] = true
+	}
+
+	coverage := &NetworkPolicyCoverageReport{DefaultDeny: []string{}, Partial: []string{}, None: []string{}}
+	for namespace := range namespaces {
+		switch {
+		case len(policies[namespace]) == 0:
+			coverage.None = append(coverage.None, namespace)
+		case hasDefaultDeny(policies[namespace]):
+			coverage.DefaultDeny = append(coverage.DefaultDeny, namespace)
+		default:
+			coverage.Partial = append(coverage.Partial, namespace)
+		}
+	}
+	sort.Strings(coverage.DefaultDeny)
+	sort.Strings(coverage.Partial)
+	sort.Strings(coverage.None)
+	return coverage, nil
+}
+
+// effectivePolicyTypes returns policy types applied by the API server when
+// the policy doesn't specify them.
+func effectivePolicyTypes(spec *networkingv1.NetworkPolicySpec) []networkingv1.PolicyType {
+	if len(spec.PolicyTypes) > 0 {
+		return spec.PolicyTypes
+	}
+	policyTypes := []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+	if len(spec.Egress) > 0 {
+		policyTypes = append(policyTypes, networkingv1.PolicyTypeEgress)
+	}
+	return policyTypes
+}
+
+func hasPolicyType(policyTypes []networkingv1.PolicyType, policyType networkingv1.PolicyType) bool {
+	for _, t := range policyTypes {
+		if t == policyType {
+			return true
+		}
+	}
+	return false
+}
+
+func hasDefaultDeny(policies []NetworkPolicyInfo) bool {
+	for i := range policies {
+		if policies[i].DefaultDenyIngress || policies[i].DefaultDenyEgress {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+func networkPoliciesBundle(t *testing.T) Bundle {
+	t.Helper()
+
+	return newTestBundle(t, map[string]string{
+		"cluster-resources/namespaces.json": `{"items": [
+			{"metadata": {"name": "secure"}},
+			{"metadata": {"name": "partial"}},
+			{"metadata": {"name": "open"}}
+		]}`,
+		"cluster-resources/network-policy/secure.json": `{"items": [
+			{"metadata": {"name": "default-deny", "namespace": "secure"}, "spec": {"podSelector": {}}},
+			{"metadata": {"name": "allow-web", "namespace": "secure"}, "spec": {
+				"podSelector": {"matchLabels": {"app": "web"}},
+				"ingress": [{"ports": [{"port": 80}]}]
+			}}
+		]}`,
+		"cluster-resources/network-policy/partial.json": `{"items": [
+			{"metadata": {"name": "allow-all", "namespace": "partial"}, "spec": {
+				"podSelector": {}, "ingress": [{}], "policyTypes": ["Ingress"]
+			}}
+		]}`,
+	})
+}
+
+func TestListNetworkPolicies(t *testing.T) {
+	policies, err := ListNetworkPolicies(networkPoliciesBundle(t))
+	require.NoError(t, err)
+	require.Len(t, policies["secure"], 2)
+
+	assert.Equal(t, NetworkPolicyInfo{
+		Name:               "default-deny",
+		PodSelector:        "<none>",
+		PolicyTypes:        []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		DefaultDenyIngress: true,
+	}, policies["secure"][0])
+	assert.Equal(t, "app=web", policies["secure"][1].PodSelector)
+	assert.False(t, policies["secure"][1].DefaultDenyIngress)
+	assert.False(t, policies["partial"][0].DefaultDenyIngress)
+}
+
+func TestNetworkPolicyCoverage(t *testing.T) {
+	coverage, err := NetworkPolicyCoverage(networkPoliciesBundle(t))
+	require.NoError(t, err)
+	assert.Equal(t, &NetworkPolicyCoverageReport{
+		DefaultDeny: []string{"secure"},
+		Partial:     []string{"partial"},
+		None:        []string{"open"},
+	}, coverage)
+}
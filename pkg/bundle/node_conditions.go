@@ -0,0 +1,52 @@
+package bundle
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NodeCondition is a condition of a node at the time of the collection.
+type NodeCondition struct {
+	Type               corev1.NodeConditionType `json:"type"`
+	Status             corev1.ConditionStatus   `json:"status"`
+	Reason             string                   `json:"reason,omitempty"`
+	Message            string                   `json:"message,omitempty"`
+	LastTransitionTime time.Time                `json:"lastTransitionTime"`
+	// Unhealthy is set when the node is under memory, disk or PID pressure,
+	// its network is unavailable or the node is not ready.
+	Unhealthy bool `json:"unhealthy"`
+}
+
+// ListNodeConditions returns conditions of each node from the bundle indexed
+// by node name.
+func ListNodeConditions(b Bundle) (map[string][]NodeCondition, error) {
+	nodes, err := loadNodes(b)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]NodeCondition, len(nodes))
+	for i := range nodes {
+		conditions := []NodeCondition{}
+		for _, c := range nodes[i].Status.Conditions {
+			conditions = append(conditions, NodeCondition{
+				Type:               c.Type,
+				Status:             c.Status,
+				Reason:             c.Reason,
+				Message:            c.Message,
+				LastTransitionTime: c.LastTransitionTime.UTC(),
+				Unhealthy:          isUnhealthyNodeCondition(c),
+			})
+		}
+		result[nodes[i].GetName()] = conditions
+	}
+	return result, nil
+}
+
+func isUnhealthyNodeCondition(c corev1.NodeCondition) bool {
+	if c.Type == corev1.NodeReady {
+		return c.Status != corev1.ConditionTrue
+	}
+	return c.Status == corev1.ConditionTrue
+}
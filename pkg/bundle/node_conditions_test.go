@@ -0,0 +1,48 @@
+package bundle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const nodeConditionsNodes = `{"items": [
+  {"metadata": {"name": "worker-1"}, "status": {"conditions": [
+    {"type": "MemoryPressure", "status": "False", "reason": "KubeletHasSufficientMemory", "lastTransitionTime": "2024-01-01T08:00:00Z"},
+    {"type": "DiskPressure", "status": "True", "reason": "KubeletHasDiskPressure", "message": "kubelet has disk pressure", "lastTransitionTime": "2024-01-01T09:30:00Z"},
+    {"type": "Ready", "status": "True", "reason": "KubeletReady", "lastTransitionTime": "2024-01-01T08:00:00Z"}
+  ]}},
+  {"metadata": {"name": "worker-2"}, "status": {"conditions": [
+    {"type": "Ready", "status": "Unknown", "reason": "NodeStatusUnknown", "lastTransitionTime": "2024-01-01T09:00:00Z"}
+  ]}},
+  {"metadata": {"name": "worker-3"}}
+]}`
+
+func TestListNodeConditions(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/nodes.json": nodeConditionsNodes,
+	})
+
+	conditions, err := ListNodeConditions(b)
+	require.NoError(t, err)
+	require.Len(t, conditions, 3)
+
+	require.Len(t, conditions["worker-1"], 3)
+	assert.False(t, conditions["worker-1"][0].Unhealthy)
+	assert.Equal(t, NodeCondition{
+		Type:               corev1.NodeDiskPressure,
+		Status:             corev1.ConditionTrue,
+		Reason:             "KubeletHasDiskPressure",
+		Message:            "kubelet has disk pressure",
+		LastTransitionTime: time.Date(2024, 1, 1, 9, 30, 0, 0, time.UTC),
+		Unhealthy:          true,
+	}, conditions["worker-1"][1])
+	assert.False(t, conditions["worker-1"][2].Unhealthy)
+
+	require.Len(t, conditions["worker-2"], 1)
+	assert.True(t, conditions["worker-2"][0].Unhealthy, "not ready node")
+	assert.Empty(t, conditions["worker-3"])
+}
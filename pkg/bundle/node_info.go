@@ -0,0 +1,38 @@
+package bundle
+
+// NodeInfo contains system information reported by the node kubelet.
+type NodeInfo struct {
+	Name                    string `json:"name"`
+	OSImage                 string `json:"osImage"`
+	OperatingSystem         string `json:"operatingSystem"`
+	Architecture            string `json:"architecture"`
+	KernelVersion           string `json:"kernelVersion"`
+	KubeletVersion          string `json:"kubeletVersion"`
+	ContainerRuntimeVersion string `json:"containerRuntimeVersion"`
+}
+
+// ListNodeInfo returns system information for each node from the bundle, based
+// on the `.status.nodeInfo` field. The cgroup driver is not part of the node
+// status and can be found only in the kubelet configuration, if collected.
+func ListNodeInfo(b Bundle) ([]NodeInfo, error) {
+	nodes, err := loadNodes(b)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]NodeInfo, 0, len(nodes))
+	for i := range nodes {
+		info := nodes[i].Status.NodeInfo
+		result = append(result, NodeInfo{
+			Name:                    nodes[i].GetName(),
+			OSImage:                 info.OSImage,
+			OperatingSystem:         info.OperatingSystem,
+			Architecture:            info.Architecture,
+			KernelVersion:           info.KernelVersion,
+			KubeletVersion:          info.KubeletVersion,
+			ContainerRuntimeVersion: info.ContainerRuntimeVersion,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,48 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const heterogeneousNodes = `{"items": [
+  {"metadata": {"name": "linux-amd64"}, "status": {"nodeInfo": {
+    "osImage": "Ubuntu 22.04.3 LTS", "operatingSystem": "linux", "architecture": "amd64",
+    "kernelVersion": "5.15.0-91-generic", "kubeletVersion": "v1.28.4",
+    "containerRuntimeVersion": "containerd://1.7.11"}}},
+  {"metadata": {"name": "linux-arm64"}, "status": {"nodeInfo": {
+    "osImage": "Flatcar Container Linux 3602.2.3", "operatingSystem": "linux", "architecture": "arm64",
+    "kernelVersion": "6.1.73-flatcar", "kubeletVersion": "v1.28.4",
+    "containerRuntimeVersion": "containerd://1.7.8"}}},
+  {"metadata": {"name": "windows"}, "status": {"nodeInfo": {
+    "osImage": "Windows Server 2022 Datacenter", "operatingSystem": "windows", "architecture": "amd64",
+    "kernelVersion": "10.0.20348.2159", "kubeletVersion": "v1.28.3",
+    "containerRuntimeVersion": "containerd://1.6.21"}}}
+]}`
+
+func TestListNodeInfo(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/nodes.json": heterogeneousNodes,
+	})
+
+	nodes, err := ListNodeInfo(b)
+	require.NoError(t, err)
+	require.Len(t, nodes, 3)
+	assert.Equal(t, NodeInfo{
+		Name:                    "linux-arm64",
+		OSImage:                 "Flatcar Container Linux 3602.2.3",
+		OperatingSystem:         "linux",
+		Architecture:            "arm64",
+		KernelVersion:           "6.1.73-flatcar",
+		KubeletVersion:          "v1.28.4",
+		ContainerRuntimeVersion: "containerd://1.7.8",
+	}, nodes[1])
+	assert.Equal(t, "windows", nodes[2].OperatingSystem)
+}
+
+func TestListNodeInfo_Missing(t *testing.T) {
+	_, err := ListNodeInfo(newTestBundle(t, nil))
+	assert.Error(t, err)
+}
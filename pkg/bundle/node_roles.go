@@ -0,0 +1,53 @@
+package bundle
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// controlPlaneRoleKeys are label and taint keys identifying control plane
+// nodes. The `master` key is used by clusters older than 1.24.
+func controlPlaneRoleKeys() []string {
+	return []string{"node-role.kubernetes.io/control-plane", "node-role.kubernetes.io/master"}
+}
+
+// ClassifyNodes returns sorted names of control plane and worker nodes.
+// Nodes are classified by the `node-role.kubernetes.io/control-plane` or
+// `node-role.kubernetes.io/master` label and, as a fallback for nodes
+// without the labels, by taints with the same keys. The numbers of nodes are the lengths of
+// the returned lists.
+func ClassifyNodes(b Bundle) (controlPlane, workers []string, err error) {
+	nodes, err := loadNodes(b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	controlPlane, workers = []string{}, []string{}
+	for i := range nodes {
+		if isControlPlaneNode(&nodes[i]) {
+			controlPlane = append(controlPlane, nodes[i].GetName())
+		} else {
+			workers = append(workers, nodes[i].GetName())
+		}
+	}
+	sort.Strings(controlPlane)
+	sort.Strings(workers)
+	return controlPlane, workers, nil
+}
+
+func isControlPlaneNode(node *corev1.Node) bool {
+	for _, key := range controlPlaneRoleKeys() {
+		if _, ok := node.Labels[key]; ok {
+			return true
+		}
+	}
+	for _, taint := range node.Spec.Taints {
+		for _, key := range controlPlaneRoleKeys() {
+			if taint.Key == key {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyNodes(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/nodes.json": `{"items": [
+  {"metadata": {"name": "cp-2", "labels": {"node-role.kubernetes.io/control-plane": ""}}},
+  {"metadata": {"name": "cp-1", "labels": {"node-role.kubernetes.io/master": ""}}},
+  {"metadata": {"name": "cp-3"}, "spec": {"taints": [{"key": "node-role.kubernetes.io/control-plane", "effect": "NoSchedule"}]}},
+  {"metadata": {"name": "worker-2", "labels": {"node-role.kubernetes.io/worker": ""}}},
+  {"metadata": {"name": "worker-1"}, "spec": {"taints": [{"key": "dedicated", "value": "gpu", "effect": "NoSchedule"}]}}
+]}`,
+	})
+
+	controlPlane, workers, err := ClassifyNodes(b)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cp-1", "cp-2", "cp-3"}, controlPlane)
+	assert.Equal(t, []string{"worker-1", "worker-2"}, workers)
+}
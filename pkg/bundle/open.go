@@ -0,0 +1,166 @@
+package bundle
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// readChunkSize is the size of chunks in which files are read, the context is
+// checked between chunks.
+const readChunkSize = 64 * 1024
+
+// resourceExtensions defines the order in which are file extensions probed
+// when opening a resource file without known extension.
+func resourceExtensions() []string {
+	return withCompressedExtensions(resourceFormats.extensions())
+}
+
+// withCompressedExtensions returns the extensions followed by their `.gz`
+// variants.
+func withCompressedExtensions(extensions []string) []string {
+	result := append([]string{}, extensions...)
+	for _, ext := range extensions {
+		result = append(result, ext+".gz")
+	}
+	return result
+}
+
+// OpenResource reads the first existing file for given path without extension.
+// The extensions are probed in order `.json`, `.yaml`, `.yml`, extensions of
+// formats added by RegisterResourceFormat and then their `.gz` variants.
+// Compressed files are transparently decompressed. The path of the file that
+// was read is returned together with data.
+func OpenResource(b Bundle, basePathWithoutExt string) ([]byte, string, error) {
+	path, err := FindFile(b, basePathWithoutExt, resourceFormats.extensions()...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	data, err := readFile(b, path)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, path, nil
+}
+
+// FindFile returns path of the first existing file for given path without
+// extension. The extensions are probed in given order and then their `.gz`
+// variants, e.g. `.log` and `.log.gz` for logs. Files split to chunks are
+// found by their first chunk. The returned file can be read by
+// ReadFileContext.
+func FindFile(b afero.Fs, basePathWithoutExt string, extensions ...string) (string, error) {
+	extensions = withCompressedExtensions(extensions)
+	for _, ext := range extensions {
+		path := basePathWithoutExt + ext
+		exists, err := afero.Exists(b, path)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			chunks, err := ChunkPaths(b, path)
+			if err != nil {
+				return "", err
+			}
+			if len(chunks) == 0 {
+				continue
+			}
+		}
+		return path, nil
+	}
+
+	return "", fmt.Errorf(
+		"file %q not found with any of extensions %v: %w",
+		basePathWithoutExt, extensions, fs.ErrNotExist,
+	)
+}
+
+// readFile reads file from the bundle and decompresses it if the file has
+// `.gz` extension, see ReadFileContext.
+func readFile(b afero.Fs, path string) ([]byte, error) {
+	return ReadFileContext(context.Background(), b, path)
+}
+
+// ReadFileContext reads file from the bundle and decompresses it if the file
+// has `.gz` extension. Files split to chunks, e.g. `pods.json.part1`, are read
+// when the file itself doesn't exist. Decompressed content is cached when
+// the bundle is wrapped by DecompressedCacheFs. Reading is aborted when
+// the context is done.
+func ReadFileContext(ctx context.Context, b afero.Fs, path string) ([]byte, error) {
+	if cache := cacheOf(b); cache != nil && strings.HasSuffix(path, ".gz") {
+		return cache.decompressed(path, func() ([]byte, error) {
+			return readFileUncached(ctx, b, path)
+		})
+	}
+	return readFileUncached(ctx, b, path)
+}
+
+func readFileUncached(ctx context.Context, b afero.Fs, path string) ([]byte, error) {
+	data, err := readWithContext(ctx, b, path)
+	if errors.Is(err, fs.ErrNotExist) {
+		chunks, ok, chunksErr := readChunks(b, path)
+		if chunksErr != nil {
+			return nil, chunksErr
+		}
+		if ok {
+			data, err = chunks, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return decompress(data, path)
+}
+
+// readWithContext reads the whole file in chunks of readChunkSize and aborts
+// reading when the context is done.
+func readWithContext(ctx context.Context, b afero.Fs, path string) ([]byte, error) {
+	f, err := b.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	buf := &bytes.Buffer{}
+	chunk := make([]byte, readChunkSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		n, err := f.Read(chunk)
+		buf.Write(chunk[:n])
+		if errors.Is(err, io.EOF) {
+			return buf.Bytes(), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// decompress decompresses data if the path has `.gz` extension.
+func decompress(data []byte, path string) ([]byte, error) {
+	if !strings.HasSuffix(path, ".gz") {
+		return data, nil
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress %q: %w", path, err)
+	}
+	defer gz.Close()
+
+	return io.ReadAll(gz)
+}
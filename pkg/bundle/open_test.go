@@ -0,0 +1,87 @@
+package bundle
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gzipString(t *testing.T, s string) string {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	w := gzip.NewWriter(buf)
+	_, err := w.Write([]byte(s))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.String()
+}
+
+func TestOpenResource(t *testing.T) {
+	for _, ext := range resourceExtensions() {
+		t.Run(ext, func(t *testing.T) {
+			content := "content" + ext
+			stored := content
+			if strings.HasSuffix(ext, ".gz") {
+				stored = gzipString(t, content)
+			}
+			b := newTestBundle(t, map[string]string{"dir/file" + ext: stored})
+
+			data, path, err := OpenResource(b, "dir/file")
+			require.NoError(t, err)
+			assert.Equal(t, "dir/file"+ext, path)
+			assert.Equal(t, content, string(data))
+		})
+	}
+}
+
+func TestOpenResource_Order(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"file.yaml": "yaml",
+		"file.json": "json",
+	})
+
+	data, path, err := OpenResource(b, "file")
+	require.NoError(t, err)
+	assert.Equal(t, "file.json", path)
+	assert.Equal(t, "json", string(data))
+}
+
+func TestOpenResource_NotFound(t *testing.T) {
+	b := newTestBundle(t, map[string]string{"file.txt": ""})
+
+	_, _, err := OpenResource(b, "file")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestLoadResources_YAMLGzip(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"pods/default.yaml.gz": gzipString(t, "- kind: Pod\n  metadata:\n    name: foo\n"),
+	})
+
+	list, err := LoadResources(b, "pods/default")
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "foo", list.Items[0].GetName())
+}
+
+func TestReadFileContext(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"logs/app.log.gz": gzipString(t, "decompressed"),
+	})
+
+	data, err := ReadFileContext(context.Background(), b, "logs/app.log.gz")
+	require.NoError(t, err)
+	assert.Equal(t, "decompressed", string(data))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = ReadFileContext(ctx, b, "logs/app.log.gz")
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,132 @@
+package bundle
+
+import (
+	"encoding/json"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// openShiftGroupSuffix is the suffix of API groups served by OpenShift.
+const openShiftGroupSuffix = "openshift.io"
+
+// OpenShiftResource is a resource of the OpenShift API stored in cluster
+// resources of bundles collected from OpenShift clusters.
+type OpenShiftResource struct {
+	// Paths are patterns of files relative to cluster resources, e.g.
+	// `routes/*.json` for resources stored per namespace.
+	Paths []string
+	GVK   schema.GroupVersionKind
+	// Resource is the plural name of the resource, e.g. `routes`.
+	Resource   string
+	Namespaced bool
+}
+
+func openShiftResources() []OpenShiftResource {
+	return []OpenShiftResource{
+		{
+			Paths:      []string{"routes/*.json", "custom-resources/routes.route.openshift.io/*.json"},
+			GVK:        schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1", Kind: "Route"},
+			Resource:   "routes",
+			Namespaced: true,
+		},
+		{
+			Paths:      []string{"deploymentconfigs/*.json", "custom-resources/deploymentconfigs.apps.openshift.io/*.json"},
+			GVK:        schema.GroupVersionKind{Group: "apps.openshift.io", Version: "v1", Kind: "DeploymentConfig"},
+			Resource:   "deploymentconfigs",
+			Namespaced: true,
+		},
+		{
+			Paths: []string{
+				"security-context-constraints.json",
+				"custom-resources/securitycontextconstraints.security.openshift.io.json",
+			},
+			GVK:      schema.GroupVersionKind{Group: "security.openshift.io", Version: "v1", Kind: "SecurityContextConstraints"},
+			Resource: "securitycontextconstraints",
+		},
+	}
+}
+
+// openShiftLayout extends layout with paths of OpenShift resources.
+type openShiftLayout struct {
+	Layout
+}
+
+// WithOpenShiftResources returns layout that knows paths of the OpenShift
+// resources, see OpenShiftResources.
+func WithOpenShiftResources(l Layout) Layout {
+	return openShiftLayout{Layout: l}
+}
+
+// OpenShiftResources returns OpenShift resources stored in the bundle with
+// the layout. Empty list is returned for layouts of bundles that weren't
+// collected from OpenShift.
+func OpenShiftResources(l Layout) []OpenShiftResource {
+	switch l := l.(type) {
+	case openShiftLayout:
+		return openShiftResources()
+	case envSkipListsLayout:
+		return OpenShiftResources(l.Layout)
+	}
+	return nil
+}
+
+// IsOpenShift checks if the bundle was collected from an OpenShift cluster.
+// The API groups and resources from the discovery client are checked for
+// `openshift.io` groups, as well as cluster resources collected for them,
+// e.g. `custom-resources/clusterversions.config.openshift.io`.
+func IsOpenShift(fs afero.Fs, l Layout) bool {
+	for _, name := range []string{"groups.json", "resources.json"} {
+		if hasOpenShiftGroup(fs, filepath.Join(l.ClusterResources(), name)) {
+			return true
+		}
+	}
+	return hasOpenShiftResources(fs, l.ClusterResources())
+}
+
+// hasOpenShiftGroup checks the `groups.json` file with the API groups or
+// the `resources.json` file with the API resources lists.
+func hasOpenShiftGroup(fs afero.Fs, path string) bool {
+	data, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return false
+	}
+
+	var entries []struct {
+		Name         string `json:"name"`
+		GroupVersion string `json:"groupVersion"`
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		group := schema.FromAPIVersionAndKind(entry.GroupVersion, "").Group
+		if isOpenShiftGroup(entry.Name) || isOpenShiftGroup(group) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasOpenShiftResources(b afero.Fs, root string) bool {
+	found := false
+	_ = afero.Walk(b, root, func(path string, info fs.FileInfo, err error) error {
+		if err != nil || found {
+			return nil
+		}
+		name := info.Name()
+		if !info.IsDir() {
+			name = strings.TrimSuffix(name, filepath.Ext(name))
+		}
+		found = strings.HasSuffix(name, "."+openShiftGroupSuffix)
+		return nil
+	})
+	return found
+}
+
+func isOpenShiftGroup(group string) bool {
+	return group == openShiftGroupSuffix || strings.HasSuffix(group, "."+openShiftGroupSuffix)
+}
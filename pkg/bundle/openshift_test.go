@@ -0,0 +1,76 @@
+package bundle
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// openShiftBundleFiles is modeled on a bundle collected from an OpenShift cluster.
+func openShiftBundleFiles() map[string]string {
+	return map[string]string{
+		"cluster-info/cluster_version.json": `{"info": {"gitVersion": "v1.27.6+f67aeb3"}, "string": "v1.27.6+f67aeb3"}`,
+		"cluster-resources/groups.json": `[
+			{"name": "apps", "versions": [{"groupVersion": "apps/v1", "version": "v1"}]},
+			{"name": "route.openshift.io", "versions": [{"groupVersion": "route.openshift.io/v1", "version": "v1"}]}
+		]`,
+		"cluster-resources/namespaces.json": `{"kind": "NamespaceList", "apiVersion": "v1", "items": [
+			{"metadata": {"name": "openshift-console"}}
+		]}`,
+		"cluster-resources/routes/openshift-console.json": `{"items": [
+			{"metadata": {"name": "console", "namespace": "openshift-console"}, "spec": {"host": "console.apps.example.com"}}
+		]}`,
+		"cluster-resources/deploymentconfigs/openshift-console.json": `{"items": []}`,
+		"cluster-resources/security-context-constraints.json": `{"items": [
+			{"metadata": {"name": "restricted-v2"}, "allowPrivilegedContainer": false}
+		]}`,
+		"cluster-resources/custom-resources/clusterversions.config.openshift.io.json": `[]`,
+	}
+}
+
+func TestIsOpenShift(t *testing.T) {
+	assert.True(t, IsOpenShift(newTestBundle(t, openShiftBundleFiles()), defaultLayout{}))
+	assert.True(t, IsOpenShift(newTestBundle(t, map[string]string{
+		"cluster-resources/resources.json": `[{"groupVersion": "security.openshift.io/v1", "resources": []}]`,
+	}), defaultLayout{}), "resources from discovery")
+	assert.True(t, IsOpenShift(newTestBundle(t, map[string]string{
+		"cluster-resources/custom-resources/routes.route.openshift.io/default.json": `[]`,
+	}), defaultLayout{}), "collected custom resources")
+	assert.False(t, IsOpenShift(newTestBundle(t, map[string]string{
+		"cluster-resources/groups.json":           `[{"name": "apps"}, {"name": "example.com"}]`,
+		"cluster-resources/pods/openshift.json":   `[]`,
+		"cluster-resources/custom-resources.json": `[]`,
+	}), defaultLayout{}))
+}
+
+func TestOpenShiftResources(t *testing.T) {
+	assert.Empty(t, OpenShiftResources(defaultLayout{}))
+
+	l, err := WithSkipListsFromEnv(WithOpenShiftResources(defaultLayout{}))
+	require.NoError(t, err)
+	assert.Equal(t, "cluster-resources", l.ClusterResources())
+
+	kinds := []string{}
+	for _, r := range OpenShiftResources(l) {
+		kinds = append(kinds, r.GVK.Kind)
+	}
+	assert.Equal(t, []string{"Route", "DeploymentConfig", "SecurityContextConstraints"}, kinds)
+}
+
+func TestNew_OpenShift(t *testing.T) {
+	setupHome(t)
+	dir := t.TempDir()
+	for path, data := range openShiftBundleFiles() {
+		writeConfig(t, filepath.Join(dir, path), data)
+	}
+
+	b, err := New(dir)
+	require.NoError(t, err)
+	require.NotEmpty(t, OpenShiftResources(b.Layout()))
+	assert.Equal(t,
+		schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1", Kind: "Route"},
+		OpenShiftResources(b.Layout())[0].GVK)
+}
@@ -0,0 +1,39 @@
+package bundle
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ListOrphanPods returns pods from all namespaces without owner references,
+// i.e. bare pods that are not managed by any controller, which are often left
+// over from manual debugging. Static pods are owned by their node and are not
+// reported. Empty list is returned when pods were not collected.
+func ListOrphanPods(b Bundle) ([]*corev1.Pod, error) {
+	list, err := loadNamespacedResources(b, "pods")
+	if isNotCollected(err) {
+		return []*corev1.Pod{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	pods, err := convertList[corev1.Pod](list)
+	if err != nil {
+		return nil, err
+	}
+
+	orphans := []*corev1.Pod{}
+	for i := range pods {
+		if len(pods[i].OwnerReferences) == 0 {
+			orphans = append(orphans, &pods[i])
+		}
+	}
+	sort.SliceStable(orphans, func(i, j int) bool {
+		if orphans[i].Namespace != orphans[j].Namespace {
+			return orphans[i].Namespace < orphans[j].Namespace
+		}
+		return orphans[i].Name < orphans[j].Name
+	})
+	return orphans, nil
+}
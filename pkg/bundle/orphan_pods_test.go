@@ -0,0 +1,29 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListOrphanPods(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/pods/default.json": `{"items": [
+  {"metadata": {"name": "web-7b4c6d8f9-abcde", "namespace": "default", "ownerReferences": [{"kind": "ReplicaSet", "name": "web-7b4c6d8f9"}]}},
+  {"metadata": {"name": "netshoot", "namespace": "default"}},
+  {"metadata": {"name": "debug", "namespace": "default"}}
+]}`,
+		"cluster-resources/pods/kube-system.json": `{"items": [
+  {"metadata": {"name": "kube-apiserver-cp-1", "namespace": "kube-system", "ownerReferences": [{"kind": "Node", "name": "cp-1"}]}}
+]}`,
+	})
+
+	pods, err := ListOrphanPods(b)
+	require.NoError(t, err)
+	names := []string{}
+	for _, pod := range pods {
+		names = append(names, pod.Namespace+"/"+pod.Name)
+	}
+	assert.Equal(t, []string{"default/debug", "default/netshoot"}, names)
+}
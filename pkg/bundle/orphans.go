@@ -0,0 +1,84 @@
+package bundle
+
+import (
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// knownFilePatterns match files outside of the layout directories that are
+// used by troubleshoot-live:
+//   - outputs of the exec collector, `<collector>/<namespace>/<pod>/<name>-stdout.txt`,
+//   - kubelet pod logs, `.../<namespace>_<pod>_<uid>/<container>/<restart>.log`,
+//   - captured port-forward responses and OpenAPI v3 documents served by
+//     the proxy.
+func knownFilePatterns() []*regexp.Regexp {
+	return []*regexp.Regexp{
+		regexp.MustCompile(`^[^/]+/[^/]+/[^/]+/[^/]+-(stdout|stderr)\.txt$`),
+		regexp.MustCompile(`(^|/)[^/_]+_[^/_]+_[^/]+/[^/]+/\d+\.log$`),
+		regexp.MustCompile(`^port-forward/`),
+		regexp.MustCompile(`^openapi/v3/`),
+		regexp.MustCompile(`^metrics/(nodes|pods)\.|^metrics/top-(nodes|pods)\.txt$`),
+	}
+}
+
+// FindOrphanFiles returns files from the bundle that aren't handled by any
+// loader, i.e. files outside of the layout directories that neither contain
+// collection metadata nor match any of the known file patterns. Hidden files
+// are ignored. The list helps to discover new kinds of bundle files that are
+// silently ignored.
+func FindOrphanFiles(b Bundle) ([]string, error) {
+	l := b.Layout()
+	knownDirs := []string{
+		l.ClusterInfo(), l.ClusterResources(), l.PodLogs(), l.ConfigMaps(), l.Secrets(),
+	}
+	known := map[string]bool{}
+	for _, name := range collectionMetadataFiles() {
+		known[name] = true
+	}
+	known[l.Analysis()] = true
+	patterns := knownFilePatterns()
+
+	orphans := []string{}
+	err := afero.Walk(b, ".", func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != "." && strings.HasPrefix(info.Name(), ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			for _, dir := range knownDirs {
+				if path == filepath.Clean(dir) {
+					return filepath.SkipDir
+				}
+			}
+			return nil
+		}
+
+		slashPath := filepath.ToSlash(path)
+		if known[slashPath] {
+			return nil
+		}
+		for _, pattern := range patterns {
+			if pattern.MatchString(slashPath) {
+				return nil
+			}
+		}
+		orphans = append(orphans, path)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(orphans)
+	return orphans, nil
+}
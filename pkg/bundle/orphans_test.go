@@ -0,0 +1,34 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindOrphanFiles(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"version.yaml":                                            "apiVersion: troubleshoot.sh/v1beta2",
+		"cluster-resources/pods/default.json":                     `{"items": []}`,
+		"cluster-info/cluster_version.json":                       `{}`,
+		"pod-logs/default/web-0-app.log":                          "logs",
+		"configmaps/default/settings.json":                        `{}`,
+		"mysql/default/mysql-0/version-stdout.txt":                "8.0",
+		"host-logs/var/log/pods/default_web-0_0a1b2c3d/app/0.log": "logs",
+		"port-forward/default/app-0/9090/metrics":                 "up 1",
+		"openapi/v3/index.json":                                   `{}`,
+		"metrics/top-nodes.txt":                                   "NAME CPU(cores) MEMORY(bytes)",
+		".troubleshoot-live/config.yaml":                          "",
+		"analysis.json":                                           "[]",
+		"host-collectors/run-host/sysctl.txt":                     "vm.max_map_count = 262144",
+		"custom-collector/output.bin":                             "data",
+	})
+
+	orphans, err := FindOrphanFiles(b)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"custom-collector/output.bin",
+		"host-collectors/run-host/sysctl.txt",
+	}, orphans)
+}
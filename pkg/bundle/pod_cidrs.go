@@ -0,0 +1,77 @@
+package bundle
+
+import (
+	"net/netip"
+	"slices"
+	"sort"
+)
+
+// PodCIDROverlap is a pod CIDR of a node overlapping with a pod CIDR of
+// another node.
+type PodCIDROverlap struct {
+	Node      string `json:"node"`
+	CIDR      string `json:"cidr"`
+	OtherNode string `json:"otherNode"`
+	OtherCIDR string `json:"otherCIDR"`
+}
+
+// ListNodePodCIDRs returns pod CIDRs allocated to each node from the bundle
+// indexed by node name. The CIDRs are read from `spec.podCIDRs` and from
+// the legacy `spec.podCIDR`, which is used by nodes of older clusters. Nodes
+// without allocated CIDRs are included with an empty list.
+func ListNodePodCIDRs(b Bundle) (map[string][]string, error) {
+	nodes, err := loadNodes(b)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]string, len(nodes))
+	for i := range nodes {
+		cidrs := append([]string{}, nodes[i].Spec.PodCIDRs...)
+		if podCIDR := nodes[i].Spec.PodCIDR; podCIDR != "" && !slices.Contains(cidrs, podCIDR) {
+			cidrs = append(cidrs, podCIDR)
+		}
+		result[nodes[i].GetName()] = cidrs
+	}
+	return result, nil
+}
+
+// FindPodCIDROverlaps returns pod CIDRs from ListNodePodCIDRs that overlap
+// across nodes. The node controller allocates distinct CIDRs, so overlaps
+// point to a misconfigured IPAM and pods with conflicting IPs. Each overlap
+// is reported once, ordered by node names. Invalid CIDRs are ignored.
+func FindPodCIDROverlaps(cidrs map[string][]string) []PodCIDROverlap {
+	names := make([]string, 0, len(cidrs))
+	for name := range cidrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	overlaps := []PodCIDROverlap{}
+	for i, node := range names {
+		for _, other := range names[i+1:] {
+			for _, cidr := range cidrs[node] {
+				for _, otherCIDR := range cidrs[other] {
+					if cidrsOverlap(cidr, otherCIDR) {
+						overlaps = append(overlaps, PodCIDROverlap{
+							Node: node, CIDR: cidr, OtherNode: other, OtherCIDR: otherCIDR,
+						})
+					}
+				}
+			}
+		}
+	}
+	return overlaps
+}
+
+func cidrsOverlap(a, b string) bool {
+	prefixA, err := netip.ParsePrefix(a)
+	if err != nil {
+		return false
+	}
+	prefixB, err := netip.ParsePrefix(b)
+	if err != nil {
+		return false
+	}
+	return prefixA.Overlaps(prefixB)
+}
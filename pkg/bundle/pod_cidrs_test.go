@@ -0,0 +1,50 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func podCIDRsBundleFiles() map[string]string {
+	return map[string]string{
+		"cluster-resources/nodes.json": `{"items": [
+			{"metadata": {"name": "node-1"}, "spec": {"podCIDR": "10.244.0.0/24", "podCIDRs": ["10.244.0.0/24", "fd00:10:244::/64"]}},
+			{"metadata": {"name": "node-2"}, "spec": {"podCIDRs": ["10.244.1.0/24"]}},
+			{"metadata": {"name": "node-3"}, "spec": {"podCIDR": "10.244.1.128/25"}},
+			{"metadata": {"name": "node-4"}, "spec": {}}
+		]}`,
+	}
+}
+
+func TestListNodePodCIDRs(t *testing.T) {
+	cidrs, err := ListNodePodCIDRs(newTestBundle(t, podCIDRsBundleFiles()))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]string{
+		"node-1": {"10.244.0.0/24", "fd00:10:244::/64"},
+		"node-2": {"10.244.1.0/24"},
+		"node-3": {"10.244.1.128/25"},
+		"node-4": {},
+	}, cidrs)
+}
+
+func TestListNodePodCIDRs_NotCollected(t *testing.T) {
+	_, err := ListNodePodCIDRs(newTestBundle(t, map[string]string{}))
+	assert.True(t, isNotCollected(err))
+}
+
+func TestFindPodCIDROverlaps(t *testing.T) {
+	cidrs, err := ListNodePodCIDRs(newTestBundle(t, podCIDRsBundleFiles()))
+	require.NoError(t, err)
+
+	assert.Equal(t, []PodCIDROverlap{
+		{Node: "node-2", CIDR: "10.244.1.0/24", OtherNode: "node-3", OtherCIDR: "10.244.1.128/25"},
+	}, FindPodCIDROverlaps(cidrs))
+
+	assert.Empty(t, FindPodCIDROverlaps(map[string][]string{
+		"node-1": {"invalid"},
+		"node-2": {"invalid"},
+	}))
+}
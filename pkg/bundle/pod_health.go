@@ -0,0 +1,117 @@
+package bundle
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PodHealth describes a pod that is not ready.
+type PodHealth struct {
+	Namespace string          `json:"namespace"`
+	Name      string          `json:"name"`
+	Phase     corev1.PodPhase `json:"phase"`
+	// Reason is the most specific known reason why the pod isn't ready, e.g.
+	// `CrashLoopBackOff`, `ImagePullBackOff`, `Unschedulable` or the pod phase.
+	Reason  string `json:"reason"`
+	Message string `json:"message,omitempty"`
+	// Restarts is the sum of restarts of all containers.
+	Restarts int32 `json:"restarts"`
+	// LastTerminationReason is the reason of the last termination of
+	// the most restarted container, e.g. `OOMKilled`.
+	LastTerminationReason string `json:"lastTerminationReason,omitempty"`
+}
+
+// ListUnhealthyPods returns pods across all namespaces that are not ready.
+// Succeeded pods, e.g. of completed jobs, are not reported. Nil is returned
+// when pods were not collected.
+func ListUnhealthyPods(b Bundle) ([]PodHealth, error) {
+	pods, err := loadOptionalNamespaced[corev1.Pod](b, "pods")
+	if err != nil || pods == nil {
+		return nil, err
+	}
+
+	result := []PodHealth{}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase == corev1.PodSucceeded || isPodReady(pod) {
+			continue
+		}
+		result = append(result, podHealth(pod))
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func podHealth(pod *corev1.Pod) PodHealth {
+	health := PodHealth{
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+		Phase:     pod.Status.Phase,
+		Reason:    string(pod.Status.Phase),
+		Message:   pod.Status.Message,
+	}
+	if health.Reason == "" {
+		health.Reason = string(corev1.PodUnknown)
+	}
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	var mostRestarted *corev1.ContainerStatus
+	for i := range statuses {
+		status := &statuses[i]
+		health.Restarts += status.RestartCount
+		if status.LastTerminationState.Terminated != nil &&
+			(mostRestarted == nil || status.RestartCount > mostRestarted.RestartCount) {
+			mostRestarted = status
+		}
+	}
+	if mostRestarted != nil {
+		health.LastTerminationReason = mostRestarted.LastTerminationState.Terminated.Reason
+	}
+
+	reason, message := notReadyReason(pod, statuses)
+	if reason != "" {
+		health.Reason, health.Message = reason, message
+	}
+	return health
+}
+
+// notReadyReason returns reason from the first waiting or failed container,
+// the pod status or the pod scheduling condition.
+func notReadyReason(pod *corev1.Pod, statuses []corev1.ContainerStatus) (string, string) {
+	for i := range statuses {
+		state := statuses[i].State
+		if state.Waiting != nil && state.Waiting.Reason != "" {
+			return state.Waiting.Reason, state.Waiting.Message
+		}
+		if state.Terminated != nil && state.Terminated.ExitCode != 0 {
+			return state.Terminated.Reason, state.Terminated.Message
+		}
+	}
+
+	if pod.Status.Reason != "" {
+		return pod.Status.Reason, pod.Status.Message
+	}
+
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse {
+			return c.Reason, c.Message
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,86 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const unhealthyPods = `{"items": [
+  {
+    "metadata": {"name": "healthy", "namespace": "default"},
+    "status": {"phase": "Running", "conditions": [{"type": "Ready", "status": "True"}]}
+  },
+  {
+    "metadata": {"name": "job-xyz", "namespace": "default"},
+    "status": {"phase": "Succeeded", "conditions": [{"type": "Ready", "status": "False"}]}
+  },
+  {
+    "metadata": {"name": "crashloop", "namespace": "default"},
+    "status": {
+      "phase": "Running",
+      "conditions": [{"type": "Ready", "status": "False"}],
+      "containerStatuses": [
+        {
+          "name": "sidecar", "restartCount": 1,
+          "state": {"running": {}},
+          "lastState": {"terminated": {"reason": "Error", "exitCode": 1}}
+        },
+        {
+          "name": "app", "restartCount": 7,
+          "state": {"waiting": {"reason": "CrashLoopBackOff", "message": "back-off 5m0s restarting failed container"}},
+          "lastState": {"terminated": {"reason": "OOMKilled", "exitCode": 137}}
+        }
+      ]
+    }
+  },
+  {
+    "metadata": {"name": "imagepull", "namespace": "apps"},
+    "status": {
+      "phase": "Pending",
+      "conditions": [{"type": "Ready", "status": "False"}],
+      "containerStatuses": [
+        {"name": "app", "state": {"waiting": {"reason": "ImagePullBackOff", "message": "Back-off pulling image"}}}
+      ]
+    }
+  },
+  {
+    "metadata": {"name": "unschedulable", "namespace": "apps"},
+    "status": {
+      "phase": "Pending",
+      "conditions": [{"type": "PodScheduled", "status": "False", "reason": "Unschedulable", "message": "0/3 nodes are available"}]
+    }
+  }
+]}`
+
+func TestListUnhealthyPods(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/pods/default.json": unhealthyPods,
+	})
+
+	pods, err := ListUnhealthyPods(b)
+	require.NoError(t, err)
+	assert.Equal(t, []PodHealth{
+		{
+			Namespace: "apps", Name: "imagepull", Phase: corev1.PodPending,
+			Reason: "ImagePullBackOff", Message: "Back-off pulling image",
+		},
+		{
+			Namespace: "apps", Name: "unschedulable", Phase: corev1.PodPending,
+			Reason: "Unschedulable", Message: "0/3 nodes are available",
+		},
+		{
+			Namespace: "default", Name: "crashloop", Phase: corev1.PodRunning,
+			Reason: "CrashLoopBackOff", Message: "back-off 5m0s restarting failed container",
+			Restarts: 8, LastTerminationReason: "OOMKilled",
+		},
+	}, pods)
+}
+
+func TestListUnhealthyPods_NotCollected(t *testing.T) {
+	pods, err := ListUnhealthyPods(newTestBundle(t, map[string]string{}))
+	require.NoError(t, err)
+	assert.Nil(t, pods)
+}
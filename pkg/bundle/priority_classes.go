@@ -0,0 +1,45 @@
+package bundle
+
+import (
+	schedulingv1 "k8s.io/api/scheduling/v1"
+)
+
+func loadPriorityClasses(b Bundle) ([]schedulingv1.PriorityClass, error) {
+	list, err := loadClusterResources(b, "priorityclasses")
+	if err != nil {
+		return nil, err
+	}
+	return convertList[schedulingv1.PriorityClass](list)
+}
+
+// ListPriorityClasses returns value for each priority class from the bundle.
+func ListPriorityClasses(b Bundle) (map[string]int32, error) {
+	priorityClasses, err := loadPriorityClasses(b)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]int32, len(priorityClasses))
+	for i := range priorityClasses {
+		result[priorityClasses[i].GetName()] = priorityClasses[i].Value
+	}
+	return result, nil
+}
+
+// GlobalDefaultPriorityClasses returns names of priority classes marked as
+// global default, which are assigned to pods without priority class name.
+// A valid cluster has at most one.
+func GlobalDefaultPriorityClasses(b Bundle) ([]string, error) {
+	priorityClasses, err := loadPriorityClasses(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var defaults []string
+	for i := range priorityClasses {
+		if priorityClasses[i].GlobalDefault {
+			defaults = append(defaults, priorityClasses[i].GetName())
+		}
+	}
+	return defaults, nil
+}
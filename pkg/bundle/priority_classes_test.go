@@ -0,0 +1,34 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const priorityClasses = `{"items": [
+  {"metadata": {"name": "system-cluster-critical"}, "value": 2000000000},
+  {"metadata": {"name": "system-node-critical"}, "value": 2000001000},
+  {"metadata": {"name": "high-priority"}, "value": 1000000, "preemptionPolicy": "PreemptLowerPriority"},
+  {"metadata": {"name": "default-priority"}, "value": 1000, "globalDefault": true}
+]}`
+
+func TestListPriorityClasses(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/priorityclasses.json": priorityClasses,
+	})
+
+	values, err := ListPriorityClasses(b)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int32{
+		"system-cluster-critical": 2000000000,
+		"system-node-critical":    2000001000,
+		"high-priority":           1000000,
+		"default-priority":        1000,
+	}, values)
+
+	defaults, err := GlobalDefaultPriorityClasses(b)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default-priority"}, defaults)
+}
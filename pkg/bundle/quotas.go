@@ -0,0 +1,84 @@
+package bundle
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ResourceQuotaInfo contains hard limits and usage of a single ResourceQuota.
+type ResourceQuotaInfo struct {
+	Name string              `json:"name"`
+	Hard corev1.ResourceList `json:"hard"`
+	Used corev1.ResourceList `json:"used"`
+}
+
+// Exhausted returns resources for which the usage reached the hard limit.
+func (i ResourceQuotaInfo) Exhausted() []corev1.ResourceName {
+	var exhausted []corev1.ResourceName
+	for name, hard := range i.Hard {
+		if used, ok := i.Used[name]; ok && used.Cmp(hard) >= 0 {
+			exhausted = append(exhausted, name)
+		}
+	}
+	return exhausted
+}
+
+// LimitRangeInfo contains limits from a single LimitRange.
+type LimitRangeInfo struct {
+	Name   string                  `json:"name"`
+	Limits []corev1.LimitRangeItem `json:"limits"`
+}
+
+// ListResourceQuotas returns resource quotas from the bundle grouped by
+// namespace. Empty result is returned when no quotas were collected.
+func ListResourceQuotas(b Bundle) (map[string][]ResourceQuotaInfo, error) {
+	list, err := loadNamespacedResources(b, "resource-quota")
+	if isNotCollected(err) {
+		return map[string][]ResourceQuotaInfo{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	quotas, err := convertList[corev1.ResourceQuota](list)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string][]ResourceQuotaInfo{}
+	for i := range quotas {
+		q := &quotas[i]
+		result[q.GetNamespace()] = append(result[q.GetNamespace()], ResourceQuotaInfo{
+			Name: q.GetName(),
+			Hard: q.Status.Hard,
+			Used: q.Status.Used,
+		})
+	}
+	return result, nil
+}
+
+// ListLimitRanges returns limit ranges from the bundle grouped by namespace.
+// Empty result is returned when no limit ranges were collected.
+func ListLimitRanges(b Bundle) (map[string][]LimitRangeInfo, error) {
+	list, err := loadNamespacedResources(b, "limitranges")
+	if isNotCollected(err) {
+		return map[string][]LimitRangeInfo{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	limitRanges, err := convertList[corev1.LimitRange](list)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string][]LimitRangeInfo{}
+	for i := range limitRanges {
+		lr := &limitRanges[i]
+		result[lr.GetNamespace()] = append(result[lr.GetNamespace()], LimitRangeInfo{
+			Name:   lr.GetName(),
+			Limits: lr.Spec.Limits,
+		})
+	}
+	return result, nil
+}
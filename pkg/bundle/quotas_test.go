@@ -0,0 +1,53 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestListResourceQuotas(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/resource-quota/team-a.json": `{"items": [{
+			"metadata": {"name": "compute", "namespace": "team-a"},
+			"status": {"hard": {"pods": "10", "requests.cpu": "4"}, "used": {"pods": "10", "requests.cpu": "1500m"}}
+		}]}`,
+		"cluster-resources/resource-quota/team-b.json": `{"items": [{
+			"metadata": {"name": "objects", "namespace": "team-b"},
+			"status": {"hard": {"configmaps": "5"}, "used": {"configmaps": "2"}}
+		}]}`,
+		"cluster-resources/resource-quota/team-c-errors.json": `["forbidden"]`,
+	})
+
+	quotas, err := ListResourceQuotas(b)
+	require.NoError(t, err)
+	require.Len(t, quotas, 2)
+	require.Len(t, quotas["team-a"], 1)
+	assert.Equal(t, "compute", quotas["team-a"][0].Name)
+	assert.True(t, resource.MustParse("1500m").Equal(quotas["team-a"][0].Used[corev1.ResourceRequestsCPU]))
+	assert.Equal(t, []corev1.ResourceName{corev1.ResourcePods}, quotas["team-a"][0].Exhausted())
+	assert.Empty(t, quotas["team-b"][0].Exhausted())
+}
+
+func TestListLimitRanges(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/limitranges/team-a.json": `{"items": [{
+			"metadata": {"name": "defaults", "namespace": "team-a"},
+			"spec": {"limits": [{"type": "Container", "default": {"memory": "512Mi"}}]}
+		}]}`,
+	})
+
+	limitRanges, err := ListLimitRanges(b)
+	require.NoError(t, err)
+	require.Len(t, limitRanges["team-a"], 1)
+	assert.Equal(t, corev1.LimitTypeContainer, limitRanges["team-a"][0].Limits[0].Type)
+}
+
+func TestListResourceQuotas_NotCollected(t *testing.T) {
+	quotas, err := ListResourceQuotas(newTestBundle(t, nil))
+	require.NoError(t, err)
+	assert.Empty(t, quotas)
+}
@@ -0,0 +1,197 @@
+package bundle
+
+import (
+	"encoding/json"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+// serviceAccountUsernamePrefix is the prefix of service account user names,
+// e.g. `system:serviceaccount:kube-system:default`.
+const serviceAccountUsernamePrefix = "system:serviceaccount:"
+
+// PermissionBinding is a binding of the subject to a role.
+type PermissionBinding struct {
+	// Kind is either `RoleBinding` or `ClusterRoleBinding`.
+	Kind      string         `json:"kind"`
+	Namespace string         `json:"namespace,omitempty"`
+	Name      string         `json:"name"`
+	RoleRef   rbacv1.RoleRef `json:"roleRef"`
+	// RoleFound is false when the referenced role is missing in the bundle.
+	RoleFound bool                `json:"roleFound"`
+	Rules     []rbacv1.PolicyRule `json:"rules,omitempty"`
+}
+
+// PermissionReport describes permissions of a subject in a namespace
+// reconstructed from the RBAC resources stored in the bundle.
+type PermissionReport struct {
+	Namespace string              `json:"namespace"`
+	Subject   rbacv1.Subject      `json:"subject"`
+	Bindings  []PermissionBinding `json:"bindings"`
+	// Rules are the effective rules of the subject in the namespace, the
+	// union of rules of all bound roles.
+	Rules []rbacv1.PolicyRule `json:"rules"`
+}
+
+// ResolveSubjectPermissions returns RoleBindings from the namespace and
+// ClusterRoleBindings that reference the subject together with the rules
+// of the bound roles. The subject is a user or group name or a service
+// account, either as `system:serviceaccount:<namespace>:<name>` or as a name
+// of a service account from the namespace. Bindings to groups of service
+// accounts, e.g. `system:serviceaccounts`, are included for service
+// accounts. RBAC resources that weren't collected are ignored.
+func ResolveSubjectPermissions(b Bundle, namespace, subject string) (*PermissionReport, error) {
+	rbac, err := loadRBAC(b)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &PermissionReport{
+		Namespace: namespace,
+		Subject:   rbacv1.Subject{Kind: rbacv1.UserKind, Name: subject},
+		Bindings:  []PermissionBinding{},
+		Rules:     []rbacv1.PolicyRule{},
+	}
+	if saNamespace, saName, ok := splitServiceAccountUsername(subject); ok {
+		report.Subject = rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Namespace: saNamespace, Name: saName}
+	} else if rbac.hasServiceAccount(namespace, subject) {
+		report.Subject = rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Namespace: namespace, Name: subject}
+	}
+
+	for i := range rbac.roleBindings {
+		binding := &rbac.roleBindings[i]
+		if binding.Namespace != namespace || !bindsSubject(binding.Subjects, report.Subject, binding.Namespace) {
+			continue
+		}
+		report.Bindings = append(report.Bindings, rbac.resolveBinding(
+			"RoleBinding", binding.Namespace, binding.Name, binding.RoleRef))
+	}
+	for i := range rbac.clusterRoleBindings {
+		binding := &rbac.clusterRoleBindings[i]
+		if !bindsSubject(binding.Subjects, report.Subject, "") {
+			continue
+		}
+		report.Bindings = append(report.Bindings, rbac.resolveBinding(
+			"ClusterRoleBinding", "", binding.Name, binding.RoleRef))
+	}
+
+	seen := map[string]bool{}
+	for _, binding := range report.Bindings {
+		for _, rule := range binding.Rules {
+			key, err := json.Marshal(rule)
+			if err != nil {
+				return nil, err
+			}
+			if !seen[string(key)] {
+				seen[string(key)] = true
+				report.Rules = append(report.Rules, rule)
+			}
+		}
+	}
+	return report, nil
+}
+
+// bindsSubject checks if the binding subjects match the subject. Namespace
+// of service accounts without namespace defaults to the binding namespace.
+func bindsSubject(subjects []rbacv1.Subject, subject rbacv1.Subject, bindingNamespace string) bool {
+	for _, s := range subjects {
+		switch {
+		case s.Kind == rbacv1.ServiceAccountKind && subject.Kind == rbacv1.ServiceAccountKind:
+			if s.Name == subject.Name && valueOrDefault(s.Namespace, bindingNamespace) == subject.Namespace {
+				return true
+			}
+		case s.Kind == rbacv1.GroupKind && subject.Kind == rbacv1.ServiceAccountKind:
+			if isServiceAccountGroup(s.Name, subject.Namespace) {
+				return true
+			}
+		case s.Kind == subject.Kind, s.Kind == rbacv1.GroupKind && subject.Kind == rbacv1.UserKind:
+			// The subject name can be either a user or a group.
+			if s.Name == subject.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func splitServiceAccountUsername(username string) (namespace, name string, ok bool) {
+	parts := strings.Split(strings.TrimPrefix(username, serviceAccountUsernamePrefix), ":")
+	if !strings.HasPrefix(username, serviceAccountUsernamePrefix) || len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// isServiceAccountGroup checks if the group contains service accounts from
+// the namespace.
+func isServiceAccountGroup(group, namespace string) bool {
+	return group == "system:serviceaccounts" ||
+		group == "system:serviceaccounts:"+namespace ||
+		group == "system:authenticated"
+}
+
+// rbacResources are the RBAC resources collected in the bundle.
+type rbacResources struct {
+	serviceAccounts     map[string]bool
+	roles               map[string][]rbacv1.PolicyRule
+	clusterRoles        map[string][]rbacv1.PolicyRule
+	roleBindings        []rbacv1.RoleBinding
+	clusterRoleBindings []rbacv1.ClusterRoleBinding
+}
+
+func (r *rbacResources) hasServiceAccount(namespace, name string) bool {
+	return r.serviceAccounts[namespace+"/"+name]
+}
+
+func (r *rbacResources) resolveBinding(kind, namespace, name string, ref rbacv1.RoleRef) PermissionBinding {
+	binding := PermissionBinding{Kind: kind, Namespace: namespace, Name: name, RoleRef: ref}
+	switch ref.Kind {
+	case "ClusterRole":
+		binding.Rules, binding.RoleFound = r.clusterRoles[ref.Name]
+	case "Role":
+		binding.Rules, binding.RoleFound = r.roles[namespace+"/"+ref.Name]
+	}
+	return binding
+}
+
+func loadRBAC(b Bundle) (*rbacResources, error) {
+	r := &rbacResources{
+		serviceAccounts: map[string]bool{},
+		roles:           map[string][]rbacv1.PolicyRule{},
+		clusterRoles:    map[string][]rbacv1.PolicyRule{},
+	}
+
+	serviceAccounts, err := loadOptionalNamespaced[corev1.ServiceAccount](b, "serviceaccounts")
+	if err != nil {
+		return nil, err
+	}
+	for i := range serviceAccounts {
+		r.serviceAccounts[serviceAccounts[i].Namespace+"/"+serviceAccounts[i].Name] = true
+	}
+
+	roles, err := loadOptionalNamespaced[rbacv1.Role](b, "roles")
+	if err != nil {
+		return nil, err
+	}
+	for i := range roles {
+		r.roles[roles[i].Namespace+"/"+roles[i].Name] = roles[i].Rules
+	}
+
+	clusterRoles, err := loadOptionalCluster[rbacv1.ClusterRole](b, "clusterroles")
+	if err != nil {
+		return nil, err
+	}
+	for i := range clusterRoles {
+		r.clusterRoles[clusterRoles[i].Name] = clusterRoles[i].Rules
+	}
+
+	if r.roleBindings, err = loadOptionalNamespaced[rbacv1.RoleBinding](b, "rolebindings"); err != nil {
+		return nil, err
+	}
+	if r.clusterRoleBindings, err = loadOptionalCluster[rbacv1.ClusterRoleBinding](b, "clusterrolebindings"); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
@@ -0,0 +1,108 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+// rbacBundleFiles binds the `app` service account to a Role and to
+// a ClusterRole in its namespace, to a ClusterRole cluster-wide via the
+// service accounts group, and references a Role that wasn't collected.
+func rbacBundleFiles() map[string]string {
+	return map[string]string{
+		"cluster-resources/serviceaccounts/default.json": `{"items": [{"metadata": {"name": "app", "namespace": "default"}}]}`,
+		"cluster-resources/roles/default.json": `{"items": [
+			{"metadata": {"name": "config-reader", "namespace": "default"},
+			 "rules": [{"apiGroups": [""], "resources": ["configmaps"], "verbs": ["get", "list"]}]}
+		]}`,
+		"cluster-resources/rolebindings/default.json": `{"items": [
+			{"metadata": {"name": "app-config", "namespace": "default"},
+			 "subjects": [{"kind": "ServiceAccount", "name": "app"}],
+			 "roleRef": {"apiGroup": "rbac.authorization.k8s.io", "kind": "Role", "name": "config-reader"}},
+			{"metadata": {"name": "app-view", "namespace": "default"},
+			 "subjects": [{"kind": "ServiceAccount", "name": "app", "namespace": "default"}],
+			 "roleRef": {"apiGroup": "rbac.authorization.k8s.io", "kind": "ClusterRole", "name": "view"}},
+			{"metadata": {"name": "app-deleted", "namespace": "default"},
+			 "subjects": [{"kind": "ServiceAccount", "name": "app"}],
+			 "roleRef": {"apiGroup": "rbac.authorization.k8s.io", "kind": "Role", "name": "deleted"}},
+			{"metadata": {"name": "other", "namespace": "default"},
+			 "subjects": [{"kind": "ServiceAccount", "name": "other"}],
+			 "roleRef": {"apiGroup": "rbac.authorization.k8s.io", "kind": "ClusterRole", "name": "admin"}}
+		]}`,
+		"cluster-resources/rolebindings/kube-system.json": `{"items": [
+			{"metadata": {"name": "app", "namespace": "kube-system"},
+			 "subjects": [{"kind": "ServiceAccount", "name": "app", "namespace": "default"}],
+			 "roleRef": {"apiGroup": "rbac.authorization.k8s.io", "kind": "ClusterRole", "name": "admin"}}
+		]}`,
+		"cluster-resources/clusterroles.json": `{"items": [
+			{"metadata": {"name": "view"},
+			 "rules": [{"apiGroups": [""], "resources": ["pods"], "verbs": ["get", "list", "watch"]},
+			           {"apiGroups": [""], "resources": ["configmaps"], "verbs": ["get", "list"]}]},
+			{"metadata": {"name": "discovery"},
+			 "rules": [{"nonResourceURLs": ["/api", "/apis"], "verbs": ["get"]}]},
+			{"metadata": {"name": "admin"}, "rules": [{"apiGroups": ["*"], "resources": ["*"], "verbs": ["*"]}]}
+		]}`,
+		"cluster-resources/clusterrolebindings.json": `{"items": [
+			{"metadata": {"name": "discovery"},
+			 "subjects": [{"kind": "Group", "name": "system:serviceaccounts"}],
+			 "roleRef": {"apiGroup": "rbac.authorization.k8s.io", "kind": "ClusterRole", "name": "discovery"}},
+			{"metadata": {"name": "admins"},
+			 "subjects": [{"kind": "User", "name": "jane"}, {"kind": "Group", "name": "admins"}],
+			 "roleRef": {"apiGroup": "rbac.authorization.k8s.io", "kind": "ClusterRole", "name": "admin"}}
+		]}`,
+	}
+}
+
+func TestResolveSubjectPermissions(t *testing.T) {
+	b := newTestBundle(t, rbacBundleFiles())
+
+	report, err := ResolveSubjectPermissions(b, "default", "app")
+	require.NoError(t, err)
+	assert.Equal(t, rbacv1.Subject{Kind: "ServiceAccount", Namespace: "default", Name: "app"}, report.Subject)
+
+	names := []string{}
+	for _, binding := range report.Bindings {
+		names = append(names, binding.Kind+" "+binding.Name)
+	}
+	assert.Equal(t, []string{
+		"RoleBinding app-config", "RoleBinding app-view", "RoleBinding app-deleted", "ClusterRoleBinding discovery",
+	}, names)
+	assert.False(t, report.Bindings[2].RoleFound)
+	assert.Empty(t, report.Bindings[2].Rules)
+
+	assert.Equal(t, []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get", "list"}},
+		{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "list", "watch"}},
+		{NonResourceURLs: []string{"/api", "/apis"}, Verbs: []string{"get"}},
+	}, report.Rules)
+
+	report, err = ResolveSubjectPermissions(b, "kube-system", "system:serviceaccount:default:app")
+	require.NoError(t, err)
+	require.Len(t, report.Bindings, 2)
+	assert.Equal(t, "admin", report.Bindings[0].RoleRef.Name)
+}
+
+func TestResolveSubjectPermissions_UserAndGroup(t *testing.T) {
+	b := newTestBundle(t, rbacBundleFiles())
+
+	for _, subject := range []string{"jane", "admins"} {
+		report, err := ResolveSubjectPermissions(b, "default", subject)
+		require.NoError(t, err)
+		require.Len(t, report.Bindings, 1, subject)
+		assert.Equal(t, "admins", report.Bindings[0].Name)
+	}
+
+	report, err := ResolveSubjectPermissions(b, "default", "nobody")
+	require.NoError(t, err)
+	assert.Empty(t, report.Bindings)
+	assert.Empty(t, report.Rules)
+}
+
+func TestResolveSubjectPermissions_NotCollected(t *testing.T) {
+	report, err := ResolveSubjectPermissions(newTestBundle(t, map[string]string{}), "default", "app")
+	require.NoError(t, err)
+	assert.Empty(t, report.Bindings)
+}
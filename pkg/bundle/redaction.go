@@ -0,0 +1,97 @@
+package bundle
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/afero"
+)
+
+const (
+	// redactionMask is the value troubleshoot replaces redacted data with.
+	redactionMask = "***HIDDEN***"
+
+	// redactionReportFile is the file with the list of redactions applied to
+	// the bundle, grouped by redactor and by file.
+	redactionReportFile = "redaction-report.json"
+
+	// maxRedactionSampledFiles limits number of files that are scanned for the
+	// redaction mask.
+	maxRedactionSampledFiles = 200
+)
+
+type redactionReport struct {
+	ByRedactor map[string][]json.RawMessage `json:"byRedactor"`
+}
+
+// DetectRedaction checks if the bundle was collected with redaction. The list
+// of redactors that were applied is available only if the bundle contains the
+// redaction report. Otherwise a sample of bundle files is checked for
+// the redaction mask and no redactor names are returned.
+func DetectRedaction(b Bundle) (bool, []string, error) {
+	data, err := afero.ReadFile(b, redactionReportFile)
+	switch {
+	case err == nil:
+		report := redactionReport{}
+		if err := json.Unmarshal(data, &report); err != nil {
+			return false, nil, fmt.Errorf("failed to parse %q: %w", redactionReportFile, err)
+		}
+		redactors := make([]string, 0, len(report.ByRedactor))
+		for name := range report.ByRedactor {
+			redactors = append(redactors, name)
+		}
+		sort.Strings(redactors)
+		return len(redactors) > 0, redactors, nil
+	case !isNotCollected(err):
+		return false, nil, err
+	}
+
+	redacted, err := containsRedactionMask(b)
+	return redacted, nil, err
+}
+
+// errStopWalk is used for terminating the afero.Walk, which doesn't support
+// fs.SkipAll.
+var errStopWalk = errors.New("stop walk")
+
+func containsRedactionMask(b Bundle) (bool, error) {
+	sampled := 0
+	found := false
+	err := afero.Walk(b, ".", func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		// Logs are not redacted by default redactors, skip potentially large files.
+		if info.IsDir() && filepath.Clean(path) == b.Layout().PodLogs() {
+			return fs.SkipDir
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		data, err := afero.ReadFile(b, path)
+		if err != nil {
+			return err
+		}
+		if bytes.Contains(data, []byte(redactionMask)) {
+			found = true
+			return errStopWalk
+		}
+
+		sampled++
+		if sampled >= maxRedactionSampledFiles {
+			return errStopWalk
+		}
+		return nil
+	})
+
+	if errors.Is(err, errStopWalk) {
+		err = nil
+	}
+	return found, err
+}
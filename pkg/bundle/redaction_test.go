@@ -0,0 +1,47 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectRedaction_Report(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"redaction-report.json": `{
+			"byRedactor": {
+				"Redact connection strings": [{"file": "cluster-resources/pods/default.json", "line": 10}],
+				"Redact AWS credentials": [{"file": "configmaps/default/aws.json", "line": 2}]
+			},
+			"byFile": {}
+		}`,
+	})
+
+	redacted, redactors, err := DetectRedaction(b)
+	require.NoError(t, err)
+	assert.True(t, redacted)
+	assert.Equal(t, []string{"Redact AWS credentials", "Redact connection strings"}, redactors)
+}
+
+func TestDetectRedaction_Mask(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/pods/default.json": `{"items": [{"spec": {"containers": [{"env": [{"value": "***HIDDEN***"}]}]}}]}`,
+	})
+
+	redacted, redactors, err := DetectRedaction(b)
+	require.NoError(t, err)
+	assert.True(t, redacted)
+	assert.Empty(t, redactors)
+}
+
+func TestDetectRedaction_NotRedacted(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/pods/default.json": `{"items": []}`,
+		"pod-logs/default/pod-app.log":        "***HIDDEN***",
+	})
+
+	redacted, _, err := DetectRedaction(b)
+	require.NoError(t, err)
+	assert.False(t, redacted)
+}
@@ -0,0 +1,188 @@
+package bundle
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+
+	"github.com/spf13/afero"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ReferenceIssue describes a reference from a pod to a resource that is not
+// present in the bundle.
+type ReferenceIssue struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	// Kind of the referenced resource, e.g. `ConfigMap`.
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Source describes where the reference is used, e.g. `volume "config"`.
+	Source string `json:"source"`
+}
+
+func (i ReferenceIssue) String() string {
+	return fmt.Sprintf("pod %s/%s references missing %s %q in %s", i.Namespace, i.Pod, i.Kind, i.Name, i.Source)
+}
+
+// namespacedNames is a set of resource names per namespace. Empty set means that
+// the resources weren't collected and the references cannot be validated.
+type namespacedNames map[string]map[string]bool
+
+func (n namespacedNames) add(namespace, name string) {
+	if n[namespace] == nil {
+		n[namespace] = map[string]bool{}
+	}
+	n[namespace][name] = true
+}
+
+// ValidateReferences reports pod volumes, `envFrom` and `env` references to
+// ConfigMaps, Secrets and PersistentVolumeClaims that are not present in the
+// bundle. Optional references are ignored. The references to a kind are
+// validated only when the bundle contains at least one resource of that kind,
+// e.g. bundles without collected secrets don't report missing secrets.
+func ValidateReferences(b Bundle) ([]ReferenceIssue, error) {
+	pods, err := loadNamespacedResources(b, "pods")
+	if isNotCollected(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	configMaps, err := collectConfigMapNames(b)
+	if err != nil {
+		return nil, err
+	}
+	secrets, err := collectCMOrSecretNames(b, b.Layout().Secrets(), LoadSecret, namespacedNames{})
+	if err != nil {
+		return nil, err
+	}
+	pvcs, err := collectClusterResourcesNames(b, "pvcs", namespacedNames{})
+	if err != nil {
+		return nil, err
+	}
+
+	typedPods, err := convertList[corev1.Pod](pods)
+	if err != nil {
+		return nil, err
+	}
+
+	checker := &referenceChecker{configMaps: configMaps, secrets: secrets, pvcs: pvcs}
+	for i := range typedPods {
+		checker.checkPod(&typedPods[i])
+	}
+
+	issues := checker.issues
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Namespace != issues[j].Namespace {
+			return issues[i].Namespace < issues[j].Namespace
+		}
+		return issues[i].Pod < issues[j].Pod
+	})
+	return issues, nil
+}
+
+type referenceChecker struct {
+	configMaps namespacedNames
+	secrets    namespacedNames
+	pvcs       namespacedNames
+
+	issues []ReferenceIssue
+}
+
+func (c *referenceChecker) checkPod(pod *corev1.Pod) {
+	check := func(existing namespacedNames, kind, name, source string, optional *bool) {
+		if len(existing) == 0 || name == "" || (optional != nil && *optional) {
+			return
+		}
+		if !existing[pod.Namespace][name] {
+			c.issues = append(c.issues, ReferenceIssue{
+				Namespace: pod.Namespace, Pod: pod.Name, Kind: kind, Name: name, Source: source,
+			})
+		}
+	}
+
+	for _, v := range pod.Spec.Volumes {
+		source := fmt.Sprintf("volume %q", v.Name)
+		switch {
+		case v.ConfigMap != nil:
+			check(c.configMaps, "ConfigMap", v.ConfigMap.Name, source, v.ConfigMap.Optional)
+		case v.Secret != nil:
+			check(c.secrets, "Secret", v.Secret.SecretName, source, v.Secret.Optional)
+		case v.PersistentVolumeClaim != nil:
+			check(c.pvcs, "PersistentVolumeClaim", v.PersistentVolumeClaim.ClaimName, source, nil)
+		}
+	}
+
+	containers := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for i := range containers {
+		container := &containers[i]
+		for _, envFrom := range container.EnvFrom {
+			source := fmt.Sprintf("container %q envFrom", container.Name)
+			if ref := envFrom.ConfigMapRef; ref != nil {
+				check(c.configMaps, "ConfigMap", ref.Name, source, ref.Optional)
+			}
+			if ref := envFrom.SecretRef; ref != nil {
+				check(c.secrets, "Secret", ref.Name, source, ref.Optional)
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			source := fmt.Sprintf("container %q env %q", container.Name, env.Name)
+			if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil {
+				check(c.configMaps, "ConfigMap", ref.Name, source, ref.Optional)
+			}
+			if ref := env.ValueFrom.SecretKeyRef; ref != nil {
+				check(c.secrets, "Secret", ref.Name, source, ref.Optional)
+			}
+		}
+	}
+}
+
+// collectConfigMapNames returns names of ConfigMaps collected either by the
+// configmap collector or by the cluster resources collector.
+func collectConfigMapNames(b Bundle) (namespacedNames, error) {
+	names, err := collectCMOrSecretNames(b, b.Layout().ConfigMaps(), LoadConfigMap, namespacedNames{})
+	if err != nil {
+		return nil, err
+	}
+	return collectClusterResourcesNames(b, "configmaps", names)
+}
+
+func collectClusterResourcesNames(b Bundle, dir string, names namespacedNames) (namespacedNames, error) {
+	list, err := loadNamespacedResources(b, dir)
+	if isNotCollected(err) {
+		return names, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := range list.Items {
+		names.add(list.Items[i].GetNamespace(), list.Items[i].GetName())
+	}
+	return names, nil
+}
+
+func collectCMOrSecretNames(
+	b Bundle,
+	root string,
+	loadFn func(afero.Fs, string) (*unstructured.Unstructured, error),
+	names namespacedNames,
+) (namespacedNames, error) {
+	err := afero.Walk(b, root, func(path string, info fs.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		obj, err := loadFn(b, path)
+		if err != nil {
+			return fmt.Errorf("failed to load %q: %w", path, err)
+		}
+		names.add(obj.GetNamespace(), obj.GetName())
+		return nil
+	})
+	return names, err
+}
@@ -0,0 +1,55 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateReferences(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/pods/default.json": `{"items": [{
+			"metadata": {"name": "web", "namespace": "default"},
+			"spec": {
+				"volumes": [
+					{"name": "config", "configMap": {"name": "web-config"}},
+					{"name": "missing", "configMap": {"name": "deleted-config"}},
+					{"name": "optional", "configMap": {"name": "optional-config", "optional": true}},
+					{"name": "tls", "secret": {"secretName": "web-tls"}},
+					{"name": "data", "persistentVolumeClaim": {"claimName": "web-data"}}
+				],
+				"containers": [{
+					"name": "app",
+					"envFrom": [{"secretRef": {"name": "web-env"}}],
+					"env": [{"name": "MODE", "valueFrom": {"configMapKeyRef": {"name": "web-config", "key": "mode"}}}]
+				}]
+			}
+		}]}`,
+		"cluster-resources/configmaps/default.json": `{"items": [{"metadata": {"name": "web-config", "namespace": "default"}}]}`,
+		"secrets/default/web-tls/tls.crt.json":      `{"name": "web-tls", "namespace": "default"}`,
+		"cluster-resources/pvcs/default.json":       `{"items": [{"metadata": {"name": "other-data", "namespace": "default"}}]}`,
+	})
+
+	issues, err := ValidateReferences(b)
+	require.NoError(t, err)
+	assert.Equal(t, []ReferenceIssue{
+		{Namespace: "default", Pod: "web", Kind: "ConfigMap", Name: "deleted-config", Source: `volume "missing"`},
+		{Namespace: "default", Pod: "web", Kind: "PersistentVolumeClaim", Name: "web-data", Source: `volume "data"`},
+		{Namespace: "default", Pod: "web", Kind: "Secret", Name: "web-env", Source: `container "app" envFrom`},
+	}, issues)
+	assert.Equal(t, `pod default/web references missing ConfigMap "deleted-config" in volume "missing"`, issues[0].String())
+}
+
+func TestValidateReferences_NotCollected(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/pods/default.json": `{"items": [{
+			"metadata": {"name": "web", "namespace": "default"},
+			"spec": {"volumes": [{"name": "tls", "secret": {"secretName": "web-tls"}}]}
+		}]}`,
+	})
+
+	issues, err := ValidateReferences(b)
+	require.NoError(t, err)
+	assert.Empty(t, issues, "secrets were not collected")
+}
@@ -0,0 +1,146 @@
+package bundle
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NamespaceRequests contains sums of pod requests and limits in a namespace.
+type NamespaceRequests struct {
+	Pods     int                 `json:"pods"`
+	Requests corev1.ResourceList `json:"requests"`
+	Limits   corev1.ResourceList `json:"limits"`
+	// BestEffortPods are pods without any requests and limits, which are not
+	// accounted in the requests and are the first to be evicted.
+	BestEffortPods []string `json:"bestEffortPods,omitempty"`
+}
+
+// RequestsReport compares requests and limits of pods with the allocatable
+// resources of nodes.
+type RequestsReport struct {
+	Namespaces map[string]*NamespaceRequests `json:"namespaces"`
+	// Requests and Limits are sums across all namespaces.
+	Requests corev1.ResourceList `json:"requests"`
+	Limits   corev1.ResourceList `json:"limits"`
+	// Allocatable is the sum of allocatable resources of all nodes.
+	Allocatable corev1.ResourceList `json:"allocatable"`
+	// RequestsUtilization and LimitsUtilization are ratios of the requests and
+	// limits to allocatable resources, e.g. 0.75 for 75 %.
+	RequestsUtilization map[corev1.ResourceName]float64 `json:"requestsUtilization"`
+	LimitsUtilization   map[corev1.ResourceName]float64 `json:"limitsUtilization"`
+	// Overcommitted are resources with limits exceeding the allocatable
+	// resources.
+	Overcommitted []corev1.ResourceName `json:"overcommitted,omitempty"`
+	// BestEffortPods is the number of pods without requests and limits.
+	BestEffortPods int `json:"bestEffortPods"`
+}
+
+// AnalyzeResourceRequests sums requests and limits of pods per namespace and
+// compares them with the allocatable resources of nodes. Only pods that are not
+// finished are accounted. The pod requests are calculated as the scheduler does,
+// i.e. the higher of the sum of containers and the largest init container
+// plus the pod overhead.
+func AnalyzeResourceRequests(b Bundle) (*RequestsReport, error) {
+	list, err := loadNamespacedResources(b, "pods")
+	if err != nil && !isNotCollected(err) {
+		return nil, err
+	}
+	var pods []corev1.Pod
+	if err == nil {
+		if pods, err = convertList[corev1.Pod](list); err != nil {
+			return nil, err
+		}
+	}
+
+	nodes, err := loadNodes(b)
+	if err != nil && !isNotCollected(err) {
+		return nil, err
+	}
+
+	report := &RequestsReport{
+		Namespaces:          map[string]*NamespaceRequests{},
+		Requests:            corev1.ResourceList{},
+		Limits:              corev1.ResourceList{},
+		Allocatable:         corev1.ResourceList{},
+		RequestsUtilization: map[corev1.ResourceName]float64{},
+		LimitsUtilization:   map[corev1.ResourceName]float64{},
+	}
+	for i := range nodes {
+		addResources(report.Allocatable, nodes[i].Status.Allocatable)
+	}
+	for i := range pods {
+		report.addPod(&pods[i])
+	}
+
+	for name, allocatable := range report.Allocatable {
+		if allocatable.IsZero() {
+			continue
+		}
+		if requests, ok := report.Requests[name]; ok {
+			report.RequestsUtilization[name] = requests.AsApproximateFloat64() / allocatable.AsApproximateFloat64()
+		}
+		if limits, ok := report.Limits[name]; ok {
+			report.LimitsUtilization[name] = limits.AsApproximateFloat64() / allocatable.AsApproximateFloat64()
+			if limits.Cmp(allocatable) > 0 {
+				report.Overcommitted = append(report.Overcommitted, name)
+			}
+		}
+	}
+	sort.Slice(report.Overcommitted, func(i, j int) bool { return report.Overcommitted[i] < report.Overcommitted[j] })
+	return report, nil
+}
+
+func (r *RequestsReport) addPod(pod *corev1.Pod) {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return
+	}
+
+	ns := r.Namespaces[pod.Namespace]
+	if ns == nil {
+		ns = &NamespaceRequests{Requests: corev1.ResourceList{}, Limits: corev1.ResourceList{}}
+		r.Namespaces[pod.Namespace] = ns
+	}
+	ns.Pods++
+
+	requests := podResources(pod, func(c *corev1.Container) corev1.ResourceList { return c.Resources.Requests })
+	limits := podResources(pod, func(c *corev1.Container) corev1.ResourceList { return c.Resources.Limits })
+	if len(requests) == 0 && len(limits) == 0 {
+		ns.BestEffortPods = append(ns.BestEffortPods, pod.Name)
+		r.BestEffortPods++
+		return
+	}
+
+	addResources(ns.Requests, requests)
+	addResources(ns.Limits, limits)
+	addResources(r.Requests, requests)
+	addResources(r.Limits, limits)
+}
+
+// podResources returns the effective pod resources, the higher of the sum of
+// containers and the largest init container, plus the pod overhead.
+func podResources(pod *corev1.Pod, resources func(*corev1.Container) corev1.ResourceList) corev1.ResourceList {
+	result := corev1.ResourceList{}
+	for i := range pod.Spec.Containers {
+		addResources(result, resources(&pod.Spec.Containers[i]))
+	}
+	for i := range pod.Spec.InitContainers {
+		for name, quantity := range resources(&pod.Spec.InitContainers[i]) {
+			if current, ok := result[name]; !ok || quantity.Cmp(current) > 0 {
+				result[name] = quantity.DeepCopy()
+			}
+		}
+	}
+	if len(result) > 0 {
+		addResources(result, pod.Spec.Overhead)
+	}
+	return result
+}
+
+func addResources(total, resources corev1.ResourceList) {
+	for name, quantity := range resources {
+		current := total[name]
+		current.Add(quantity)
+		total[name] = current
+	}
+}
@@ -0,0 +1,93 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const requestsPods = `{"items": [
+  {
+    "metadata": {"name": "web-0", "namespace": "default"},
+    "spec": {"containers": [
+      {"name": "app", "resources": {"requests": {"cpu": "500m", "memory": "1Gi"}, "limits": {"cpu": "2", "memory": "2Gi"}}},
+      {"name": "sidecar", "resources": {"requests": {"cpu": "100m", "memory": "128Mi"}}}
+    ]},
+    "status": {"phase": "Running"}
+  },
+  {
+    "metadata": {"name": "web-1", "namespace": "default"},
+    "spec": {
+      "initContainers": [{"name": "migrate", "resources": {"requests": {"cpu": "1", "memory": "256Mi"}}}],
+      "containers": [{"name": "app", "resources": {"requests": {"cpu": "500m", "memory": "1Gi"}, "limits": {"cpu": "2", "memory": "2Gi"}}}]
+    },
+    "status": {"phase": "Running"}
+  },
+  {
+    "metadata": {"name": "batch-done", "namespace": "default"},
+    "spec": {"containers": [{"name": "job", "resources": {"requests": {"cpu": "4"}}}]},
+    "status": {"phase": "Succeeded"}
+  },
+  {
+    "metadata": {"name": "debug", "namespace": "tools"},
+    "spec": {"containers": [{"name": "shell"}]},
+    "status": {"phase": "Running"}
+  }
+]}`
+
+const requestsNodes = `{"items": [
+  {"metadata": {"name": "node-1"}, "status": {"allocatable": {"cpu": "2", "memory": "4Gi", "pods": "110"}}},
+  {"metadata": {"name": "node-2"}, "status": {"allocatable": {"cpu": "2", "memory": "4Gi", "pods": "110"}}}
+]}`
+
+func TestAnalyzeResourceRequests(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/pods/default.json": requestsPods,
+		"cluster-resources/nodes.json":        requestsNodes,
+	})
+
+	report, err := AnalyzeResourceRequests(b)
+	require.NoError(t, err)
+
+	quantity := func(t *testing.T, expected string, actual resource.Quantity) {
+		t.Helper()
+		expectedQuantity := resource.MustParse(expected)
+		assert.Zero(t, expectedQuantity.Cmp(actual), "expected %s, got %s", expected, actual.String())
+	}
+
+	def := report.Namespaces["default"]
+	require.NotNil(t, def)
+	assert.Equal(t, 2, def.Pods)
+	// web-0: 600m + web-1: max(500m, init 1) = 1600m
+	quantity(t, "1600m", def.Requests[corev1.ResourceCPU])
+	quantity(t, "2176Mi", def.Requests[corev1.ResourceMemory])
+	quantity(t, "4", def.Limits[corev1.ResourceCPU])
+	assert.Empty(t, def.BestEffortPods)
+
+	tools := report.Namespaces["tools"]
+	require.NotNil(t, tools)
+	assert.Equal(t, []string{"debug"}, tools.BestEffortPods)
+	assert.Equal(t, 1, report.BestEffortPods)
+
+	quantity(t, "4", report.Allocatable[corev1.ResourceCPU])
+	assert.InDelta(t, 0.4, report.RequestsUtilization[corev1.ResourceCPU], 0.001)
+	assert.InDelta(t, 1.0, report.LimitsUtilization[corev1.ResourceCPU], 0.001)
+	assert.InDelta(t, 0.5, report.LimitsUtilization[corev1.ResourceMemory], 0.001)
+	assert.Empty(t, report.Overcommitted)
+}
+
+func TestAnalyzeResourceRequests_Overcommitted(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/pods/default.json": requestsPods,
+		"cluster-resources/nodes.json": `{"items": [
+  {"metadata": {"name": "node-1"}, "status": {"allocatable": {"cpu": "3", "memory": "16Gi"}}}
+]}`,
+	})
+
+	report, err := AnalyzeResourceRequests(b)
+	require.NoError(t, err)
+	assert.Equal(t, []corev1.ResourceName{corev1.ResourceCPU}, report.Overcommitted)
+}
@@ -0,0 +1,92 @@
+package bundle
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ResourceFormat describes a file format with k8s API resources.
+type ResourceFormat struct {
+	// Name of the format used in error messages, e.g. `json`.
+	Name string
+	// Extensions of files in the format, e.g. `.json`. Files with the
+	// extension are parsed only by this format. The extensions are also
+	// probed, in registration order, by OpenResource.
+	Extensions []string
+	// Sniff reports if data from a file without any registered extension is
+	// in the format. When nil, the format is tried for all such files and the
+	// data is in the format when Parse succeeds.
+	Sniff func(data []byte) bool
+	// Parse parses the decompressed file data. The path is given without the
+	// `.gz` extension.
+	Parse func(data []byte, path string) (*unstructured.UnstructuredList, error)
+}
+
+// resourceFormatRegistry holds formats in registration order.
+type resourceFormatRegistry struct {
+	mu      sync.RWMutex
+	formats []ResourceFormat
+}
+
+//nolint:gochecknoglobals // Formats are registered from init functions of other packages.
+var resourceFormats = &resourceFormatRegistry{formats: builtinResourceFormats()}
+
+func builtinResourceFormats() []ResourceFormat {
+	return []ResourceFormat{
+		{Name: "json", Extensions: []string{".json"}, Parse: parseJSONList},
+		{Name: "yaml", Extensions: []string{".yaml", ".yml"}, Parse: func(data []byte, _ string) (*unstructured.UnstructuredList, error) {
+			return parseYAMLList(data)
+		}},
+	}
+}
+
+// RegisterResourceFormat adds a format used by LoadResourcesFromFile and
+// LoadResources. Formats registered later are tried after the built-in JSON
+// and YAML formats for files without a known extension.
+func RegisterResourceFormat(format ResourceFormat) {
+	resourceFormats.mu.Lock()
+	defer resourceFormats.mu.Unlock()
+	resourceFormats.formats = append(resourceFormats.formats, format)
+}
+
+func (r *resourceFormatRegistry) list() []ResourceFormat {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]ResourceFormat{}, r.formats...)
+}
+
+// extensions returns extensions of all formats in registration order.
+func (r *resourceFormatRegistry) extensions() []string {
+	var extensions []string
+	for _, format := range r.list() {
+		extensions = append(extensions, format.Extensions...)
+	}
+	return extensions
+}
+
+// parse parses data with the format registered for the path extension. Data
+// of files with other extensions, e.g. `.txt` with redirected
+// `kubectl get -o json` output, is parsed by the first format that accepts it.
+func (r *resourceFormatRegistry) parse(data []byte, path string) (*unstructured.UnstructuredList, error) {
+	formats := r.list()
+	for _, format := range formats {
+		for _, ext := range format.Extensions {
+			if strings.HasSuffix(path, ext) {
+				return format.Parse(data, path)
+			}
+		}
+	}
+
+	for _, format := range formats {
+		if format.Sniff != nil && !format.Sniff(data) {
+			continue
+		}
+		if list, err := format.Parse(data, path); err == nil {
+			return list, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported data format")
+}
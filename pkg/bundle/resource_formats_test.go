@@ -0,0 +1,70 @@
+package bundle
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// registerTestResourceFormat registers format and removes it when the test
+// finishes.
+func registerTestResourceFormat(t *testing.T, format ResourceFormat) {
+	t.Helper()
+	formats := resourceFormats.list()
+	t.Cleanup(func() {
+		resourceFormats.mu.Lock()
+		defer resourceFormats.mu.Unlock()
+		resourceFormats.formats = formats
+	})
+	RegisterResourceFormat(format)
+}
+
+// parsePodNames parses lines with pod names.
+func parsePodNames(data []byte, _ string) (*unstructured.UnstructuredList, error) {
+	list := &unstructured.UnstructuredList{}
+	for _, name := range strings.Fields(strings.TrimPrefix(string(data), "pods:")) {
+		pod := unstructured.Unstructured{}
+		pod.SetAPIVersion("v1")
+		pod.SetKind("Pod")
+		pod.SetName(name)
+		list.Items = append(list.Items, pod)
+	}
+	return list, nil
+}
+
+func TestRegisterResourceFormat(t *testing.T) {
+	registerTestResourceFormat(t, ResourceFormat{
+		Name:       "names",
+		Extensions: []string{".names"},
+		Sniff:      func(data []byte) bool { return bytes.HasPrefix(data, []byte("pods:")) },
+		Parse:      parsePodNames,
+	})
+
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/pods/default.names": "a\nb\n",
+		"kubectl/get-pods.txt":                 "pods: c",
+		"kubectl/other.txt":                    "c d",
+		"kubectl/pods.json":                    `[{"kind": "Pod", "metadata": {"name": "e"}}]`,
+	})
+
+	list, err := LoadResources(b, "cluster-resources/pods/default")
+	require.NoError(t, err)
+	require.Len(t, list.Items, 2)
+	assert.Equal(t, "b", list.Items[1].GetName())
+
+	list, err = LoadResourcesFromFile(b, "kubectl/get-pods.txt")
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "c", list.Items[0].GetName())
+
+	_, err = LoadResourcesFromFile(b, "kubectl/other.txt")
+	assert.Error(t, err)
+
+	list, err = LoadResourcesFromFile(b, "kubectl/pods.json")
+	require.NoError(t, err)
+	assert.Equal(t, "e", list.Items[0].GetName())
+}
@@ -1,9 +1,11 @@
 package bundle
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/afero"
@@ -11,6 +13,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/apimachinery/pkg/util/yaml"
 
 	"github.com/mhrabovcin/troubleshoot-live/pkg/utils"
@@ -18,35 +22,102 @@ import (
 
 // LoadResourcesFromFile tries to k8s API resources from a given file. It supports
 // resources stored as List kind, YAML array of separate resources, JSON array of
-// resources and JSON stored item list without TypeMeta information.
+// resources, JSON stored item list without TypeMeta information and JSON
+// envelope with the items nested in `data.items`.
 // The result will be returned as `UnstructuredList` but the items could be missing
 // GVK information. It is up to caller to add GVK to each item before further
 // processing.
 func LoadResourcesFromFile(bundle afero.Fs, path string) (*unstructured.UnstructuredList, error) {
-	list := &unstructured.UnstructuredList{}
+	data, err := readFile(bundle, path)
+	if err != nil {
+		return nil, err
+	}
 
-	data, err := afero.ReadFile(bundle, path)
+	return parseResources(data, path)
+}
+
+// LoadResources loads resources from the first file matching given path without
+// extension. See OpenResource for the list of probed extensions.
+func LoadResources(b Bundle, basePathWithoutExt string) (*unstructured.UnstructuredList, error) {
+	data, path, err := OpenResource(b, basePathWithoutExt)
 	if err != nil {
 		return nil, err
 	}
 
-	if strings.HasSuffix(path, ".json") {
-		return parseJSONList(data, path)
+	return parseResources(data, path)
+}
+
+// parseResources parses data with the registered format detected from the
+// path extension. Compressed files are expected to be already decompressed.
+// Files with other extensions, e.g. `.txt` with redirected `kubectl get -o json`
+// output, are parsed as JSON, then as YAML and then by other registered formats.
+func parseResources(data []byte, path string) (*unstructured.UnstructuredList, error) {
+	return resourceFormats.parse(data, strings.TrimSuffix(path, ".gz"))
+}
+
+// parseYAMLList parses YAML array of resources, a List kind or a single
+// resource, which is returned as a list with one item.
+func parseYAMLList(data []byte) (*unstructured.UnstructuredList, error) {
+	var content any
+	if err := yaml.Unmarshal(data, &content); err != nil {
+		return nil, err
 	}
 
-	if strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml") {
-		items := []unstructured.Unstructured{}
-		if err := yaml.Unmarshal(data, &items); err != nil {
-			return nil, err
+	switch content := content.(type) {
+	case nil:
+		return &unstructured.UnstructuredList{}, nil
+	case []any:
+		list := &unstructured.UnstructuredList{}
+		for i, item := range content {
+			object, ok := item.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("item %d is not an object", i)
+			}
+			list.Items = append(list.Items, unstructured.Unstructured{Object: object})
+		}
+		return list, nil
+	case map[string]any:
+		kind, _, _ := unstructured.NestedString(content, "kind")
+		if kind == "" {
+			return nil, fmt.Errorf("object without kind is not a resource")
+		}
+		if !strings.HasSuffix(kind, "List") {
+			return &unstructured.UnstructuredList{Items: []unstructured.Unstructured{{Object: content}}}, nil
 		}
-		list.Items = items
+		list := &unstructured.UnstructuredList{}
+		list.SetUnstructuredContent(content)
 		return list, nil
 	}
-
-	return nil, fmt.Errorf("unsupported data format")
+	return nil, fmt.Errorf("unsupported YAML content %T", content)
 }
 
 func parseJSONList(data []byte, path string) (*unstructured.UnstructuredList, error) {
+	list, err := parseJSONListFormats(data, path)
+	if err != nil || len(list.Items) > 0 {
+		return list, err
+	}
+
+	// Format:
+	// - items nested in a data envelope, checked last as any JSON object is
+	//   accepted by the previous formats without items
+	// {
+	//  "metadata": { ... },
+	//  "data": { "items": [ {}, {}, ... {} ] }
+	// }
+	envelope := struct {
+		Data struct {
+			Items []map[string]any `json:"items"`
+		} `json:"data"`
+	}{}
+	if err := unmarshalItems(data, &envelope, func() []map[string]any { return envelope.Data.Items }); err == nil {
+		for _, item := range envelope.Data.Items {
+			list.Items = append(list.Items, unstructured.Unstructured{Object: item})
+		}
+	}
+	return list, nil
+}
+
+func parseJSONListFormats(data []byte, path string) (*unstructured.UnstructuredList, error) {
 	list := &unstructured.UnstructuredList{}
 	// Format:
 	// - stored as unstructured.UnstructedList and items contain GVK info
@@ -55,12 +126,14 @@ func parseJSONList(data []byte, path string) (*unstructured.UnstructuredList, er
 		return list, nil
 	}
 	errs := []error{err}
+	// Failed unmarshal can leave partially decoded items in the list.
+	list = &unstructured.UnstructuredList{}
 
 	// Format:
 	// - no GVK info in objects
 	// [ {}, {}, ... {} ]
 	items := []map[string]any{}
-	if secondErr := json.Unmarshal(data, &items); secondErr != nil {
+	if secondErr := unmarshalItems(data, &items, func() []map[string]any { return items }); secondErr != nil {
 		errs = append(errs, secondErr)
 	} else {
 		for _, item := range items {
@@ -79,7 +152,7 @@ func parseJSONList(data []byte, path string) (*unstructured.UnstructuredList, er
 	untypedList := struct {
 		Items []map[string]any `json:"items"`
 	}{}
-	if thirdErr := json.Unmarshal(data, &untypedList); thirdErr != nil {
+	if thirdErr := unmarshalItems(data, &untypedList, func() []map[string]any { return untypedList.Items }); thirdErr != nil {
 		errs = append(errs, thirdErr)
 	} else {
 		for _, item := range untypedList.Items {
@@ -95,6 +168,24 @@ func parseJSONList(data []byte, path string) (*unstructured.UnstructuredList, er
 	return nil, fmt.Errorf("failed to load resources from JSON file %q with errors: %w", path, errors.Join(errs...))
 }
 
+// unmarshalItems decodes JSON items with numbers converted to int64 or
+// float64 the same way as unstructured objects do. Plain json.Unmarshal
+// decodes all numbers as float64, which corrupts large integers, e.g.
+// `generation` or big quantities.
+func unmarshalItems(data []byte, v any, items func() []map[string]any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	for _, item := range items() {
+		if err := utiljson.ConvertMapNumbers(item, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // cmOrSecret represents a special data structure that troubleshoot uses for
 // storing secrets and configmaps.
 type cmOrSecret struct {
@@ -104,16 +195,56 @@ type cmOrSecret struct {
 	Data map[string]string `json:"data,omitempty"`
 }
 
+// cmOrSecretFieldAliases returns field names used by collector variants for
+// each of the cmOrSecret fields. The troubleshoot names are first.
+func cmOrSecretFieldAliases() map[string][]string {
+	return map[string][]string{
+		"name":      {"name", "configMapName", "secretName"},
+		"namespace": {"namespace", "configMapNamespace", "secretNamespace"},
+		"data":      {"data", "configMapData"},
+	}
+}
+
+// parseCMOrSecret parses the cmOrSecret struct. Fields stored under any of
+// the known aliases are accepted, the first present alias is used.
+func parseCMOrSecret(data []byte) (*cmOrSecret, error) {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	normalized := map[string]json.RawMessage{}
+	for field, aliases := range cmOrSecretFieldAliases() {
+		for _, alias := range aliases {
+			if value, ok := fields[alias]; ok {
+				normalized[field] = value
+				break
+			}
+		}
+	}
+
+	encoded, err := json.Marshal(normalized)
+	if err != nil {
+		return nil, err
+	}
+	result := &cmOrSecret{}
+	if err := json.Unmarshal(encoded, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 // LoadConfigMap loads configmap data from special struct that support-bundle
-// uses to store CMs in.
+// uses to store CMs in. Field names of forked collectors, e.g.
+// `configMapName`, are supported as well.
 func LoadConfigMap(bundle afero.Fs, path string) (*unstructured.Unstructured, error) {
 	data, err := afero.ReadFile(bundle, path)
 	if err != nil {
 		return nil, err
 	}
 
-	cmStruct := cmOrSecret{}
-	if err := json.Unmarshal(data, &cmStruct); err != nil {
+	cmStruct, err := parseCMOrSecret(data)
+	if err != nil {
 		return nil, err
 	}
 
@@ -140,8 +271,8 @@ func LoadSecret(bundle afero.Fs, path string) (*unstructured.Unstructured, error
 		return nil, err
 	}
 
-	secretData := cmOrSecret{}
-	if err := json.Unmarshal(data, &secretData); err != nil {
+	secretData, err := parseCMOrSecret(data)
+	if err != nil {
 		return nil, err
 	}
 
@@ -158,3 +289,39 @@ func LoadSecret(bundle afero.Fs, path string) (*unstructured.Unstructured, error
 
 	return &unstructured.Unstructured{Object: u}, nil
 }
+
+// GVKFromPath returns GVK of resources stored in the file at path relative to
+// cluster resources. Kind was not stored in older troubleshoot versions for
+// non-CRDs, so the kind is detected by the file name, e.g. `pods/*.json`.
+// Empty GVK is returned for unknown files.
+func GVKFromPath(l Layout, path string) schema.GroupVersionKind {
+	mappings := map[string]schema.GroupVersionKind{
+		"cronjobs/*.json":      {Version: "v1", Kind: "CronJob", Group: "batch"},
+		"deployments/*.json":   {Version: "v1", Kind: "Deployment", Group: "apps"},
+		"events/*.json":        {Version: "v1", Kind: "Event"},
+		"ingress/*.json":       {Version: "v1", Kind: "Ingress", Group: "networking.k8s.io"},
+		"jobs/*.json":          {Version: "v1", Kind: "Job", Group: "batch"},
+		"limitranges/*.json":   {Version: "v1", Kind: "LimitRange"},
+		"nodes.json":           {Version: "v1", Kind: "Node"},
+		"pods/*.json":          {Version: "v1", Kind: "Pod"},
+		"pvcs/*.json":          {Version: "v1", Kind: "PersistentVolumeClaim"},
+		"pvs.json":             {Version: "v1", Kind: "PersistentVolume"},
+		"replicasets/*.json":   {Version: "v1", Kind: "ReplicaSet", Group: "apps"},
+		"services/*.json":      {Version: "v1", Kind: "Service"},
+		"statefulsets/*.json":  {Version: "v1", Kind: "StatefulSet", Group: "apps"},
+		"storage-classes.json": {Version: "v1", Kind: "StorageClass", Group: "storage.k8s.io"},
+	}
+	for _, r := range OpenShiftResources(l) {
+		for _, pattern := range r.Paths {
+			mappings[pattern] = r.GVK
+		}
+	}
+
+	path = strings.TrimSuffix(path, ".gz")
+	for pattern, gvk := range mappings {
+		if ok, _ := filepath.Match(pattern, path); ok {
+			return gvk
+		}
+	}
+	return schema.GroupVersionKind{}
+}
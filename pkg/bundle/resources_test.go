@@ -0,0 +1,138 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestLoadResourcesFromFile_TextWithJSON(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"kubectl/get-pods.txt": `{"apiVersion": "v1", "kind": "List", "items": [
+			{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "a"}},
+			{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "b"}}
+		]}`,
+	})
+
+	list, err := LoadResourcesFromFile(b, "kubectl/get-pods.txt")
+	require.NoError(t, err)
+	require.Len(t, list.Items, 2)
+	assert.Equal(t, "b", list.Items[1].GetName())
+}
+
+func TestLoadResourcesFromFile_TextWithYAML(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"kubectl/get-pods.log": "- apiVersion: v1\n  kind: Pod\n  metadata:\n    name: a\n",
+	})
+
+	list, err := LoadResourcesFromFile(b, "kubectl/get-pods.log")
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "a", list.Items[0].GetName())
+}
+
+func TestLoadResourcesFromFile_Unsupported(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"kubectl/output.txt": "NAME   READY   STATUS\nfoo    1/1     Running\n",
+	})
+
+	_, err := LoadResourcesFromFile(b, "kubectl/output.txt")
+	assert.EqualError(t, err, "unsupported data format")
+}
+
+func TestLoadResourcesFromFile_LargeIntegers(t *testing.T) {
+	// 2^53 + 1 can't be represented as float64.
+	const generation = int64(9007199254740993)
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/deployments/default.json": `[
+			{"metadata": {"name": "a", "generation": 9007199254740993}, "spec": {"progressDeadlineSeconds": 1.5}}
+		]`,
+		"cluster-resources/statefulsets/default.json": `{"items": [
+			{"metadata": {"name": "b", "generation": 9007199254740993}}
+		]}`,
+	})
+
+	for _, path := range []string{
+		"cluster-resources/deployments/default.json",
+		"cluster-resources/statefulsets/default.json",
+	} {
+		list, err := LoadResourcesFromFile(b, path)
+		require.NoError(t, err)
+		require.Len(t, list.Items, 1)
+		assert.Equal(t, generation, list.Items[0].GetGeneration(), path)
+	}
+
+	list, err := LoadResourcesFromFile(b, "cluster-resources/deployments/default.json")
+	require.NoError(t, err)
+	value, _, err := unstructured.NestedFloat64(list.Items[0].Object, "spec", "progressDeadlineSeconds")
+	require.NoError(t, err)
+	assert.InDelta(t, 1.5, value, 0)
+}
+
+func TestLoadResourcesFromFile_YAMLSingleObject(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/deployments/default.yaml": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n  namespace: default\nspec:\n  replicas: 3\n",
+		"cluster-resources/services/default.yaml":    "apiVersion: v1\nkind: ServiceList\nitems:\n- apiVersion: v1\n  kind: Service\n  metadata:\n    name: a\n- apiVersion: v1\n  kind: Service\n  metadata:\n    name: b\n",
+	})
+
+	list, err := LoadResourcesFromFile(b, "cluster-resources/deployments/default.yaml")
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "Deployment", list.Items[0].GetKind())
+	assert.Equal(t, "web", list.Items[0].GetName())
+	replicas, _, _ := unstructured.NestedInt64(list.Items[0].Object, "spec", "replicas")
+	assert.Equal(t, int64(3), replicas)
+
+	list, err = LoadResourcesFromFile(b, "cluster-resources/services/default.yaml")
+	require.NoError(t, err)
+	require.Len(t, list.Items, 2)
+	assert.Equal(t, "b", list.Items[1].GetName())
+}
+
+func TestLoadResourcesFromFile_DataEnvelope(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/pods/default.json": `{"apiVersion": "export.example.com/v1", "metadata": {"exportedAt": "2024-01-01T10:00:00Z"},
+			"data": {"items": [
+				{"metadata": {"name": "a"}, "spec": {"priority": 10}},
+				{"metadata": {"name": "b"}}
+			]}}`,
+		"cluster-resources/services/default.json": `{"metadata": {}, "data": {"note": "no items"}}`,
+	})
+
+	list, err := LoadResourcesFromFile(b, "cluster-resources/pods/default.json")
+	require.NoError(t, err)
+	require.Len(t, list.Items, 2)
+	assert.Equal(t, "b", list.Items[1].GetName())
+	priority, _, _ := unstructured.NestedInt64(list.Items[0].Object, "spec", "priority")
+	assert.Equal(t, int64(10), priority)
+
+	list, err = LoadResourcesFromFile(b, "cluster-resources/services/default.json")
+	require.NoError(t, err)
+	assert.Empty(t, list.Items)
+}
+
+func TestLoadConfigMap_FieldAliases(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"configmaps/default/settings.json": `{"configMapName": "settings", "configMapNamespace": "default", "configMapData": {"mode": "debug"}}`,
+		"configmaps/default/plain.json":    `{"name": "plain", "namespace": "default", "configMapName": "ignored", "data": {"a": "b"}}`,
+		"secrets/default/token.json":       `{"secretName": "token", "secretNamespace": "default", "value": "redacted"}`,
+	})
+
+	cm, err := LoadConfigMap(b, "configmaps/default/settings.json")
+	require.NoError(t, err)
+	assert.Equal(t, "settings", cm.GetName())
+	assert.Equal(t, "default", cm.GetNamespace())
+	value, _, _ := unstructured.NestedString(cm.Object, "data", "mode")
+	assert.Equal(t, "debug", value)
+
+	cm, err = LoadConfigMap(b, "configmaps/default/plain.json")
+	require.NoError(t, err)
+	assert.Equal(t, "plain", cm.GetName(), "troubleshoot field name takes precedence")
+
+	secret, err := LoadSecret(b, "secrets/default/token.json")
+	require.NoError(t, err)
+	assert.Equal(t, "token", secret.GetName())
+	assert.Equal(t, "default", secret.GetNamespace())
+}
@@ -0,0 +1,116 @@
+package bundle
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	revisionAnnotation    = "deployment.kubernetes.io/revision"
+	changeCauseAnnotation = "kubernetes.io/change-cause"
+)
+
+// RevisionInfo is a revision of a deployment reconstructed from its
+// ReplicaSet.
+type RevisionInfo struct {
+	Revision        int64  `json:"revision"`
+	ReplicaSet      string `json:"replicaSet"`
+	PodTemplateHash string `json:"podTemplateHash,omitempty"`
+	// ChangeCause is the `kubernetes.io/change-cause` annotation.
+	ChangeCause   string                 `json:"changeCause,omitempty"`
+	Replicas      int32                  `json:"replicas"`
+	ReadyReplicas int32                  `json:"readyReplicas"`
+	Created       metav1.Time            `json:"created"`
+	Template      corev1.PodTemplateSpec `json:"template"`
+}
+
+// ReconstructRolloutHistory returns revisions of the deployment ordered from
+// the oldest, like `kubectl rollout history`. ReplicaSets are matched to
+// the deployment by owner references. When the deployment was collected,
+// ReplicaSets without owner references that match its selector and have
+// the `pod-template-hash` label are included as well.
+func ReconstructRolloutHistory(b Bundle, namespace, deployment string) ([]RevisionInfo, error) {
+	list, err := LoadResources(b, filepath.Join(b.Layout().ClusterResources(), "replicasets", namespace))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load replicasets of namespace %q: %w", namespace, err)
+	}
+	replicaSets, err := convertList[appsv1.ReplicaSet](list)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := findDeployment(b, namespace, deployment)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := []RevisionInfo{}
+	for i := range replicaSets {
+		rs := &replicaSets[i]
+		if !isOwnedByDeployment(rs, deployment, owner) {
+			continue
+		}
+		revision, _ := strconv.ParseInt(rs.Annotations[revisionAnnotation], 10, 64)
+		revisions = append(revisions, RevisionInfo{
+			Revision:        revision,
+			ReplicaSet:      rs.Name,
+			PodTemplateHash: rs.Labels[appsv1.DefaultDeploymentUniqueLabelKey],
+			ChangeCause:     rs.Annotations[changeCauseAnnotation],
+			Replicas:        rs.Status.Replicas,
+			ReadyReplicas:   rs.Status.ReadyReplicas,
+			Created:         rs.CreationTimestamp,
+			Template:        rs.Spec.Template,
+		})
+	}
+
+	sort.SliceStable(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+	return revisions, nil
+}
+
+// findDeployment returns the deployment or nil when it wasn't collected.
+func findDeployment(b Bundle, namespace, name string) (*appsv1.Deployment, error) {
+	list, err := LoadResources(b, filepath.Join(b.Layout().ClusterResources(), "deployments", namespace))
+	if isNotCollected(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load deployments of namespace %q: %w", namespace, err)
+	}
+	deployments, err := convertList[appsv1.Deployment](list)
+	if err != nil {
+		return nil, err
+	}
+	for i := range deployments {
+		if deployments[i].Name == name {
+			return &deployments[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func isOwnedByDeployment(rs *appsv1.ReplicaSet, name string, deployment *appsv1.Deployment) bool {
+	for _, ref := range rs.OwnerReferences {
+		if ref.Kind != "Deployment" || ref.Name != name {
+			continue
+		}
+		return deployment == nil || ref.UID == "" || deployment.UID == "" || ref.UID == deployment.UID
+	}
+	if len(rs.OwnerReferences) > 0 || deployment == nil || rs.Labels[appsv1.DefaultDeploymentUniqueLabelKey] == "" {
+		return false
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil || selector.Empty() {
+		return false
+	}
+	return selector.Matches(labels.Set(rs.Labels))
+}
@@ -0,0 +1,69 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rolloutReplicaSets = `{"items": [
+  {
+    "metadata": {
+      "name": "web-5d8f7c9b6", "namespace": "default",
+      "labels": {"app": "web", "pod-template-hash": "5d8f7c9b6"},
+      "annotations": {"deployment.kubernetes.io/revision": "2", "kubernetes.io/change-cause": "kubectl set image deployment/web web=web:1.1"},
+      "ownerReferences": [{"apiVersion": "apps/v1", "kind": "Deployment", "name": "web", "uid": "uid-web"}]
+    },
+    "spec": {"template": {"spec": {"containers": [{"name": "web", "image": "web:1.1"}]}}},
+    "status": {"replicas": 3, "readyReplicas": 2}
+  },
+  {
+    "metadata": {
+      "name": "web-7b4c6d8f9", "namespace": "default",
+      "labels": {"app": "web", "pod-template-hash": "7b4c6d8f9"},
+      "annotations": {"deployment.kubernetes.io/revision": "1"},
+      "ownerReferences": [{"apiVersion": "apps/v1", "kind": "Deployment", "name": "web", "uid": "uid-web"}]
+    },
+    "spec": {"template": {"spec": {"containers": [{"name": "web", "image": "web:1.0"}]}}},
+    "status": {"replicas": 0}
+  },
+  {
+    "metadata": {
+      "name": "api-6f5d4c3b2", "namespace": "default",
+      "labels": {"app": "api", "pod-template-hash": "6f5d4c3b2"},
+      "annotations": {"deployment.kubernetes.io/revision": "1"},
+      "ownerReferences": [{"apiVersion": "apps/v1", "kind": "Deployment", "name": "api", "uid": "uid-api"}]
+    }
+  }
+]}`
+
+func TestReconstructRolloutHistory(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/deployments/default.json": `{"items": [{
+  "metadata": {"name": "web", "namespace": "default", "uid": "uid-web"},
+  "spec": {"selector": {"matchLabels": {"app": "web"}}}
+}]}`,
+		"cluster-resources/replicasets/default.json": rolloutReplicaSets,
+	})
+
+	revisions, err := ReconstructRolloutHistory(b, "default", "web")
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+
+	assert.Equal(t, int64(1), revisions[0].Revision)
+	assert.Equal(t, "web-7b4c6d8f9", revisions[0].ReplicaSet)
+	assert.Equal(t, "web:1.0", revisions[0].Template.Spec.Containers[0].Image)
+	assert.Equal(t, int32(0), revisions[0].Replicas)
+
+	assert.Equal(t, int64(2), revisions[1].Revision)
+	assert.Equal(t, "5d8f7c9b6", revisions[1].PodTemplateHash)
+	assert.Equal(t, "kubectl set image deployment/web web=web:1.1", revisions[1].ChangeCause)
+	assert.Equal(t, int32(3), revisions[1].Replicas)
+	assert.Equal(t, int32(2), revisions[1].ReadyReplicas)
+}
+
+func TestReconstructRolloutHistory_NotCollected(t *testing.T) {
+	_, err := ReconstructRolloutHistory(newTestBundle(t, map[string]string{}), "default", "web")
+	assert.Error(t, err)
+}
@@ -0,0 +1,93 @@
+package bundle
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// maxWrapperDirs limits how many nested wrapper directories are detected.
+const maxWrapperDirs = 3
+
+// rootPrefixLayout is implemented by layouts that configure the directory
+// with the bundle data.
+type rootPrefixLayout interface {
+	RootPrefix() string
+}
+
+// bundleRoot returns the directory with the bundle data relative to the root
+// of the filesystem. Extracted bundles often have all files nested under
+// a single directory, e.g. `support-bundle-2024-01-01/cluster-resources`.
+// The directory is configured by the layout config `rootPrefix` or detected
+// as a chain of directories with a single subdirectory that lead to
+// the bundle data. Empty string is returned when the filesystem root contains
+// the bundle data.
+func bundleRoot(fs afero.Fs, l Layout) (string, error) {
+	if rl, ok := l.(rootPrefixLayout); ok && rl.RootPrefix() != "" {
+		root := filepath.Clean(rl.RootPrefix())
+		if filepath.IsAbs(root) || strings.HasPrefix(root, "..") {
+			return "", fmt.Errorf("invalid root prefix %q: must be a relative path inside the bundle", rl.RootPrefix())
+		}
+		if ok, err := afero.DirExists(fs, root); err != nil || !ok {
+			return "", fmt.Errorf("root prefix directory %q doesn't exist in the bundle", root)
+		}
+		return root, nil
+	}
+
+	root := ""
+	for i := 0; i < maxWrapperDirs; i++ {
+		if containsBundleData(fs, root, l) {
+			return root, nil
+		}
+		wrapper, err := singleSubdirectory(fs, root)
+		if err != nil || wrapper == "" {
+			return "", err
+		}
+		root = filepath.Join(root, wrapper)
+	}
+	if containsBundleData(fs, root, l) {
+		return root, nil
+	}
+	return "", nil
+}
+
+// hasRootPrefix checks if the layout configures the root prefix.
+func hasRootPrefix(l Layout) bool {
+	rl, ok := l.(rootPrefixLayout)
+	return ok && rl.RootPrefix() != ""
+}
+
+// containsBundleData checks if the directory contains data of a support bundle
+// or a cluster-info dump.
+func containsBundleData(fs afero.Fs, dir string, l Layout) bool {
+	for _, name := range []string{l.ClusterResources(), clusterInfoDumpNodesFile} {
+		if ok, _ := afero.Exists(fs, filepath.Join(dir, name)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// singleSubdirectory returns the name of the only directory in dir. Hidden
+// files are ignored, e.g. the `.troubleshoot-live` config directory. Empty
+// string is returned when dir contains any other files or directories.
+func singleSubdirectory(fs afero.Fs, dir string) (string, error) {
+	entries, err := afero.ReadDir(fs, valueOrDefault(dir, "."))
+	if err != nil {
+		return "", err
+	}
+
+	found := ""
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if !entry.IsDir() || found != "" {
+			return "", nil
+		}
+		found = entry.Name()
+	}
+	return found, nil
+}
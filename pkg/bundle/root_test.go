@@ -0,0 +1,66 @@
+package bundle
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_WrapperDirectory(t *testing.T) {
+	setupHome(t)
+	dir := t.TempDir()
+	root := filepath.Join(dir, "support-bundle-2024-01-01")
+	writeConfig(t, filepath.Join(root, "cluster-resources", "namespaces.json"), `{"items": []}`)
+	writeConfig(t, filepath.Join(root, "pod-logs", "default", "app-0-app.log"), "logs")
+	writeConfig(t, filepath.Join(dir, ".DS_Store"), "")
+
+	b, err := New(dir)
+	require.NoError(t, err)
+
+	data, err := afero.ReadFile(b, "pod-logs/default/app-0-app.log")
+	require.NoError(t, err)
+	assert.Equal(t, "logs", string(data))
+
+	list, err := LoadResources(b, "cluster-resources/namespaces")
+	require.NoError(t, err)
+	assert.Empty(t, list.Items)
+}
+
+func TestBundleRoot(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "outer/inner/cluster-resources/nodes.json", []byte(`{"items": []}`), 0o600))
+
+	root, err := bundleRoot(fs, defaultLayout{})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("outer", "inner"), root)
+
+	root, err = bundleRoot(fs, configLayout{cfg: LayoutConfig{RootPrefix: "outer"}})
+	require.NoError(t, err)
+	assert.Equal(t, "outer", root, "configured prefix takes precedence")
+
+	_, err = bundleRoot(fs, configLayout{cfg: LayoutConfig{RootPrefix: "../outer"}})
+	assert.Error(t, err)
+
+	_, err = bundleRoot(fs, configLayout{cfg: LayoutConfig{RootPrefix: "missing"}})
+	assert.Error(t, err)
+
+	require.NoError(t, afero.WriteFile(fs, "other/file.txt", []byte("data"), 0o600))
+	root, err = bundleRoot(fs, defaultLayout{})
+	require.NoError(t, err)
+	assert.Equal(t, "", root, "multiple top-level directories are not unwrapped")
+}
+
+func TestNew_WrapperDirectoryConfig(t *testing.T) {
+	setupHome(t)
+	dir := t.TempDir()
+	root := filepath.Join(dir, "support-bundle-2024-01-01")
+	writeConfig(t, filepath.Join(root, "."+ConfigDirName, ConfigFileName), "podLogs: logs\n")
+	writeConfig(t, filepath.Join(root, "cluster-resources", "namespaces.json"), `{"items": []}`)
+
+	b, err := New(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "logs", b.Layout().PodLogs(), "config in the wrapper directory is used")
+}
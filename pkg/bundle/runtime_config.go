@@ -0,0 +1,38 @@
+package bundle
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DetectRuntimeConfig returns API groups and versions enabled or disabled by
+// the `--runtime-config` flag of the kube-apiserver, e.g.
+// `api/all=false,apps/v1=true` is returned as `{"api/all": false, "apps/v1": true}`.
+// Keys without value, e.g. `batch/v2alpha1`, are enabled. The disabled APIs
+// explain resource kinds missing in the bundle. Nil is returned when
+// the bundle doesn't contain the kube-apiserver pod.
+func DetectRuntimeConfig(b Bundle) (map[string]bool, error) {
+	pod, err := findKubeApiserverPod(b)
+	if isNotCollected(err) {
+		return nil, nil
+	}
+	if err != nil || pod == nil {
+		return nil, err
+	}
+
+	config := map[string]bool{}
+	for _, entry := range strings.Split(apiServerFlag(pod, "runtime-config"), ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if key == "" {
+			continue
+		}
+		enabled := true
+		if ok {
+			if enabled, err = strconv.ParseBool(value); err != nil {
+				continue
+			}
+		}
+		config[key] = enabled
+	}
+	return config, nil
+}
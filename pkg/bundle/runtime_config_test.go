@@ -0,0 +1,34 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectRuntimeConfig(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/pods/kube-system.json": `{"items": [{
+  "metadata": {"name": "kube-apiserver-cp-1", "labels": {"component": "kube-apiserver"}},
+  "spec": {"containers": [{
+    "name": "kube-apiserver",
+    "command": ["kube-apiserver", "--runtime-config=api/all=true,batch/v1beta1=false,flowcontrol.apiserver.k8s.io/v1alpha1,storage.k8s.io/v1beta1=invalid"]
+  }]}
+}]}`,
+	})
+
+	config, err := DetectRuntimeConfig(b)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{
+		"api/all":                               true,
+		"batch/v1beta1":                         false,
+		"flowcontrol.apiserver.k8s.io/v1alpha1": true,
+	}, config)
+}
+
+func TestDetectRuntimeConfig_NotPresent(t *testing.T) {
+	config, err := DetectRuntimeConfig(newTestBundle(t, map[string]string{}))
+	require.NoError(t, err)
+	assert.Nil(t, config)
+}
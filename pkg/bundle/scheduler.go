@@ -0,0 +1,141 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/afero"
+	"sigs.k8s.io/yaml"
+)
+
+const schedulerComponent = "kube-scheduler"
+
+// SchedulerProfile contains plugins configured in a scheduler profile.
+type SchedulerProfile struct {
+	SchedulerName   string   `json:"schedulerName"`
+	EnabledPlugins  []string `json:"enabledPlugins,omitempty"`
+	DisabledPlugins []string `json:"disabledPlugins,omitempty"`
+}
+
+// SchedulerConfig contains configuration of kube-scheduler.
+type SchedulerConfig struct {
+	// Flags are all flags passed to the kube-scheduler.
+	Flags map[string]string `json:"flags"`
+	// LeaderElect is the value of the `--leader-elect` flag, which defaults to
+	// true.
+	LeaderElect bool `json:"leaderElect"`
+	// ConfigPath is the value of the `--config` flag.
+	ConfigPath string `json:"configPath,omitempty"`
+	// ConfigSource is the bundle file with the scheduler configuration, empty
+	// if the configuration wasn't collected.
+	ConfigSource string `json:"configSource,omitempty"`
+	// Profiles are parsed from the collected scheduler configuration.
+	Profiles []SchedulerProfile `json:"profiles,omitempty"`
+}
+
+// schedulerConfiguration is a subset of KubeSchedulerConfiguration.
+type schedulerConfiguration struct {
+	Profiles []struct {
+		SchedulerName string `json:"schedulerName"`
+		// Plugins are indexed by extension point, e.g. `score`.
+		Plugins map[string]struct {
+			Enabled  []struct{ Name string } `json:"enabled"`
+			Disabled []struct{ Name string } `json:"disabled"`
+		} `json:"plugins"`
+	} `json:"profiles"`
+}
+
+// DetectSchedulerConfig returns flags of the kube-scheduler static pod and
+// the profiles from the file referenced by the `--config` flag, if a file with
+// the same name was collected to the bundle, e.g. by copy from host collector.
+// Nil is returned when the bundle doesn't contain the scheduler pod, e.g.
+// in managed clusters.
+func DetectSchedulerConfig(b Bundle) (*SchedulerConfig, error) {
+	flags, err := controlPlaneFlags(b, schedulerComponent)
+	if isNotCollected(err) {
+		return nil, nil
+	}
+	if err != nil || flags == nil {
+		return nil, err
+	}
+
+	cfg := &SchedulerConfig{
+		Flags:       flags,
+		LeaderElect: flags["leader-elect"] != "false",
+		ConfigPath:  flags["config"],
+	}
+	if cfg.ConfigPath == "" {
+		return cfg, nil
+	}
+
+	cfg.ConfigSource, err = findCollectedFile(b, filepath.Base(cfg.ConfigPath))
+	if err != nil || cfg.ConfigSource == "" {
+		return cfg, err
+	}
+
+	data, err := afero.ReadFile(b, cfg.ConfigSource)
+	if err != nil {
+		return nil, err
+	}
+	schedulerCfg := &schedulerConfiguration{}
+	if err := yaml.Unmarshal(data, schedulerCfg); err != nil {
+		return nil, fmt.Errorf("failed to parse scheduler config %q: %w", cfg.ConfigSource, err)
+	}
+
+	for _, p := range schedulerCfg.Profiles {
+		profile := SchedulerProfile{SchedulerName: p.SchedulerName}
+		enabled, disabled := map[string]bool{}, map[string]bool{}
+		for _, plugins := range p.Plugins {
+			for _, plugin := range plugins.Enabled {
+				enabled[plugin.Name] = true
+			}
+			for _, plugin := range plugins.Disabled {
+				disabled[plugin.Name] = true
+			}
+		}
+		profile.EnabledPlugins, profile.DisabledPlugins = sortedKeys(enabled), sortedKeys(disabled)
+		cfg.Profiles = append(cfg.Profiles, profile)
+	}
+	return cfg, nil
+}
+
+// findCollectedFile returns the first file with given name outside of
+// the cluster resources and pod logs directories.
+func findCollectedFile(b Bundle, name string) (string, error) {
+	found := ""
+	err := afero.Walk(b, ".", func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if path == b.Layout().ClusterResources() || path == b.Layout().PodLogs() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Name() == name {
+			found = path
+			return errStopWalk
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStopWalk) {
+		return "", err
+	}
+	return found, nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	if len(m) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
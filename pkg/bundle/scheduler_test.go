@@ -0,0 +1,85 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectSchedulerConfig(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/pods/kube-system.json": `{"items": [{
+			"metadata": {"name": "kube-scheduler-cp-1", "namespace": "kube-system", "labels": {"component": "kube-scheduler"}},
+			"spec": {"containers": [{"name": "kube-scheduler", "command": [
+				"kube-scheduler",
+				"--authentication-kubeconfig=/etc/kubernetes/scheduler.conf",
+				"--config=/etc/kubernetes/scheduler-config.yaml",
+				"--leader-elect=false",
+				"--profiling"
+			]}]}
+		}]}`,
+		"host-files/cp-1/scheduler-config.yaml": `
+apiVersion: kubescheduler.config.k8s.io/v1
+kind: KubeSchedulerConfiguration
+profiles:
+- schedulerName: default-scheduler
+  plugins:
+    score:
+      enabled:
+      - name: NodeResourcesBalancedAllocation
+        weight: 2
+      disabled:
+      - name: ImageLocality
+    multiPoint:
+      enabled:
+      - name: PodTopologySpread
+- schedulerName: batch-scheduler
+`,
+	})
+
+	cfg, err := DetectSchedulerConfig(b)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+	assert.False(t, cfg.LeaderElect)
+	assert.Equal(t, "true", cfg.Flags["profiling"])
+	assert.Equal(t, "/etc/kubernetes/scheduler-config.yaml", cfg.ConfigPath)
+	assert.Equal(t, "host-files/cp-1/scheduler-config.yaml", cfg.ConfigSource)
+	assert.Equal(t, []SchedulerProfile{
+		{
+			SchedulerName:   "default-scheduler",
+			EnabledPlugins:  []string{"NodeResourcesBalancedAllocation", "PodTopologySpread"},
+			DisabledPlugins: []string{"ImageLocality"},
+		},
+		{SchedulerName: "batch-scheduler"},
+	}, cfg.Profiles)
+}
+
+func TestDetectSchedulerConfig_WithoutConfigFile(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/pods/kube-system.json": `{"items": [{
+			"metadata": {"name": "kube-scheduler-cp-1", "labels": {"component": "kube-scheduler"}},
+			"spec": {"containers": [{"name": "kube-scheduler", "command": ["kube-scheduler", "--config=/etc/scheduler.yaml"]}]}
+		}]}`,
+	})
+
+	cfg, err := DetectSchedulerConfig(b)
+	require.NoError(t, err)
+	assert.True(t, cfg.LeaderElect)
+	assert.Empty(t, cfg.ConfigSource)
+	assert.Empty(t, cfg.Profiles)
+}
+
+func TestDetectSchedulerConfig_ManagedCluster(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/pods/kube-system.json": `{"items": [{"metadata": {"name": "coredns-1"}}]}`,
+	})
+
+	cfg, err := DetectSchedulerConfig(b)
+	require.NoError(t, err)
+	assert.Nil(t, cfg)
+
+	cfg, err = DetectSchedulerConfig(newTestBundle(t, nil))
+	require.NoError(t, err)
+	assert.Nil(t, cfg)
+}
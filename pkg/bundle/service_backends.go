@@ -0,0 +1,240 @@
+package bundle
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+)
+
+// ServiceBackends describes endpoints of a service and pods behind them.
+type ServiceBackends struct {
+	Namespace string             `json:"namespace"`
+	Service   string             `json:"service"`
+	Type      corev1.ServiceType `json:"type"`
+	Selector  map[string]string  `json:"selector,omitempty"`
+	// Source is the kind of resources the endpoints were loaded from,
+	// `EndpointSlice` or `Endpoints`. Empty when neither was collected.
+	Source    string            `json:"source,omitempty"`
+	Endpoints []ServiceEndpoint `json:"endpoints"`
+}
+
+// ServiceEndpoint is an endpoint address of a service.
+type ServiceEndpoint struct {
+	Address string   `json:"address"`
+	Ports   []string `json:"ports,omitempty"`
+	Node    string   `json:"node,omitempty"`
+	Ready   bool     `json:"ready"`
+	// Pod is the name of the pod referenced by the endpoint target.
+	Pod string `json:"pod,omitempty"`
+	// PodFound is set when the referenced pod is present in the bundle.
+	PodFound bool `json:"podFound"`
+	// PodHealth describes the referenced pod when it exists and isn't ready.
+	PodHealth *PodHealth `json:"podHealth,omitempty"`
+}
+
+// ResolveServiceBackends returns endpoints of the service and the health of
+// pods behind them. The endpoints are read from EndpointSlices labeled with
+// the service name, or from the Endpoints resource when no EndpointSlices
+// were collected. The returned error wraps fs.ErrNotExist when the service is
+// not present in the bundle.
+func ResolveServiceBackends(b Bundle, namespace, service string) (*ServiceBackends, error) {
+	svc, err := findService(b, namespace, service)
+	if err != nil {
+		return nil, err
+	}
+
+	backends := &ServiceBackends{
+		Namespace: namespace,
+		Service:   service,
+		Type:      svc.Spec.Type,
+		Selector:  svc.Spec.Selector,
+		Endpoints: []ServiceEndpoint{},
+	}
+
+	endpoints, err := endpointSliceEndpoints(b, namespace, service)
+	if err != nil {
+		return nil, err
+	}
+	backends.Source = "EndpointSlice"
+	if endpoints == nil {
+		if endpoints, err = legacyEndpoints(b, namespace, service); err != nil {
+			return nil, err
+		}
+		backends.Source = "Endpoints"
+	}
+	if endpoints == nil {
+		backends.Source = ""
+		return backends, nil
+	}
+
+	pods, err := loadPodsByName(b, namespace)
+	if err != nil {
+		return nil, err
+	}
+	for i := range endpoints {
+		if pod, ok := pods[endpoints[i].Pod]; ok && endpoints[i].Pod != "" {
+			endpoints[i].PodFound = true
+			if !isPodReady(pod) {
+				health := podHealth(pod)
+				endpoints[i].PodHealth = &health
+			}
+		}
+	}
+
+	sort.SliceStable(endpoints, func(i, j int) bool { return endpoints[i].Address < endpoints[j].Address })
+	backends.Endpoints = endpoints
+	return backends, nil
+}
+
+func findService(b Bundle, namespace, name string) (*corev1.Service, error) {
+	list, err := loadClusterResources(b, filepath.Join("services", namespace))
+	if err != nil {
+		return nil, err
+	}
+	services, err := convertList[corev1.Service](list)
+	if err != nil {
+		return nil, err
+	}
+	for i := range services {
+		if services[i].Name == name {
+			return &services[i], nil
+		}
+	}
+	return nil, fmt.Errorf("service %q not found in namespace %q: %w", name, namespace, fs.ErrNotExist)
+}
+
+// endpointSliceEndpoints returns endpoints from EndpointSlices of the service.
+// Nil is returned when there aren't any collected EndpointSlices of the
+// service.
+func endpointSliceEndpoints(b Bundle, namespace, service string) ([]ServiceEndpoint, error) {
+	list, err := loadClusterResources(b, filepath.Join("endpointslices", namespace))
+	if isNotCollected(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	slices, err := convertList[discoveryv1.EndpointSlice](list)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []ServiceEndpoint
+	for i := range slices {
+		slice := &slices[i]
+		if slice.Labels[discoveryv1.LabelServiceName] != service {
+			continue
+		}
+		ports := []string{}
+		for _, port := range slice.Ports {
+			if port.Port == nil {
+				continue
+			}
+			name := ""
+			if port.Name != nil {
+				name = *port.Name
+			}
+			ports = append(ports, endpointPort(name, *port.Port))
+		}
+		if endpoints == nil {
+			endpoints = []ServiceEndpoint{}
+		}
+		for _, e := range slice.Endpoints {
+			endpoint := ServiceEndpoint{
+				Ports: ports,
+				// Nil ready condition is interpreted as ready.
+				Ready: e.Conditions.Ready == nil || *e.Conditions.Ready,
+			}
+			if e.NodeName != nil {
+				endpoint.Node = *e.NodeName
+			}
+			if e.TargetRef != nil && e.TargetRef.Kind == "Pod" {
+				endpoint.Pod = e.TargetRef.Name
+			}
+			for _, address := range e.Addresses {
+				endpoint.Address = address
+				endpoints = append(endpoints, endpoint)
+			}
+		}
+	}
+	return endpoints, nil
+}
+
+// legacyEndpoints returns endpoints from the Endpoints resource of the
+// service. Nil is returned when it wasn't collected.
+func legacyEndpoints(b Bundle, namespace, service string) ([]ServiceEndpoint, error) {
+	list, err := loadClusterResources(b, filepath.Join("endpoints", namespace))
+	if isNotCollected(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	items, err := convertList[corev1.Endpoints](list)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range items {
+		if items[i].Name != service {
+			continue
+		}
+		endpoints := []ServiceEndpoint{}
+		for _, subset := range items[i].Subsets {
+			ports := []string{}
+			for _, port := range subset.Ports {
+				ports = append(ports, endpointPort(port.Name, port.Port))
+			}
+			for ready, addresses := range map[bool][]corev1.EndpointAddress{
+				true: subset.Addresses, false: subset.NotReadyAddresses,
+			} {
+				for _, address := range addresses {
+					endpoint := ServiceEndpoint{Address: address.IP, Ports: ports, Ready: ready}
+					if address.NodeName != nil {
+						endpoint.Node = *address.NodeName
+					}
+					if address.TargetRef != nil && address.TargetRef.Kind == "Pod" {
+						endpoint.Pod = address.TargetRef.Name
+					}
+					endpoints = append(endpoints, endpoint)
+				}
+			}
+		}
+		return endpoints, nil
+	}
+	return nil, nil
+}
+
+func endpointPort(name string, port int32) string {
+	if name == "" {
+		return strconv.Itoa(int(port))
+	}
+	return fmt.Sprintf("%s:%d", name, port)
+}
+
+// loadPodsByName returns pods of the namespace indexed by name. Empty map is
+// returned when the pods were not collected.
+func loadPodsByName(b Bundle, namespace string) (map[string]*corev1.Pod, error) {
+	list, err := loadClusterResources(b, filepath.Join("pods", namespace))
+	if isNotCollected(err) {
+		return map[string]*corev1.Pod{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	pods, err := convertList[corev1.Pod](list)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*corev1.Pod, len(pods))
+	for i := range pods {
+		result[pods[i].Name] = &pods[i]
+	}
+	return result, nil
+}
@@ -0,0 +1,86 @@
+package bundle
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveServiceBackends(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/services/default.json": `{"items": [
+  {"metadata": {"name": "web"}, "spec": {"type": "ClusterIP", "selector": {"app": "web"}}}
+]}`,
+		"cluster-resources/endpointslices/default.json": `{"items": [
+  {"metadata": {"name": "web-abc12", "labels": {"kubernetes.io/service-name": "web"}}, "addressType": "IPv4",
+   "ports": [{"name": "http", "port": 8080}],
+   "endpoints": [
+     {"addresses": ["10.0.0.2"], "conditions": {"ready": false}, "nodeName": "worker-2", "targetRef": {"kind": "Pod", "name": "web-1"}},
+     {"addresses": ["10.0.0.1"], "conditions": {"ready": true}, "nodeName": "worker-1", "targetRef": {"kind": "Pod", "name": "web-0"}},
+     {"addresses": ["10.0.0.3"], "conditions": {"ready": true}, "targetRef": {"kind": "Pod", "name": "web-gone"}}
+   ]},
+  {"metadata": {"name": "other-xyz", "labels": {"kubernetes.io/service-name": "other"}}, "addressType": "IPv4",
+   "endpoints": [{"addresses": ["10.0.0.9"]}]}
+]}`,
+		"cluster-resources/pods/default.json": `{"items": [
+  {"metadata": {"name": "web-0"}, "status": {"phase": "Running", "conditions": [{"type": "Ready", "status": "True"}]}},
+  {"metadata": {"name": "web-1"}, "status": {"phase": "Running", "conditions": [{"type": "Ready", "status": "False"}],
+   "containerStatuses": [{"name": "app", "restartCount": 4, "state": {"waiting": {"reason": "CrashLoopBackOff"}}}]}}
+]}`,
+	})
+
+	backends, err := ResolveServiceBackends(b, "default", "web")
+	require.NoError(t, err)
+	assert.Equal(t, "EndpointSlice", backends.Source)
+	assert.Equal(t, map[string]string{"app": "web"}, backends.Selector)
+	require.Len(t, backends.Endpoints, 3)
+
+	ready := backends.Endpoints[0]
+	assert.Equal(t, "10.0.0.1", ready.Address)
+	assert.Equal(t, []string{"http:8080"}, ready.Ports)
+	assert.True(t, ready.Ready)
+	assert.True(t, ready.PodFound)
+	assert.Nil(t, ready.PodHealth)
+
+	notReady := backends.Endpoints[1]
+	assert.False(t, notReady.Ready)
+	require.NotNil(t, notReady.PodHealth)
+	assert.Equal(t, "CrashLoopBackOff", notReady.PodHealth.Reason)
+
+	missing := backends.Endpoints[2]
+	assert.Equal(t, "web-gone", missing.Pod)
+	assert.False(t, missing.PodFound)
+}
+
+func TestResolveServiceBackends_Endpoints(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/services/default.json": `{"items": [{"metadata": {"name": "db"}}]}`,
+		"cluster-resources/endpoints/default.json": `{"items": [
+  {"metadata": {"name": "db"}, "subsets": [{
+    "addresses": [{"ip": "10.0.1.1", "targetRef": {"kind": "Pod", "name": "db-0"}}],
+    "notReadyAddresses": [{"ip": "10.0.1.2", "targetRef": {"kind": "Pod", "name": "db-1"}}],
+    "ports": [{"port": 5432}]
+  }]}
+]}`,
+	})
+
+	backends, err := ResolveServiceBackends(b, "default", "db")
+	require.NoError(t, err)
+	assert.Equal(t, "Endpoints", backends.Source)
+	require.Len(t, backends.Endpoints, 2)
+	assert.True(t, backends.Endpoints[0].Ready)
+	assert.Equal(t, []string{"5432"}, backends.Endpoints[0].Ports)
+	assert.False(t, backends.Endpoints[1].Ready)
+	assert.False(t, backends.Endpoints[1].PodFound)
+}
+
+func TestResolveServiceBackends_MissingService(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/services/default.json": `{"items": []}`,
+	})
+
+	_, err := ResolveServiceBackends(b, "default", "web")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
@@ -1,12 +1,7 @@
 package bundle
 
 import (
-	"fmt"
-	"path/filepath"
-	"strings"
-
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/runtime"
 )
 
 const apiServerContainerName = "kube-apiserver"
@@ -50,64 +45,22 @@ func DetectServiceNodePortRange(b Bundle) (string, error) {
 }
 
 func findKubeApiserverPod(b Bundle) (*corev1.Pod, error) {
-	path := filepath.Join(b.Layout().ClusterResources(), "pods", "kube-system.json")
-	list, err := LoadResourcesFromFile(b, path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load pods from file %q: %w", path, err)
-	}
-
-	for i := range list.Items {
-		pod := &corev1.Pod{}
-		if err := runtime.DefaultUnstructuredConverter.
-			FromUnstructured(list.Items[i].UnstructuredContent(), &pod); err != nil {
-			return nil, err
-		}
-
-		if isKubeApiserverPod(pod) {
-			return pod, nil
-		}
-	}
-
-	return nil, nil
+	return findControlPlanePod(b, apiServerContainerName)
 }
 
 func parseNodePortRangeArg(pod *corev1.Pod) (string, error) {
-	for _, c := range pod.Spec.Containers {
-		if c.Name != apiServerContainerName {
-			continue
-		}
-
-		for _, arg := range c.Command {
-			if strings.HasPrefix(arg, "--service-node-port-range=") {
-				return strings.TrimPrefix(arg, "--service-node-port-range="), nil
-			}
-		}
-	}
-
-	return "", nil
+	return apiServerFlag(pod, "service-node-port-range"), nil
 }
 
 func parseIPRangeArg(pod *corev1.Pod) (string, error) {
-	for _, c := range pod.Spec.Containers {
-		if c.Name != apiServerContainerName {
-			continue
-		}
-
-		for _, arg := range c.Command {
-			if strings.HasPrefix(arg, "--service-cluster-ip-range=") {
-				return strings.TrimPrefix(arg, "--service-cluster-ip-range="), nil
-			}
-		}
-	}
-
-	return "", nil
+	return apiServerFlag(pod, "service-cluster-ip-range"), nil
 }
 
-func isKubeApiserverPod(pod *corev1.Pod) bool {
-	if !strings.HasPrefix(pod.GetName(), "kube-apiserver-") {
-		return false
+func apiServerFlag(pod *corev1.Pod, name string) string {
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == apiServerContainerName {
+			return containerFlags(&pod.Spec.Containers[i])[name]
+		}
 	}
-
-	labels := pod.GetLabels()
-	return labels["component"] == "kube-apiserver"
+	return ""
 }
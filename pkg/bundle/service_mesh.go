@@ -0,0 +1,135 @@
+package bundle
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Names of service meshes reported by DetectServiceMesh.
+const (
+	MeshIstio   = "istio"
+	MeshLinkerd = "linkerd"
+)
+
+// MeshInfo describes the service mesh detected in the cluster.
+type MeshInfo struct {
+	// Name is MeshIstio or MeshLinkerd.
+	Name string `json:"name"`
+	// ControlPlane lists control plane deployments as `<namespace>/<name>`.
+	ControlPlane []string `json:"controlPlane"`
+	// InjectionNamespaces are namespaces with sidecar injection enabled.
+	InjectionNamespaces []string `json:"injectionNamespaces"`
+	// SidecarPods is the number of pods with an injected sidecar.
+	SidecarPods int `json:"sidecarPods"`
+}
+
+// meshDetector describes how a service mesh marks injected namespaces and
+// pods.
+type meshDetector struct {
+	name string
+	// injectionEnabled reports if the namespace has sidecar injection
+	// enabled.
+	injectionEnabled func(ns *unstructured.Unstructured) bool
+	// sidecarAnnotation is the pod annotation added by the injector.
+	sidecarAnnotation string
+	// controlPlane are names of the control plane deployments.
+	controlPlane map[string]bool
+}
+
+func meshDetectors() []meshDetector {
+	return []meshDetector{
+		{
+			name: MeshIstio,
+			injectionEnabled: func(ns *unstructured.Unstructured) bool {
+				_, revision := ns.GetLabels()["istio.io/rev"]
+				return ns.GetLabels()["istio-injection"] == "enabled" || revision
+			},
+			sidecarAnnotation: "sidecar.istio.io/status",
+			controlPlane:      map[string]bool{"istiod": true},
+		},
+		{
+			name: MeshLinkerd,
+			injectionEnabled: func(ns *unstructured.Unstructured) bool {
+				return ns.GetAnnotations()["linkerd.io/inject"] == "enabled"
+			},
+			sidecarAnnotation: "linkerd.io/proxy-version",
+			controlPlane: map[string]bool{
+				"linkerd-destination": true, "linkerd-identity": true, "linkerd-proxy-injector": true,
+			},
+		},
+	}
+}
+
+// DetectServiceMesh detects Istio or Linkerd from namespaces with sidecar
+// injection enabled, e.g. by the `istio-injection=enabled` label, pods with
+// the sidecar status annotation, e.g. `sidecar.istio.io/status`, and
+// the control plane deployments. When both meshes are found, the one with more
+// sidecar pods is returned. Nil is returned when no mesh is detected.
+// Resources that weren't collected are skipped.
+func DetectServiceMesh(b Bundle) (*MeshInfo, error) {
+	resources := map[string]*unstructured.UnstructuredList{}
+	for _, dir := range []string{"namespaces", "pods", "deployments"} {
+		list, err := loadMeshResources(b, dir)
+		if err != nil {
+			return nil, err
+		}
+		resources[dir] = list
+	}
+
+	var detected *MeshInfo
+	for _, detector := range meshDetectors() {
+		info := detector.detect(resources["namespaces"], resources["pods"], resources["deployments"])
+		if info == nil {
+			continue
+		}
+		if detected == nil || info.SidecarPods > detected.SidecarPods {
+			detected = info
+		}
+	}
+	return detected, nil
+}
+
+// loadMeshResources loads cluster scoped namespaces or namespaced resources
+// from all namespaces. Empty list is returned when they weren't collected.
+func loadMeshResources(b Bundle, dir string) (*unstructured.UnstructuredList, error) {
+	var list *unstructured.UnstructuredList
+	var err error
+	if dir == "namespaces" {
+		list, err = loadClusterResources(b, dir)
+	} else {
+		list, err = loadNamespacedResources(b, dir)
+	}
+	if isNotCollected(err) {
+		return &unstructured.UnstructuredList{}, nil
+	}
+	return list, err
+}
+
+func (d meshDetector) detect(namespaces, pods, deployments *unstructured.UnstructuredList) *MeshInfo {
+	info := &MeshInfo{Name: d.name, ControlPlane: []string{}, InjectionNamespaces: []string{}}
+	for i := range namespaces.Items {
+		if d.injectionEnabled(&namespaces.Items[i]) {
+			info.InjectionNamespaces = append(info.InjectionNamespaces, namespaces.Items[i].GetName())
+		}
+	}
+	for i := range pods.Items {
+		if _, ok := pods.Items[i].GetAnnotations()[d.sidecarAnnotation]; ok {
+			info.SidecarPods++
+		}
+	}
+	for i := range deployments.Items {
+		if d.controlPlane[deployments.Items[i].GetName()] {
+			info.ControlPlane = append(info.ControlPlane,
+				fmt.Sprintf("%s/%s", deployments.Items[i].GetNamespace(), deployments.Items[i].GetName()))
+		}
+	}
+
+	if len(info.InjectionNamespaces) == 0 && info.SidecarPods == 0 && len(info.ControlPlane) == 0 {
+		return nil
+	}
+	sort.Strings(info.InjectionNamespaces)
+	sort.Strings(info.ControlPlane)
+	return info
+}
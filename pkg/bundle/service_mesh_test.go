@@ -0,0 +1,67 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectServiceMesh_Istio(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/namespaces.json": `{"items": [
+  {"metadata": {"name": "default", "labels": {"istio-injection": "enabled"}}},
+  {"metadata": {"name": "shop", "labels": {"istio.io/rev": "1-20"}}},
+  {"metadata": {"name": "kube-system"}}
+]}`,
+		"cluster-resources/pods/default.json": `{"items": [
+  {"metadata": {"name": "web", "annotations": {"sidecar.istio.io/status": "{\"containers\":[\"istio-proxy\"]}"}}},
+  {"metadata": {"name": "job"}}
+]}`,
+		"cluster-resources/deployments/istio-system.json": `{"items": [
+  {"metadata": {"name": "istiod", "namespace": "istio-system"}}
+]}`,
+	})
+
+	info, err := DetectServiceMesh(b)
+	require.NoError(t, err)
+	assert.Equal(t, &MeshInfo{
+		Name:                MeshIstio,
+		ControlPlane:        []string{"istio-system/istiod"},
+		InjectionNamespaces: []string{"default", "shop"},
+		SidecarPods:         1,
+	}, info)
+}
+
+func TestDetectServiceMesh_Linkerd(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/namespaces.json": `{"items": [
+  {"metadata": {"name": "emojivoto", "annotations": {"linkerd.io/inject": "enabled"}}}
+]}`,
+		"cluster-resources/pods/emojivoto.json": `{"items": [
+  {"metadata": {"name": "web", "annotations": {"linkerd.io/proxy-version": "stable-2.14.0"}}},
+  {"metadata": {"name": "voting", "annotations": {"linkerd.io/proxy-version": "stable-2.14.0"}}}
+]}`,
+		"cluster-resources/deployments/linkerd.json": `{"items": [
+  {"metadata": {"name": "linkerd-destination", "namespace": "linkerd"}},
+  {"metadata": {"name": "linkerd-identity", "namespace": "linkerd"}}
+]}`,
+	})
+
+	info, err := DetectServiceMesh(b)
+	require.NoError(t, err)
+	assert.Equal(t, MeshLinkerd, info.Name)
+	assert.Equal(t, []string{"linkerd/linkerd-destination", "linkerd/linkerd-identity"}, info.ControlPlane)
+	assert.Equal(t, []string{"emojivoto"}, info.InjectionNamespaces)
+	assert.Equal(t, 2, info.SidecarPods)
+}
+
+func TestDetectServiceMesh_None(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/namespaces.json": `{"items": [{"metadata": {"name": "default"}}]}`,
+	})
+
+	info, err := DetectServiceMesh(b)
+	require.NoError(t, err)
+	assert.Nil(t, info)
+}
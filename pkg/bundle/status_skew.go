@@ -0,0 +1,71 @@
+package bundle
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// StatusSkew describes a workload whose status was not updated by the
+// controller for the latest spec.
+type StatusSkew struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Generation is the `metadata.generation` of the workload spec.
+	Generation int64 `json:"generation"`
+	// ObservedGeneration is the generation for which the controller last
+	// updated the status.
+	ObservedGeneration int64 `json:"observedGeneration"`
+}
+
+// DetectResourceVersionSkew reports Deployments, StatefulSets and
+// DaemonSets whose `status.observedGeneration` lags behind
+// `metadata.generation`, which means that the controller didn't reconcile
+// the latest spec before the bundle was collected. Resources that weren't
+// collected are skipped.
+func DetectResourceVersionSkew(b Bundle) ([]StatusSkew, error) {
+	workloads := []struct {
+		dir  string
+		kind string
+	}{
+		{"deployments", "Deployment"},
+		{"statefulsets", "StatefulSet"},
+		{"daemonsets", "DaemonSet"},
+	}
+
+	skews := []StatusSkew{}
+	for _, w := range workloads {
+		list, err := loadNamespacedResources(b, w.dir)
+		if isNotCollected(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var found []StatusSkew
+		for i := range list.Items {
+			item := &list.Items[i]
+			observed, ok, _ := unstructured.NestedInt64(item.Object, "status", "observedGeneration")
+			if !ok || observed >= item.GetGeneration() {
+				continue
+			}
+			found = append(found, StatusSkew{
+				Kind:               w.kind,
+				Namespace:          item.GetNamespace(),
+				Name:               item.GetName(),
+				Generation:         item.GetGeneration(),
+				ObservedGeneration: observed,
+			})
+		}
+		sort.Slice(found, func(i, j int) bool {
+			if found[i].Namespace != found[j].Namespace {
+				return found[i].Namespace < found[j].Namespace
+			}
+			return found[i].Name < found[j].Name
+		})
+		skews = append(skews, found...)
+	}
+	return skews, nil
+}
@@ -0,0 +1,28 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectResourceVersionSkew(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/deployments/default.json": `{"items": [
+  {"metadata": {"name": "web", "namespace": "default", "generation": 7}, "status": {"observedGeneration": 5}},
+  {"metadata": {"name": "api", "namespace": "default", "generation": 3}, "status": {"observedGeneration": 3}},
+  {"metadata": {"name": "new", "namespace": "default", "generation": 1}, "status": {}}
+]}`,
+		"cluster-resources/statefulsets/db.json": `{"items": [
+  {"metadata": {"name": "postgres", "namespace": "db", "generation": 2}, "status": {"observedGeneration": 1}}
+]}`,
+	})
+
+	skews, err := DetectResourceVersionSkew(b)
+	require.NoError(t, err)
+	assert.Equal(t, []StatusSkew{
+		{Kind: "Deployment", Namespace: "default", Name: "web", Generation: 7, ObservedGeneration: 5},
+		{Kind: "StatefulSet", Namespace: "db", Name: "postgres", Generation: 2, ObservedGeneration: 1},
+	}, skews)
+}
@@ -0,0 +1,114 @@
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// CustomResourcesDir is the cluster resources directory with custom resources
+// stored as `<crd>.json` for cluster scoped resources and as
+// `<crd>/<namespace>.json` for namespaced resources.
+const CustomResourcesDir = "custom-resources"
+
+// StreamAllResources writes all resources from cluster resources to the
+// writer as NDJSON, one item per line. See StreamAllResourcesContext.
+func StreamAllResources(b Bundle, w io.Writer) error {
+	return StreamAllResourcesContext(context.Background(), b, w)
+}
+
+// StreamAllResourcesContext writes all resources from cluster resources to
+// the writer as NDJSON, one item per line. Files are loaded one at a time,
+// so the whole bundle is never kept in memory. Files and directories skipped
+// by the layout are not written, same as files that don't contain resources.
+// Kind detected from the file path and namespace from the file name of
+// namespaced files are set on items without them. Streaming stops when the
+// context is canceled.
+func StreamAllResourcesContext(ctx context.Context, b Bundle, w io.Writer) error {
+	enc := json.NewEncoder(w)
+	root := b.Layout().ClusterResources()
+	err := afero.Walk(b, root, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != root && slices.Contains(b.Layout().SkipDirs(), info.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		// Chunks are loaded together when the first chunk is visited.
+		if base, part, ok := SplitChunkPath(path); ok {
+			if part != 1 {
+				return nil
+			}
+			path = base
+		}
+		if b.Layout().SkipResourceMatches(path) || strings.Contains(filepath.Base(path), "-errors.") {
+			return nil
+		}
+
+		list, err := LoadResourcesFromFile(b, path)
+		if err != nil {
+			// Not a resource list.
+			return nil
+		}
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		return streamResources(ctx, enc, b.Layout(), relPath, list.Items)
+	})
+	if isNotCollected(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stream cluster resources: %w", err)
+	}
+	return nil
+}
+
+func streamResources(ctx context.Context, enc *json.Encoder, l Layout, path string, items []unstructured.Unstructured) error {
+	gvk := GVKFromPath(l, path)
+	namespace := namespaceFromPath(path)
+	for i := range items {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		item := &items[i]
+		if item.GetKind() == "" && !gvk.Empty() {
+			item.SetGroupVersionKind(gvk)
+		}
+		if item.GetNamespace() == "" && namespace != "" {
+			item.SetNamespace(namespace)
+		}
+		if err := enc.Encode(item.Object); err != nil {
+			return fmt.Errorf("failed to write resource from %q: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// namespaceFromPath returns namespace of resources stored per namespace, e.g.
+// `default` for `pods/default.json`. Empty string is returned for files with
+// cluster scoped resources, e.g. `nodes.json`.
+func namespaceFromPath(path string) string {
+	dir := filepath.Dir(path)
+	if dir == "." || dir == CustomResourcesDir {
+		return ""
+	}
+	name := strings.TrimSuffix(filepath.Base(path), ".gz")
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
@@ -0,0 +1,84 @@
+package bundle
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func streamBundleFiles() map[string]string {
+	return map[string]string{
+		"cluster-resources/nodes.json": `{"items": [{"metadata": {"name": "node-1"}}]}`,
+		"cluster-resources/pods/default.json": `[
+			{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "app-0", "namespace": "default"}},
+			{"metadata": {"name": "app-1"}}
+		]`,
+		"cluster-resources/pods/kube-system.json.part1":      `[{"metadata": {"name": "dns"}},`,
+		"cluster-resources/pods/kube-system.json.part2":      `{"metadata": {"name": "proxy"}}]`,
+		"cluster-resources/pods/kube-system-errors.json":     `["failed to list pods"]`,
+		"cluster-resources/groups.json":                      `[{"name": "apps"}]`,
+		"cluster-resources/auth-cani-list/default.json":      `[{"metadata": {"name": "skipped"}}]`,
+		"cluster-resources/custom-resources/a.b.io.yaml":     "- apiVersion: b.io/v1\n  kind: A\n  metadata:\n    name: cluster-a\n",
+		"cluster-resources/custom-resources/c.b.io/ns.json":  `[{"apiVersion": "b.io/v1", "kind": "C", "metadata": {"name": "c-0"}}]`,
+		"cluster-resources/pod-disruption-budgets-info.json": `[{"metadata": {"name": "skipped"}}]`,
+		"cluster-resources/cluster-version.txt":              `not a resource`,
+	}
+}
+
+func TestStreamAllResources(t *testing.T) {
+	b := newTestBundle(t, streamBundleFiles())
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, StreamAllResources(b, buf))
+
+	got := map[string]string{}
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var item struct {
+			APIVersion string `json:"apiVersion"`
+			Kind       string `json:"kind"`
+			Metadata   struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+		}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &item), "line %q is not valid JSON", scanner.Text())
+		got[item.Metadata.Name] = item.APIVersion + " " + item.Kind + " " + item.Metadata.Namespace
+	}
+	require.NoError(t, scanner.Err())
+
+	assert.Equal(t, map[string]string{
+		"node-1":    "v1 Node ",
+		"app-0":     "v1 Pod default",
+		"app-1":     "v1 Pod default",
+		"dns":       "v1 Pod kube-system",
+		"proxy":     "v1 Pod kube-system",
+		"cluster-a": "b.io/v1 A ",
+		"c-0":       "b.io/v1 C ns",
+	}, got)
+}
+
+func TestStreamAllResources_NotCollected(t *testing.T) {
+	b := newTestBundle(t, map[string]string{})
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, StreamAllResources(b, buf))
+	assert.Empty(t, buf.String())
+}
+
+func TestStreamAllResourcesContext_Canceled(t *testing.T) {
+	b := newTestBundle(t, streamBundleFiles())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	buf := &bytes.Buffer{}
+	err := StreamAllResourcesContext(ctx, b, buf)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, buf.String())
+}
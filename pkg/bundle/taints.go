@@ -0,0 +1,24 @@
+package bundle
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ListNodeTaints returns taints of each node from the bundle indexed by node
+// name. Nodes without taints are included with an empty list.
+func ListNodeTaints(b Bundle) (map[string][]corev1.Taint, error) {
+	nodes, err := loadNodes(b)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]corev1.Taint, len(nodes))
+	for i := range nodes {
+		taints := nodes[i].Spec.Taints
+		if taints == nil {
+			taints = []corev1.Taint{}
+		}
+		result[nodes[i].GetName()] = taints
+	}
+	return result, nil
+}
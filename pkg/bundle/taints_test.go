@@ -0,0 +1,35 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestListNodeTaints(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/nodes.json": `{"items": [
+			{"metadata": {"name": "control-plane"}, "spec": {"taints": [
+				{"key": "node-role.kubernetes.io/control-plane", "effect": "NoSchedule"}
+			]}},
+			{"metadata": {"name": "gpu"}, "spec": {"taints": [
+				{"key": "nvidia.com/gpu", "value": "true", "effect": "NoSchedule"},
+				{"key": "node.kubernetes.io/unreachable", "effect": "NoExecute"}
+			]}},
+			{"metadata": {"name": "worker"}, "spec": {}}
+		]}`,
+	})
+
+	taints, err := ListNodeTaints(b)
+	require.NoError(t, err)
+	require.Len(t, taints, 3)
+	assert.Equal(t, []corev1.Taint{
+		{Key: "node-role.kubernetes.io/control-plane", Effect: corev1.TaintEffectNoSchedule},
+	}, taints["control-plane"])
+	require.Len(t, taints["gpu"], 2)
+	assert.Equal(t, "true", taints["gpu"][0].Value)
+	assert.Equal(t, corev1.TaintEffectNoExecute, taints["gpu"][1].Effect)
+	assert.Empty(t, taints["worker"])
+}
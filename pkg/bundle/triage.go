@@ -0,0 +1,230 @@
+package bundle
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Masterminds/semver/v3"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Severities of triage findings.
+const (
+	TriageCritical = "critical"
+	TriageWarning  = "warning"
+	TriageInfo     = "info"
+)
+
+// Names of checks aggregated by the triage.
+const (
+	TriageCheckUnhealthyPods  = "unhealthy-pods"
+	TriageCheckNodeConditions = "node-conditions"
+	TriageCheckDeprecatedAPIs = "deprecated-apis"
+	TriageCheckReferences     = "dangling-references"
+	TriageCheckPodCIDRs       = "pod-cidr-overlaps"
+)
+
+// TriageFinding is a single issue reported by the triage.
+type TriageFinding struct {
+	Severity string `json:"severity"`
+	Check    string `json:"check"`
+	// Object identifies the affected resource, e.g. `Pod default/app-0`.
+	Object  string `json:"object"`
+	Message string `json:"message"`
+}
+
+// Triage is a prioritized report of issues found in the bundle.
+type Triage struct {
+	// Findings are ordered by severity, starting with critical findings.
+	Findings []TriageFinding `json:"findings"`
+	// Counts contains number of findings per severity.
+	Counts map[string]int `json:"counts"`
+	// Skipped contains checks that didn't run because their inputs weren't
+	// collected, indexed by the check name.
+	Skipped map[string]string `json:"skipped,omitempty"`
+}
+
+func (t *Triage) add(severity, check, object, message string) {
+	t.Findings = append(t.Findings, TriageFinding{Severity: severity, Check: check, Object: object, Message: message})
+	t.Counts[severity]++
+}
+
+func (t *Triage) skip(check, reason string) {
+	if t.Skipped == nil {
+		t.Skipped = map[string]string{}
+	}
+	t.Skipped[check] = reason
+}
+
+// TriageSummary aggregates unhealthy pods, node conditions, deprecated APIs,
+// references to missing resources and overlapping pod CIDRs of nodes into
+// a single report. Checks with inputs that weren't collected are reported as
+// skipped, e.g. deprecated APIs aren't checked when the cluster version is
+// unknown.
+func TriageSummary(b Bundle) (*Triage, error) {
+	t := &Triage{Findings: []TriageFinding{}, Counts: map[string]int{
+		TriageCritical: 0,
+		TriageWarning:  0,
+		TriageInfo:     0,
+	}}
+
+	for _, check := range []func(Bundle, *Triage) error{
+		triageUnhealthyPods,
+		triageNodeConditions,
+		triageDeprecatedAPIs,
+		triageReferences,
+		triagePodCIDRs,
+	} {
+		if err := check(b, t); err != nil {
+			return nil, err
+		}
+	}
+
+	rank := map[string]int{TriageCritical: 0, TriageWarning: 1, TriageInfo: 2}
+	sort.SliceStable(t.Findings, func(i, j int) bool {
+		return rank[t.Findings[i].Severity] < rank[t.Findings[j].Severity]
+	})
+	return t, nil
+}
+
+// criticalPodReasons are reasons of pods that won't recover without changes.
+func criticalPodReasons() map[string]bool {
+	return map[string]bool{
+		string(corev1.PodFailed):     true,
+		"CrashLoopBackOff":           true,
+		"ImagePullBackOff":           true,
+		"ErrImagePull":               true,
+		"CreateContainerConfigError": true,
+		"InvalidImageName":           true,
+	}
+}
+
+func triageUnhealthyPods(b Bundle, t *Triage) error {
+	pods, err := ListUnhealthyPods(b)
+	if err != nil {
+		return err
+	}
+	if pods == nil {
+		t.skip(TriageCheckUnhealthyPods, "pods were not collected")
+		return nil
+	}
+
+	for i := range pods {
+		pod := &pods[i]
+		severity := TriageWarning
+		if criticalPodReasons()[pod.Reason] || pod.LastTerminationReason == "OOMKilled" {
+			severity = TriageCritical
+		}
+		message := fmt.Sprintf("pod is not ready: %s", pod.Reason)
+		if pod.Restarts > 0 {
+			message += fmt.Sprintf(", %d restarts", pod.Restarts)
+		}
+		if pod.LastTerminationReason != "" {
+			message += fmt.Sprintf(", last terminated with %s", pod.LastTerminationReason)
+		}
+		t.add(severity, TriageCheckUnhealthyPods, fmt.Sprintf("Pod %s/%s", pod.Namespace, pod.Name), message)
+	}
+	return nil
+}
+
+func triageNodeConditions(b Bundle, t *Triage) error {
+	conditions, err := ListNodeConditions(b)
+	if isNotCollected(err) {
+		t.skip(TriageCheckNodeConditions, "nodes were not collected")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(conditions))
+	for name := range conditions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, c := range conditions[name] {
+			if !c.Unhealthy {
+				continue
+			}
+			severity := TriageWarning
+			if c.Type == corev1.NodeReady {
+				severity = TriageCritical
+			}
+			message := fmt.Sprintf("condition %s is %s", c.Type, c.Status)
+			if c.Reason != "" {
+				message += fmt.Sprintf(": %s", c.Reason)
+			}
+			t.add(severity, TriageCheckNodeConditions, "Node "+name, message)
+		}
+	}
+	return nil
+}
+
+// triageDeprecatedAPIs reports resources with APIs deprecated in the version
+// of the cluster, which have to be migrated before the cluster is upgraded.
+func triageDeprecatedAPIs(b Bundle, t *Triage) error {
+	version, err := DetectClusterVersion(b)
+	if err != nil {
+		return err
+	}
+	if version == "" {
+		t.skip(TriageCheckDeprecatedAPIs, "cluster version was not collected")
+		return nil
+	}
+	if _, err := semver.NewVersion(version); err != nil {
+		t.skip(TriageCheckDeprecatedAPIs, fmt.Sprintf("cluster version %q cannot be parsed", version))
+		return nil
+	}
+
+	deprecations, err := DetectDeprecatedAPIs(b, version)
+	if err != nil {
+		return err
+	}
+	for i := range deprecations {
+		d := &deprecations[i]
+		object := fmt.Sprintf("%s %s", d.Kind, d.Name)
+		if d.Namespace != "" {
+			object = fmt.Sprintf("%s %s/%s", d.Kind, d.Namespace, d.Name)
+		}
+		message := fmt.Sprintf("%s is deprecated and removed in %s", d.APIVersion, d.RemovedIn)
+		if d.Replacement != "" {
+			message += fmt.Sprintf(", use %s", d.Replacement)
+		}
+		if d.Removed {
+			t.add(TriageWarning, TriageCheckDeprecatedAPIs, object, message)
+			continue
+		}
+		t.add(TriageInfo, TriageCheckDeprecatedAPIs, object, message)
+	}
+	return nil
+}
+
+func triageReferences(b Bundle, t *Triage) error {
+	issues, err := ValidateReferences(b)
+	if err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		t.add(TriageWarning, TriageCheckReferences, fmt.Sprintf("Pod %s/%s", issue.Namespace, issue.Pod),
+			fmt.Sprintf("references missing %s %q in %s", issue.Kind, issue.Name, issue.Source))
+	}
+	return nil
+}
+
+func triagePodCIDRs(b Bundle, t *Triage) error {
+	cidrs, err := ListNodePodCIDRs(b)
+	if isNotCollected(err) {
+		t.skip(TriageCheckPodCIDRs, "nodes were not collected")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, overlap := range FindPodCIDROverlaps(cidrs) {
+		t.add(TriageWarning, TriageCheckPodCIDRs, "Node "+overlap.Node,
+			fmt.Sprintf("pod CIDR %s overlaps with pod CIDR %s of node %s", overlap.CIDR, overlap.OtherCIDR, overlap.OtherNode))
+	}
+	return nil
+}
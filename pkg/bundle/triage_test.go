@@ -0,0 +1,104 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// triageBundleFiles is a bundle with a crashing pod, a pod referencing
+// a missing ConfigMap, a node that is not ready and a deprecated Ingress.
+func triageBundleFiles() map[string]string {
+	return map[string]string{
+		"cluster-info/cluster_version.json": `{"info": {"gitVersion": "v1.21.4"}, "string": "v1.21.4"}`,
+		"cluster-resources/nodes.json": `{"items": [
+			{"metadata": {"name": "node-1"}, "status": {"conditions": [
+				{"type": "Ready", "status": "True"},
+				{"type": "DiskPressure", "status": "True", "reason": "KubeletHasDiskPressure"}
+			]}},
+			{"metadata": {"name": "node-2"}, "status": {"conditions": [
+				{"type": "Ready", "status": "Unknown", "reason": "NodeStatusUnknown"}
+			]}}
+		]}`,
+		"cluster-resources/pods/default.json": `{"items": [
+			{
+				"metadata": {"name": "api", "namespace": "default"},
+				"status": {"phase": "Running", "containerStatuses": [{
+					"name": "api", "restartCount": 7,
+					"state": {"waiting": {"reason": "CrashLoopBackOff"}},
+					"lastState": {"terminated": {"reason": "OOMKilled", "exitCode": 137}}
+				}]}
+			},
+			{
+				"metadata": {"name": "web", "namespace": "default"},
+				"spec": {"volumes": [{"name": "config", "configMap": {"name": "web-config"}}]},
+				"status": {"phase": "Running", "conditions": [{"type": "Ready", "status": "True"}]}
+			},
+			{
+				"metadata": {"name": "ok", "namespace": "default"},
+				"status": {"phase": "Running", "conditions": [{"type": "Ready", "status": "True"}]}
+			}
+		]}`,
+		"cluster-resources/configmaps/default.json": `{"items": [{"metadata": {"name": "other", "namespace": "default"}}]}`,
+		"cluster-resources/ingress/default.json": `{"items": [
+			{"apiVersion": "extensions/v1beta1", "kind": "Ingress", "metadata": {"name": "web", "namespace": "default"}}
+		]}`,
+	}
+}
+
+func TestTriageSummary(t *testing.T) {
+	triage, err := TriageSummary(newTestBundle(t, triageBundleFiles()))
+	require.NoError(t, err)
+
+	assert.Equal(t, []TriageFinding{
+		{
+			Severity: TriageCritical, Check: TriageCheckUnhealthyPods, Object: "Pod default/api",
+			Message: "pod is not ready: CrashLoopBackOff, 7 restarts, last terminated with OOMKilled",
+		},
+		{
+			Severity: TriageCritical, Check: TriageCheckNodeConditions, Object: "Node node-2",
+			Message: "condition Ready is Unknown: NodeStatusUnknown",
+		},
+		{
+			Severity: TriageWarning, Check: TriageCheckNodeConditions, Object: "Node node-1",
+			Message: "condition DiskPressure is True: KubeletHasDiskPressure",
+		},
+		{
+			Severity: TriageWarning, Check: TriageCheckReferences, Object: "Pod default/web",
+			Message: `references missing ConfigMap "web-config" in volume "config"`,
+		},
+		{
+			Severity: TriageInfo, Check: TriageCheckDeprecatedAPIs, Object: "Ingress default/web",
+			Message: "extensions/v1beta1 is deprecated and removed in 1.22, use networking.k8s.io/v1",
+		},
+	}, triage.Findings)
+	assert.Equal(t, map[string]int{TriageCritical: 2, TriageWarning: 2, TriageInfo: 1}, triage.Counts)
+	assert.Empty(t, triage.Skipped)
+}
+
+func TestTriageSummary_MissingInputs(t *testing.T) {
+	triage, err := TriageSummary(newTestBundle(t, map[string]string{
+		"cluster-resources/namespaces.json": `{"items": []}`,
+	}))
+	require.NoError(t, err)
+
+	assert.Empty(t, triage.Findings)
+	assert.Equal(t, map[string]int{TriageCritical: 0, TriageWarning: 0, TriageInfo: 0}, triage.Counts)
+	assert.Equal(t, map[string]string{
+		TriageCheckUnhealthyPods:  "pods were not collected",
+		TriageCheckNodeConditions: "nodes were not collected",
+		TriageCheckDeprecatedAPIs: "cluster version was not collected",
+		TriageCheckPodCIDRs:       "nodes were not collected",
+	}, triage.Skipped)
+}
+
+func TestTriageSummary_PodCIDROverlaps(t *testing.T) {
+	triage, err := TriageSummary(newTestBundle(t, podCIDRsBundleFiles()))
+	require.NoError(t, err)
+
+	assert.Contains(t, triage.Findings, TriageFinding{
+		Severity: TriageWarning, Check: TriageCheckPodCIDRs, Object: "Node node-2",
+		Message: "pod CIDR 10.244.1.0/24 overlaps with pod CIDR 10.244.1.128/25 of node node-3",
+	})
+}
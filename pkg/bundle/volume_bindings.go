@@ -0,0 +1,129 @@
+package bundle
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// VolumeBinding is a PersistentVolumeClaim joined with its bound
+// PersistentVolume. PersistentVolumes that aren't bound to any claim from
+// the bundle are reported with empty claim.
+type VolumeBinding struct {
+	Namespace  string                            `json:"namespace,omitempty"`
+	Claim      string                            `json:"claim,omitempty"`
+	ClaimPhase corev1.PersistentVolumeClaimPhase `json:"claimPhase,omitempty"`
+
+	Volume      string                       `json:"volume,omitempty"`
+	VolumePhase corev1.PersistentVolumePhase `json:"volumePhase,omitempty"`
+
+	StorageClass string `json:"storageClass,omitempty"`
+	// Capacity is the capacity of the volume, or the requested storage of
+	// claims without volume.
+	Capacity string `json:"capacity,omitempty"`
+	// Bound is set when the claim and the volume reference each other.
+	Bound bool `json:"bound"`
+	// Reason explains why the claim or the volume isn't bound.
+	Reason string `json:"reason,omitempty"`
+}
+
+// ListVolumeBindings returns PersistentVolumeClaims from all namespaces
+// joined with the PersistentVolumes referenced by `spec.volumeName` and
+// `spec.claimRef`, followed by volumes without claims. Resources that
+// weren't collected are treated as empty.
+func ListVolumeBindings(b Bundle) ([]VolumeBinding, error) {
+	pvs, err := loadOptionalCluster[corev1.PersistentVolume](b, "pvs")
+	if err != nil {
+		return nil, err
+	}
+	pvcList, err := loadNamespacedResources(b, "pvcs")
+	if err != nil && !isNotCollected(err) {
+		return nil, err
+	}
+	var pvcs []corev1.PersistentVolumeClaim
+	if pvcList != nil {
+		if pvcs, err = convertList[corev1.PersistentVolumeClaim](pvcList); err != nil {
+			return nil, err
+		}
+	}
+
+	volumes := make(map[string]*corev1.PersistentVolume, len(pvs))
+	for i := range pvs {
+		volumes[pvs[i].Name] = &pvs[i]
+	}
+
+	claimed := map[string]bool{}
+	bindings := []VolumeBinding{}
+	for i := range pvcs {
+		binding := claimBinding(&pvcs[i], volumes)
+		if binding.Bound {
+			claimed[binding.Volume] = true
+		}
+		bindings = append(bindings, binding)
+	}
+	sort.SliceStable(bindings, func(i, j int) bool {
+		if bindings[i].Namespace != bindings[j].Namespace {
+			return bindings[i].Namespace < bindings[j].Namespace
+		}
+		return bindings[i].Claim < bindings[j].Claim
+	})
+
+	for i := range pvs {
+		pv := &pvs[i]
+		if claimed[pv.Name] {
+			continue
+		}
+		binding := VolumeBinding{
+			Volume:       pv.Name,
+			VolumePhase:  pv.Status.Phase,
+			StorageClass: pv.Spec.StorageClassName,
+			Capacity:     quantityString(pv.Spec.Capacity, corev1.ResourceStorage),
+			Reason:       "volume isn't bound to any claim",
+		}
+		if ref := pv.Spec.ClaimRef; ref != nil {
+			binding.Reason = fmt.Sprintf("claim %s/%s not found in the bundle", ref.Namespace, ref.Name)
+		}
+		bindings = append(bindings, binding)
+	}
+	return bindings, nil
+}
+
+func claimBinding(pvc *corev1.PersistentVolumeClaim, volumes map[string]*corev1.PersistentVolume) VolumeBinding {
+	binding := VolumeBinding{
+		Namespace:  pvc.Namespace,
+		Claim:      pvc.Name,
+		ClaimPhase: pvc.Status.Phase,
+		Volume:     pvc.Spec.VolumeName,
+		Capacity:   quantityString(pvc.Spec.Resources.Requests, corev1.ResourceStorage),
+	}
+	if pvc.Spec.StorageClassName != nil {
+		binding.StorageClass = *pvc.Spec.StorageClassName
+	}
+
+	if pvc.Spec.VolumeName == "" {
+		binding.Reason = fmt.Sprintf("claim is %s without volume", valueOrDefault(string(pvc.Status.Phase), "Pending"))
+		return binding
+	}
+	pv, ok := volumes[pvc.Spec.VolumeName]
+	if !ok {
+		binding.Reason = "volume not found in the bundle"
+		return binding
+	}
+
+	binding.VolumePhase = pv.Status.Phase
+	binding.Capacity = quantityString(pv.Spec.Capacity, corev1.ResourceStorage)
+	if ref := pv.Spec.ClaimRef; ref == nil || ref.Namespace != pvc.Namespace || ref.Name != pvc.Name {
+		binding.Reason = "volume is bound to another claim"
+		return binding
+	}
+	binding.Bound = true
+	return binding
+}
+
+func quantityString(resources corev1.ResourceList, name corev1.ResourceName) string {
+	if quantity, ok := resources[name]; ok {
+		return quantity.String()
+	}
+	return ""
+}
@@ -0,0 +1,58 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const volumeBindingsPVs = `{"items": [
+  {
+    "metadata": {"name": "pv-data"},
+    "spec": {"capacity": {"storage": "10Gi"}, "storageClassName": "standard", "claimRef": {"namespace": "db", "name": "data-postgres-0"}},
+    "status": {"phase": "Bound"}
+  },
+  {
+    "metadata": {"name": "pv-released"},
+    "spec": {"capacity": {"storage": "1Gi"}, "storageClassName": "standard", "claimRef": {"namespace": "db", "name": "deleted"}},
+    "status": {"phase": "Released"}
+  }
+]}`
+
+const volumeBindingsPVCs = `{"items": [
+  {
+    "metadata": {"name": "data-postgres-0", "namespace": "db"},
+    "spec": {"volumeName": "pv-data", "storageClassName": "standard", "resources": {"requests": {"storage": "5Gi"}}},
+    "status": {"phase": "Bound"}
+  },
+  {
+    "metadata": {"name": "cache", "namespace": "db"},
+    "spec": {"storageClassName": "fast", "resources": {"requests": {"storage": "2Gi"}}},
+    "status": {"phase": "Pending"}
+  }
+]}`
+
+func TestListVolumeBindings(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/pvs.json":     volumeBindingsPVs,
+		"cluster-resources/pvcs/db.json": volumeBindingsPVCs,
+	})
+
+	bindings, err := ListVolumeBindings(b)
+	require.NoError(t, err)
+	assert.Equal(t, []VolumeBinding{
+		{
+			Namespace: "db", Claim: "cache", ClaimPhase: "Pending",
+			StorageClass: "fast", Capacity: "2Gi", Reason: "claim is Pending without volume",
+		},
+		{
+			Namespace: "db", Claim: "data-postgres-0", ClaimPhase: "Bound",
+			Volume: "pv-data", VolumePhase: "Bound", StorageClass: "standard", Capacity: "10Gi", Bound: true,
+		},
+		{
+			Volume: "pv-released", VolumePhase: "Released", StorageClass: "standard", Capacity: "1Gi",
+			Reason: "claim db/deleted not found in the bundle",
+		},
+	}, bindings)
+}
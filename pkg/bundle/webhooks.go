@@ -0,0 +1,82 @@
+package bundle
+
+import (
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+)
+
+// WebhookBackend is the backend of an admission webhook.
+type WebhookBackend struct {
+	// Kind is `MutatingWebhookConfiguration` or `ValidatingWebhookConfiguration`.
+	Kind          string `json:"kind"`
+	Configuration string `json:"configuration"`
+	Webhook       string `json:"webhook"`
+	FailurePolicy string `json:"failurePolicy,omitempty"`
+
+	// URL is set for webhooks called by URL.
+	URL string `json:"url,omitempty"`
+	// ServiceNamespace and ServiceName are set for webhooks backed by
+	// a service.
+	ServiceNamespace string `json:"serviceNamespace,omitempty"`
+	ServiceName      string `json:"serviceName,omitempty"`
+	// ServiceExists is set when the backing service is present in
+	// the bundle.
+	ServiceExists bool `json:"serviceExists"`
+}
+
+// ListWebhookBackends returns backends of mutating and validating admission
+// webhooks from the bundle. None of the backends run in the local cluster,
+// the webhooks with failure policy `Fail` reject requests of resources they
+// match, e.g. during the import. Configurations that weren't collected are
+// skipped.
+func ListWebhookBackends(b Bundle) ([]WebhookBackend, error) {
+	services, err := collectClusterResourcesNames(b, "services", namespacedNames{})
+	if err != nil {
+		return nil, err
+	}
+
+	backends := []WebhookBackend{}
+	mutating, err := loadOptionalCluster[admissionregistrationv1.MutatingWebhookConfiguration](
+		b, "mutatingwebhookconfigurations")
+	if err != nil {
+		return nil, err
+	}
+	for i := range mutating {
+		for _, w := range mutating[i].Webhooks {
+			backends = append(backends, newWebhookBackend(
+				"MutatingWebhookConfiguration", mutating[i].Name, w.Name, w.FailurePolicy, w.ClientConfig, services))
+		}
+	}
+
+	validating, err := loadOptionalCluster[admissionregistrationv1.ValidatingWebhookConfiguration](
+		b, "validatingwebhookconfigurations")
+	if err != nil {
+		return nil, err
+	}
+	for i := range validating {
+		for _, w := range validating[i].Webhooks {
+			backends = append(backends, newWebhookBackend(
+				"ValidatingWebhookConfiguration", validating[i].Name, w.Name, w.FailurePolicy, w.ClientConfig, services))
+		}
+	}
+	return backends, nil
+}
+
+func newWebhookBackend(
+	kind, configuration, webhook string,
+	failurePolicy *admissionregistrationv1.FailurePolicyType,
+	clientConfig admissionregistrationv1.WebhookClientConfig,
+	services namespacedNames,
+) WebhookBackend {
+	backend := WebhookBackend{Kind: kind, Configuration: configuration, Webhook: webhook}
+	if failurePolicy != nil {
+		backend.FailurePolicy = string(*failurePolicy)
+	}
+	if clientConfig.URL != nil {
+		backend.URL = *clientConfig.URL
+	}
+	if svc := clientConfig.Service; svc != nil {
+		backend.ServiceNamespace, backend.ServiceName = svc.Namespace, svc.Name
+		backend.ServiceExists = services[svc.Namespace][svc.Name]
+	}
+	return backend
+}
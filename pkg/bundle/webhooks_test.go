@@ -0,0 +1,51 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListWebhookBackends(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/services/cert-manager.json": `{"items": [{"metadata": {"name": "cert-manager-webhook", "namespace": "cert-manager"}}]}`,
+		"cluster-resources/mutatingwebhookconfigurations.json": `{"items": [{
+  "metadata": {"name": "cert-manager-webhook"},
+  "webhooks": [{
+    "name": "webhook.cert-manager.io", "failurePolicy": "Fail",
+    "clientConfig": {"service": {"namespace": "cert-manager", "name": "cert-manager-webhook", "path": "/mutate"}}
+  }]
+}]}`,
+		"cluster-resources/validatingwebhookconfigurations.json": `{"items": [{
+  "metadata": {"name": "policies"},
+  "webhooks": [
+    {"name": "validate.policy.example.com", "failurePolicy": "Ignore", "clientConfig": {"service": {"namespace": "policy", "name": "policy-webhook"}}},
+    {"name": "external.example.com", "clientConfig": {"url": "https://webhook.example.com/validate"}}
+  ]
+}]}`,
+	})
+
+	backends, err := ListWebhookBackends(b)
+	require.NoError(t, err)
+	assert.Equal(t, []WebhookBackend{
+		{
+			Kind: "MutatingWebhookConfiguration", Configuration: "cert-manager-webhook", Webhook: "webhook.cert-manager.io",
+			FailurePolicy: "Fail", ServiceNamespace: "cert-manager", ServiceName: "cert-manager-webhook", ServiceExists: true,
+		},
+		{
+			Kind: "ValidatingWebhookConfiguration", Configuration: "policies", Webhook: "validate.policy.example.com",
+			FailurePolicy: "Ignore", ServiceNamespace: "policy", ServiceName: "policy-webhook",
+		},
+		{
+			Kind: "ValidatingWebhookConfiguration", Configuration: "policies", Webhook: "external.example.com",
+			URL: "https://webhook.example.com/validate",
+		},
+	}, backends)
+}
+
+func TestListWebhookBackends_NotCollected(t *testing.T) {
+	backends, err := ListWebhookBackends(newTestBundle(t, map[string]string{}))
+	require.NoError(t, err)
+	assert.Empty(t, backends)
+}
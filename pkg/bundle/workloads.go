@@ -0,0 +1,76 @@
+package bundle
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// WorkloadSummary contains number of workloads and pods per phase across all
+// namespaces in the bundle.
+type WorkloadSummary struct {
+	Deployments  int `json:"deployments"`
+	StatefulSets int `json:"statefulSets"`
+	DaemonSets   int `json:"daemonSets"`
+	Jobs         int `json:"jobs"`
+	CronJobs     int `json:"cronJobs"`
+
+	// PodPhases contains number of pods per phase.
+	PodPhases map[corev1.PodPhase]int `json:"podPhases"`
+
+	// Notes describe data that weren't collected or couldn't be loaded, the
+	// related counts are incomplete.
+	Notes []string `json:"notes,omitempty"`
+}
+
+// SummarizeWorkloads counts workloads and pods from the bundle. Resources that
+// weren't collected or failed to load are reported in the notes and
+// the summary contains the rest of data.
+func SummarizeWorkloads(b Bundle) (*WorkloadSummary, error) {
+	summary := &WorkloadSummary{
+		PodPhases: map[corev1.PodPhase]int{},
+	}
+
+	counts := []struct {
+		dir   string
+		count *int
+	}{
+		{"deployments", &summary.Deployments},
+		{"statefulsets", &summary.StatefulSets},
+		{"daemonsets", &summary.DaemonSets},
+		{"jobs", &summary.Jobs},
+		{"cronjobs", &summary.CronJobs},
+	}
+	for _, c := range counts {
+		list, ok := summary.load(b, c.dir)
+		if ok {
+			*c.count = len(list.Items)
+		}
+	}
+
+	if pods, ok := summary.load(b, "pods"); ok {
+		for i := range pods.Items {
+			phase, _, _ := unstructured.NestedString(pods.Items[i].Object, "status", "phase")
+			if phase == "" {
+				phase = string(corev1.PodUnknown)
+			}
+			summary.PodPhases[corev1.PodPhase(phase)]++
+		}
+	}
+
+	return summary, nil
+}
+
+func (s *WorkloadSummary) load(b Bundle, dir string) (*unstructured.UnstructuredList, bool) {
+	list, err := loadNamespacedResources(b, dir)
+	switch {
+	case isNotCollected(err):
+		s.Notes = append(s.Notes, fmt.Sprintf("%s were not collected", dir))
+		return nil, false
+	case err != nil:
+		s.Notes = append(s.Notes, err.Error())
+		return nil, false
+	}
+	return list, true
+}
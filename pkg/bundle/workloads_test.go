@@ -0,0 +1,57 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSummarizeWorkloads(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/deployments/default.json": `{"items": [
+			{"metadata": {"name": "web", "namespace": "default"}},
+			{"metadata": {"name": "api", "namespace": "default"}}
+		]}`,
+		"cluster-resources/deployments/kube-system.json": `{"items": [
+			{"metadata": {"name": "coredns", "namespace": "kube-system"}}
+		]}`,
+		"cluster-resources/statefulsets/default.json":   `{"items": [{"metadata": {"name": "db", "namespace": "default"}}]}`,
+		"cluster-resources/daemonsets/kube-system.json": `{"items": [{"metadata": {"name": "proxy", "namespace": "kube-system"}}]}`,
+		"cluster-resources/jobs/default.json": `{"items": [
+			{"metadata": {"name": "migrate", "namespace": "default"}},
+			{"metadata": {"name": "backup-1", "namespace": "default"}}
+		]}`,
+		"cluster-resources/jobs/broken.json": `{"items": [`,
+		"cluster-resources/pods/default.json": `{"items": [
+			{"metadata": {"name": "web-1"}, "status": {"phase": "Running"}},
+			{"metadata": {"name": "web-2"}, "status": {"phase": "Running"}},
+			{"metadata": {"name": "migrate-1"}, "status": {"phase": "Succeeded"}},
+			{"metadata": {"name": "db-0"}, "status": {"phase": "Pending"}}
+		]}`,
+		"cluster-resources/pods/kube-system.json": `{"items": [
+			{"metadata": {"name": "proxy-1"}, "status": {"phase": "Failed"}},
+			{"metadata": {"name": "coredns-1"}}
+		]}`,
+	})
+
+	summary, err := SummarizeWorkloads(b)
+	require.NoError(t, err)
+	assert.Equal(t, 3, summary.Deployments)
+	assert.Equal(t, 1, summary.StatefulSets)
+	assert.Equal(t, 1, summary.DaemonSets)
+	assert.Equal(t, 0, summary.Jobs, "jobs failed to load")
+	assert.Equal(t, 0, summary.CronJobs)
+	assert.Equal(t, map[corev1.PodPhase]int{
+		corev1.PodRunning:   2,
+		corev1.PodSucceeded: 1,
+		corev1.PodPending:   1,
+		corev1.PodFailed:    1,
+		corev1.PodUnknown:   1,
+	}, summary.PodPhases)
+
+	require.Len(t, summary.Notes, 2)
+	assert.Contains(t, summary.Notes[0], "cluster-resources/jobs/broken.json")
+	assert.Equal(t, "cronjobs were not collected", summary.Notes[1])
+}
@@ -1,12 +1,11 @@
 package envtest
 
 import (
-	"encoding/json"
 	"path/filepath"
 	"strconv"
 
 	"github.com/Masterminds/semver/v3"
-	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/util/yaml"
 	versions "sigs.k8s.io/controller-runtime/tools/setup-envtest/versions"
 
 	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
@@ -53,13 +52,13 @@ func selectorFromSemver(sv *semver.Version) versions.Selector {
 // DetectK8sVersion attempts to load k8s server version from which was bundle
 // collected.
 func DetectK8sVersion(b bundle.Bundle) (versions.Selector, error) {
-	data, err := afero.ReadFile(b, filepath.Join(b.Layout().ClusterInfo(), "cluster_version.json"))
+	data, _, err := bundle.OpenResource(b, filepath.Join(b.Layout().ClusterInfo(), "cluster_version"))
 	if err != nil {
 		return nil, err
 	}
 
 	i := &clusterInfo{}
-	if err := json.Unmarshal(data, &i); err != nil {
+	if err := yaml.Unmarshal(data, &i); err != nil {
 		return nil, err
 	}
 
@@ -9,13 +9,11 @@ import (
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
-	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
-	"github.com/mhrabovcin/troubleshoot-live/pkg/cli"
 )
 
 func loadCRDs(b bundle.Bundle) (*unstructured.UnstructuredList, error) {
@@ -63,63 +61,38 @@ func loadCRDs(b bundle.Bundle) (*unstructured.UnstructuredList, error) {
 	return list, nil
 }
 
+// importCRDs imports CRDs and then custom resources of the established CRDs,
+// see ImportCRDsThenCRs.
 func importCRDs(
 	ctx context.Context,
 	cfg *importerConfig,
 ) error {
-	list, err := loadCRDs(cfg.bundle)
-	if err != nil {
-		cli.WarnOnErrorsFilePresence(
-			cfg.bundle, cfg.out,
-			filepath.Join(cfg.bundle.Layout().ClusterResources(), "custom-resource-definitions.json"),
-		)
-		return err
-	}
-
-	cfg.out.Infof("Processing %d records from CRD file", len(list.Items))
-
-	return list.EachListItem(func(in runtime.Object) error {
-		o, _ := meta.Accessor(in)
-		u, _ := in.(*unstructured.Unstructured)
-		gvr, includeStatus, err := detectGVR(cfg.discoveryClient, u)
-		cfg.out.V(5).Infof("CRD import: detected %s %q", o.GetName(), gvr)
-
-		// Assume that k8s api server doesn't know about the old apiextensions v1beta1
-		// version. Attempt to convert it to v1.
-		if err != nil {
-			cfg.out.V(1).Infof("Attempting to convert CRD %s from v1beta1 to v1", u.GetName())
-			v1beta1Extension := &apiextensionsv1beta1.CustomResourceDefinition{}
-			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, v1beta1Extension); err != nil {
-				return fmt.Errorf("failed to convert CRD unstructured to v1beta1 extension %q: %w", o.GetName(), err)
-			}
-			v1Extension, err := convertCRD(v1beta1Extension)
-			if err != nil {
-				return fmt.Errorf("failed to convert CRD from v1beta1 to v1: %w", err)
-			}
-
-			if crdHasNonStructuralSchema(v1Extension) {
-				cfg.out.Warnf("CRD in bundle %s has status NonStructuralSchema set to true", u.GetName())
-				v1Extension.Spec.PreserveUnknownFields = false
-			}
+	_, err := importCRDsThenCRs(ctx, cfg)
+	return err
+}
 
-			gvr = schema.GroupVersionResource{
-				Group:    "apiextensions.k8s.io",
-				Version:  "v1",
-				Resource: "customresourcedefinitions",
-			}
-			includeStatus = true
-			uMap, _ := runtime.DefaultUnstructuredConverter.ToUnstructured(v1Extension)
-			in = &unstructured.Unstructured{Object: uMap}
+// crdAsV1 decodes the CRD, CRDs stored as v1beta1 are converted to v1.
+func crdAsV1(u *unstructured.Unstructured) (*apiextensionsv1.CustomResourceDefinition, error) {
+	if u.GetAPIVersion() != apiextensionsv1beta1.SchemeGroupVersion.String() {
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, crd); err != nil {
+			return nil, fmt.Errorf("failed to decode CRD: %w", err)
 		}
+		return crd, nil
+	}
 
-		err = importObject(ctx, cfg.dynamicClient, gvr, in, includeStatus)
-		if err != nil {
-			cfg.out.Warnf(
-				"Failed to import CRD %q (%s) with error: %s", o.GetName(), gvr, err,
-			)
-		}
-		return nil
-	})
+	v1beta1Extension := &apiextensionsv1beta1.CustomResourceDefinition{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, v1beta1Extension); err != nil {
+		return nil, fmt.Errorf("failed to decode v1beta1 CRD: %w", err)
+	}
+	crd, err := convertCRD(v1beta1Extension)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert CRD from v1beta1 to v1: %w", err)
+	}
+	if crdHasNonStructuralSchema(crd) {
+		crd.Spec.PreserveUnknownFields = false
+	}
+	return crd, nil
 }
 
 // IsStatusConditionPresentAndEqual returns true when conditionType is present and equal to status.
@@ -0,0 +1,359 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mesosphere/dkp-cli-runtime/core/output"
+	"github.com/spf13/afero"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+	"github.com/mhrabovcin/troubleshoot-live/pkg/cli"
+	"github.com/mhrabovcin/troubleshoot-live/pkg/rewriter"
+)
+
+const (
+	// DefaultCRDImportConcurrency is the default number of files with custom
+	// resources imported concurrently.
+	DefaultCRDImportConcurrency = 4
+	// DefaultCRDEstablishTimeout is the default time to wait for API server
+	// to establish the imported CRDs.
+	DefaultCRDEstablishTimeout = 30 * time.Second
+)
+
+// CRDImportOption configures the ImportCRDsThenCRs.
+type CRDImportOption func(*crdImportOptions)
+
+type crdImportOptions struct {
+	concurrency      int
+	establishTimeout time.Duration
+	pollInterval     time.Duration
+	order            []string
+}
+
+// WithCRDImportConcurrency limits number of files with custom resources that
+// are imported concurrently. Values lower than 1 import files sequentially.
+func WithCRDImportConcurrency(concurrency int) CRDImportOption {
+	return func(o *crdImportOptions) {
+		o.concurrency = concurrency
+	}
+}
+
+// WithCRDEstablishTimeout limits time spent waiting for API server to
+// establish the imported CRDs.
+func WithCRDEstablishTimeout(timeout time.Duration) CRDImportOption {
+	return func(o *crdImportOptions) {
+		o.establishTimeout = timeout
+	}
+}
+
+// WithCRDEstablishPollInterval sets interval of checks whether the imported
+// CRDs are established.
+func WithCRDEstablishPollInterval(interval time.Duration) CRDImportOption {
+	return func(o *crdImportOptions) {
+		o.pollInterval = interval
+	}
+}
+
+// WithCRDImportOrder sets CRDs, e.g. `widgets.example.com`, that are imported
+// first together with their custom resources, in the given order. Remaining
+// CRDs are imported ordered by name.
+func WithCRDImportOrder(names ...string) CRDImportOption {
+	return func(o *crdImportOptions) {
+		o.order = names
+	}
+}
+
+// CRDImportResult describes the result of the ImportCRDsThenCRs.
+type CRDImportResult struct {
+	// Established contains names of CRDs established by API server.
+	Established []string
+	// Failed contains the reason per name of each CRD that failed to import
+	// or wasn't established.
+	Failed map[string]string
+	// Imported contains number of imported custom resources per CRD name.
+	Imported map[string]int
+}
+
+// ImportCRDsThenCRs imports CRDs from the bundle, waits until API server
+// establishes them and then imports custom resources of the established
+// CRDs from cluster resources. Custom resources of CRDs that were not
+// established are not imported. The result is returned also with the error
+// reporting CRDs that failed.
+func ImportCRDsThenCRs(
+	ctx context.Context,
+	b bundle.Bundle,
+	restCfg *rest.Config,
+	rr rewriter.ResourceRewriter,
+	out output.Output,
+	opts ...CRDImportOption,
+) (*CRDImportResult, error) {
+	dynamicClient, err := dynamic.NewForConfig(restCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &importerConfig{
+		dynamicClient: dynamicClient,
+		bundle:        b,
+		rewriter:      rr,
+		out:           out,
+	}
+	return importCRDsThenCRs(ctx, cfg, opts...)
+}
+
+func importCRDsThenCRs(ctx context.Context, cfg *importerConfig, opts ...CRDImportOption) (*CRDImportResult, error) {
+	o := &crdImportOptions{
+		concurrency:      DefaultCRDImportConcurrency,
+		establishTimeout: DefaultCRDEstablishTimeout,
+		pollInterval:     crdEstablishPollInterval,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	list, err := loadCRDs(cfg.bundle)
+	if err != nil {
+		cli.WarnOnErrorsFilePresence(
+			cfg.bundle, cfg.out,
+			filepath.Join(cfg.bundle.Layout().ClusterResources(), "custom-resource-definitions.json"),
+		)
+		return nil, err
+	}
+	cfg.out.Infof("Processing %d records from CRD file", len(list.Items))
+
+	result := &CRDImportResult{Failed: map[string]string{}, Imported: map[string]int{}}
+	crds := registerCRDs(ctx, cfg, list, result)
+
+	// Custom resources can be created only after API server establishes
+	// the CRDs and starts serving them.
+	names := make([]string, 0, len(crds))
+	for _, crd := range crds {
+		names = append(names, crd.Name)
+	}
+	cfg.out.V(1).Infof("Waiting for %d CRDs to be established by API server", len(names))
+	failed := waitForCRDsEstablished(ctx, cfg.dynamicClient, names, o.pollInterval, o.establishTimeout)
+
+	var established []*apiextensionsv1.CustomResourceDefinition
+	for _, crd := range crds {
+		if reason, ok := failed[crd.Name]; ok {
+			result.Failed[crd.Name] = reason
+			continue
+		}
+		established = append(established, crd)
+	}
+	orderCRDs(established, o.order)
+
+	for _, crd := range established {
+		result.Established = append(result.Established, crd.Name)
+	}
+	if err := importCustomResources(ctx, cfg, established, o.concurrency, result); err != nil {
+		return result, err
+	}
+
+	names = make([]string, 0, len(result.Failed))
+	for name, reason := range result.Failed {
+		cfg.out.Warnf("CRD %q: %s", name, reason)
+		names = append(names, name)
+	}
+	if len(names) > 0 {
+		sort.Strings(names)
+		return result, fmt.Errorf("%d CRDs were not established: %s", len(names), strings.Join(names, ", "))
+	}
+	return result, nil
+}
+
+// registerCRDs creates CRDs in API server. CRDs stored as v1beta1 are
+// converted to v1. The collected status is not imported, API server sets
+// the status once it establishes the CRD.
+func registerCRDs(
+	ctx context.Context,
+	cfg *importerConfig,
+	list *unstructured.UnstructuredList,
+	result *CRDImportResult,
+) []*apiextensionsv1.CustomResourceDefinition {
+	crdGVR := apiextensionsv1.SchemeGroupVersion.WithResource("customresourcedefinitions")
+
+	var crds []*apiextensionsv1.CustomResourceDefinition
+	for i := range list.Items {
+		name := list.Items[i].GetName()
+		crd, err := crdAsV1(&list.Items[i])
+		if err != nil {
+			result.Failed[name] = err.Error()
+			continue
+		}
+
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(crd)
+		if err != nil {
+			result.Failed[name] = err.Error()
+			continue
+		}
+		u := &unstructured.Unstructured{Object: obj}
+		u.SetGroupVersionKind(apiextensionsv1.SchemeGroupVersion.WithKind("CustomResourceDefinition"))
+		unstructured.RemoveNestedField(u.Object, "status")
+
+		if err := importObject(ctx, cfg, crdGVR, u, false); err != nil {
+			result.Failed[name] = err.Error()
+			continue
+		}
+		crds = append(crds, crd)
+	}
+	return crds
+}
+
+func orderCRDs(crds []*apiextensionsv1.CustomResourceDefinition, order []string) {
+	rank := map[string]int{}
+	for i, name := range order {
+		rank[name] = i - len(order)
+	}
+	sort.SliceStable(crds, func(i, j int) bool {
+		x, y := rank[crds[i].Name], rank[crds[j].Name]
+		if x != y {
+			return x < y
+		}
+		return crds[i].Name < crds[j].Name
+	})
+}
+
+// customResourcesFile is a file from cluster resources with custom resources
+// of a CRD.
+type customResourcesFile struct {
+	path  string
+	crd   *apiextensionsv1.CustomResourceDefinition
+	items []unstructured.Unstructured
+}
+
+// importCustomResources imports custom resources of the CRDs stored in cluster
+// resources. Files are imported concurrently, ordered by the CRDs.
+func importCustomResources(
+	ctx context.Context,
+	cfg *importerConfig,
+	crds []*apiextensionsv1.CustomResourceDefinition,
+	concurrency int,
+	result *CRDImportResult,
+) error {
+	files, err := findCustomResourcesFiles(cfg.bundle, crds)
+	if err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+	tasks := make(chan *customResourcesFile)
+	var wg sync.WaitGroup
+	for i := 0; i < max(concurrency, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range tasks {
+				imported, failures := importCustomResourcesFile(ctx, cfg, file)
+				mu.Lock()
+				result.Imported[file.crd.Name] += imported
+				for _, failure := range failures {
+					cfg.out.Warnf("Failed to import custom resource from %q: %s", file.path, failure)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	if cfg.customResourcesFiles == nil {
+		cfg.customResourcesFiles = map[string]bool{}
+	}
+	for _, file := range files {
+		cfg.customResourcesFiles[file.path] = true
+		tasks <- file
+	}
+	close(tasks)
+	wg.Wait()
+	return nil
+}
+
+func importCustomResourcesFile(ctx context.Context, cfg *importerConfig, file *customResourcesFile) (int, []string) {
+	imported := 0
+	var failures []string
+	for i := range file.items {
+		u := &file.items[i]
+		gv, err := schema.ParseGroupVersion(u.GetAPIVersion())
+		if err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+
+		gvr := gv.WithResource(file.crd.Spec.Names.Plural)
+		if err := importObject(ctx, cfg, gvr, u, hasStatusSubresource(file.crd, gv.Version)); err != nil {
+			failures = append(failures, fmt.Sprintf("%s/%s: %s", u.GetNamespace(), u.GetName(), err))
+			continue
+		}
+		imported++
+	}
+	return imported, failures
+}
+
+func hasStatusSubresource(crd *apiextensionsv1.CustomResourceDefinition, version string) bool {
+	for _, v := range crd.Spec.Versions {
+		if v.Name == version {
+			return v.Subresources != nil && v.Subresources.Status != nil
+		}
+	}
+	return false
+}
+
+// findCustomResourcesFiles returns files from the custom resources directory
+// of cluster resources with custom resources of the CRDs, ordered by the CRDs
+// and by path.
+func findCustomResourcesFiles(
+	b bundle.Bundle,
+	crds []*apiextensionsv1.CustomResourceDefinition,
+) ([]*customResourcesFile, error) {
+	byGroupKind := map[schema.GroupKind]int{}
+	for i, crd := range crds {
+		byGroupKind[schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}] = i
+	}
+
+	var files []*customResourcesFile
+	root := filepath.Join(b.Layout().ClusterResources(), bundle.CustomResourcesDir)
+	err := afero.Walk(b, root, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() && isSkippedDir(b, info.Name()) {
+			return fs.SkipDir
+		}
+		path, ok := chunkedFilePath(path)
+		if info.IsDir() || !ok || isSkippedResource(b, filepath.Base(path)) || isErrorsFile(path) {
+			return nil
+		}
+
+		list, err := bundle.LoadResourcesFromFile(b, path)
+		if err != nil || len(list.Items) == 0 {
+			return nil
+		}
+		i, ok := byGroupKind[list.Items[0].GroupVersionKind().GroupKind()]
+		if !ok {
+			return nil
+		}
+		files = append(files, &customResourcesFile{path: path, crd: crds[i], items: list.Items})
+		return nil
+	})
+
+	rank := map[*apiextensionsv1.CustomResourceDefinition]int{}
+	for i, crd := range crds {
+		rank[crd] = i
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return rank[files[i].crd] < rank[files[j].crd]
+	})
+	return files, err
+}
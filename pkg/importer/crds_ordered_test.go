@@ -0,0 +1,110 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mesosphere/dkp-cli-runtime/core/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/rewriter"
+)
+
+func TestImportCRDsThenCRs(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/custom-resource-definitions.json": `{"items": [
+			{"apiVersion": "apiextensions.k8s.io/v1", "kind": "CustomResourceDefinition",
+			 "metadata": {"name": "widgets.example.com"},
+			 "spec": {"group": "example.com", "scope": "Namespaced",
+			  "names": {"plural": "widgets", "kind": "Widget", "listKind": "WidgetList"},
+			  "versions": [{"name": "v1", "served": true, "storage": true, "subresources": {"status": {}}}]}},
+			{"apiVersion": "apiextensions.k8s.io/v1", "kind": "CustomResourceDefinition",
+			 "metadata": {"name": "gadgets.example.com"},
+			 "spec": {"group": "example.com", "scope": "Namespaced",
+			  "names": {"plural": "gadgets", "kind": "Gadget", "listKind": "GadgetList"},
+			  "versions": [{"name": "v1", "served": true, "storage": true}]}},
+			{"apiVersion": "apiextensions.k8s.io/v1", "kind": "CustomResourceDefinition",
+			 "metadata": {"name": "gizmos.example.com"},
+			 "spec": {"group": "example.com", "scope": "Cluster",
+			  "names": {"plural": "gizmos", "kind": "Gizmo", "listKind": "GizmoList"},
+			  "versions": [{"name": "v1", "served": true, "storage": true}]},
+			 "status": {"conditions": [{"type": "Established", "status": "True"}]}}
+		]}`,
+		"cluster-resources/custom-resources/widgets.example.com/default.json": `{"items": [
+			{"apiVersion": "example.com/v1", "kind": "Widget", "metadata": {"name": "a", "namespace": "default"}},
+			{"apiVersion": "example.com/v1", "kind": "Widget", "metadata": {"name": "b", "namespace": "default"}}
+		]}`,
+		"cluster-resources/custom-resources/widgets.example.com/other.json": `{"items": [
+			{"apiVersion": "example.com/v1", "kind": "Widget", "metadata": {"name": "c", "namespace": "other"}}
+		]}`,
+		"cluster-resources/custom-resources/gadgets.example.com/default.json": `{"items": [
+			{"apiVersion": "example.com/v1", "kind": "Gadget", "metadata": {"name": "a", "namespace": "default"}}
+		]}`,
+		"cluster-resources/pods/default.json": `{"items": [
+			{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "a", "namespace": "default"}}
+		]}`,
+	})
+
+	// The collected status of gizmos is not imported, so API server never
+	// establishes the CRD.
+	client, counts := crdsClient(map[string][]any{
+		"widgets.example.com": {map[string]any{"type": "Established", "status": "True"}},
+		"gadgets.example.com": {map[string]any{
+			"type": "NamesAccepted", "status": "False", "message": `"gadgets" is already in use`,
+		}},
+	}, 3)
+	cfg := &importerConfig{
+		dynamicClient: client,
+		bundle:        b,
+		rewriter:      rewriter.Default(),
+		out:           output.NewNonInteractiveShell(&bytes.Buffer{}, &bytes.Buffer{}, 0),
+	}
+
+	result, err := importCRDsThenCRs(context.Background(), cfg,
+		WithCRDImportConcurrency(2),
+		WithCRDEstablishTimeout(50*time.Millisecond),
+		WithCRDEstablishPollInterval(time.Millisecond),
+	)
+	assert.EqualError(t, err, "2 CRDs were not established: gadgets.example.com, gizmos.example.com")
+	require.NotNil(t, result)
+
+	assert.Equal(t, []string{"widgets.example.com"}, result.Established)
+	assert.Equal(t, map[string]string{
+		"gadgets.example.com": `names were not accepted: "gadgets" is already in use`,
+		"gizmos.example.com":  "not established within 50ms",
+	}, result.Failed)
+	assert.Equal(t, map[string]int{"widgets.example.com": 3}, result.Imported)
+	assert.GreaterOrEqual(t, counts["widgets.example.com"], 3, "custom resources are imported once the CRD is established")
+
+	widgets, err := client.Resource(schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}).
+		Namespace("other").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, widgets.Items, 1)
+
+	gadgets, err := client.Resource(schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "gadgets"}).
+		List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, gadgets.Items, "custom resources of CRDs that were not established are not imported")
+}
+
+func TestOrderCRDs(t *testing.T) {
+	crds := []*apiextensionsv1.CustomResourceDefinition{
+		{ObjectMeta: metav1.ObjectMeta{Name: "c"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "a"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "d"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "b"}},
+	}
+	orderCRDs(crds, []string{"d", "b"})
+
+	names := []string{}
+	for _, crd := range crds {
+		names = append(names, crd.Name)
+	}
+	assert.Equal(t, []string{"d", "b", "a", "c"}, names)
+}
@@ -0,0 +1,144 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+)
+
+// crdEstablishPollInterval is the interval of checks whether the imported
+// CRDs are established.
+const crdEstablishPollInterval = 500 * time.Millisecond
+
+// servedCRD is the API resource that is available in discovery once the CRD
+// is established by API server.
+type servedCRD struct {
+	name         string
+	groupVersion string
+	resource     string
+}
+
+// crdServedResource returns the API resource of the first served version of
+// the CRD. Both `apiextensions.k8s.io/v1` and `v1beta1` versions are supported.
+func crdServedResource(crd *unstructured.Unstructured) (servedCRD, bool) {
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	plural, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "plural")
+	if group == "" || plural == "" {
+		return servedCRD{}, false
+	}
+
+	version, _, _ := unstructured.NestedString(crd.Object, "spec", "version")
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, v := range versions {
+		v, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		if served, _, _ := unstructured.NestedBool(v, "served"); served {
+			version, _, _ = unstructured.NestedString(v, "name")
+			break
+		}
+	}
+	if version == "" {
+		return servedCRD{}, false
+	}
+
+	return servedCRD{
+		name:         crd.GetName(),
+		groupVersion: schema.GroupVersion{Group: group, Version: version}.String(),
+		resource:     plural,
+	}, true
+}
+
+func isResourceServed(cl discovery.DiscoveryInterface, crd servedCRD) bool {
+	resources, err := cl.ServerResourcesForGroupVersion(crd.groupVersion)
+	if err != nil {
+		return false
+	}
+	for _, r := range resources.APIResources {
+		if r.Name == crd.resource {
+			return true
+		}
+	}
+	return false
+}
+
+// waitForCRDsEstablished polls API server until the CRDs with given names are
+// established, so that their custom resources can be imported. The reason is
+// returned per name of each CRD with names that were not accepted or that is
+// not established before the timeout.
+func waitForCRDsEstablished(
+	ctx context.Context,
+	cl dynamic.Interface,
+	names []string,
+	interval, timeout time.Duration,
+) map[string]string {
+	crdClient := cl.Resource(apiextensionsv1.SchemeGroupVersion.WithResource("customresourcedefinitions"))
+
+	failed := map[string]string{}
+	pending := names
+	_ = wait.PollUntilContextTimeout(ctx, interval, timeout, true, func(ctx context.Context) (bool, error) {
+		var notEstablished []string
+		for _, name := range pending {
+			u, err := crdClient.Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				notEstablished = append(notEstablished, name)
+				continue
+			}
+
+			live := &apiextensionsv1.CustomResourceDefinition{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, live); err != nil {
+				failed[name] = fmt.Sprintf("failed to decode CRD from API server: %s", err)
+				continue
+			}
+
+			switch establishedState(live) {
+			case apiextensionsv1.ConditionFalse:
+				failed[name] = fmt.Sprintf("names were not accepted: %s", namesAcceptedMessage(live))
+			case apiextensionsv1.ConditionUnknown:
+				notEstablished = append(notEstablished, name)
+			}
+		}
+		pending = notEstablished
+		return len(pending) == 0, nil
+	})
+
+	for _, name := range pending {
+		failed[name] = fmt.Sprintf("not established within %s", timeout)
+	}
+	return failed
+}
+
+// establishedState returns true when the CRD is established, false when its
+// names were rejected and the CRD won't be established and unknown otherwise.
+func establishedState(crd *apiextensionsv1.CustomResourceDefinition) apiextensionsv1.ConditionStatus {
+	for _, c := range crd.Status.Conditions {
+		if c.Type == apiextensionsv1.Established && c.Status == apiextensionsv1.ConditionTrue {
+			return apiextensionsv1.ConditionTrue
+		}
+	}
+	for _, c := range crd.Status.Conditions {
+		if c.Type == apiextensionsv1.NamesAccepted && c.Status == apiextensionsv1.ConditionFalse {
+			return apiextensionsv1.ConditionFalse
+		}
+	}
+	return apiextensionsv1.ConditionUnknown
+}
+
+func namesAcceptedMessage(crd *apiextensionsv1.CustomResourceDefinition) string {
+	for _, c := range crd.Status.Conditions {
+		if c.Type == apiextensionsv1.NamesAccepted {
+			return c.Message
+		}
+	}
+	return ""
+}
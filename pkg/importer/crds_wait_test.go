@@ -0,0 +1,112 @@
+package importer
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestCRDServedResource(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/custom-resource-definitions.json": `{"items": [
+			{"apiVersion": "apiextensions.k8s.io/v1", "kind": "CustomResourceDefinition",
+			 "metadata": {"name": "widgets.example.com"},
+			 "spec": {"group": "example.com", "names": {"plural": "widgets", "kind": "Widget"}, "versions": [
+				{"name": "v1alpha1", "served": false},
+				{"name": "v1", "served": true, "storage": true}
+			 ]}},
+			{"metadata": {"name": "gadgets.example.com"},
+			 "spec": {"group": "example.com", "version": "v1beta1", "preserveUnknownFields": true,
+			  "names": {"plural": "gadgets", "kind": "Gadget"}}}
+		]}`,
+	})
+
+	list, err := loadCRDs(b)
+	require.NoError(t, err)
+
+	crd, ok := crdServedResource(&list.Items[0])
+	require.True(t, ok)
+	assert.Equal(t, servedCRD{name: "widgets.example.com", groupVersion: "example.com/v1", resource: "widgets"}, crd)
+
+	crd, ok = crdServedResource(&list.Items[1])
+	require.True(t, ok)
+	assert.Equal(t, "example.com/v1beta1", crd.groupVersion)
+
+	_, ok = crdServedResource(&unstructured.Unstructured{Object: map[string]any{}})
+	assert.False(t, ok)
+}
+
+// crdsClient returns fake client with reactor that sets the conditions of
+// created CRDs once the CRD was polled given number of times, as API server
+// does when it establishes the CRD. The number of polls per CRD is counted.
+func crdsClient(conditions map[string][]any, polls int) (*dynamicfake.FakeDynamicClient, map[string]int) {
+	crdsGVR := apiextensionsv1.SchemeGroupVersion.WithResource("customresourcedefinitions")
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		crdsGVR: "CustomResourceDefinitionList",
+		{Group: "example.com", Version: "v1", Resource: "widgets"}: "WidgetList",
+		{Group: "example.com", Version: "v1", Resource: "gadgets"}: "GadgetList",
+	})
+
+	var mu sync.Mutex
+	counts := map[string]int{}
+	client.PrependReactor("get", "customresourcedefinitions", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		name := action.(clienttesting.GetAction).GetName()
+		obj, err := client.Tracker().Get(crdsGVR, "", name)
+		if err != nil {
+			return true, nil, err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		counts[name]++
+		u := obj.(*unstructured.Unstructured).DeepCopy()
+		if c, ok := conditions[name]; ok && counts[name] >= polls {
+			_ = unstructured.SetNestedSlice(u.Object, c, "status", "conditions")
+		}
+		return true, u, nil
+	})
+	return client, counts
+}
+
+func TestWaitForCRDsEstablished(t *testing.T) {
+	client, counts := crdsClient(map[string][]any{
+		"widgets.example.com": {map[string]any{"type": "Established", "status": "True"}},
+		"gadgets.example.com": {map[string]any{"type": "NamesAccepted", "status": "False", "message": "already in use"}},
+	}, 3)
+	crdsGVR := apiextensionsv1.SchemeGroupVersion.WithResource("customresourcedefinitions")
+	for _, name := range []string{"widgets.example.com", "gadgets.example.com", "gizmos.example.com"} {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(apiextensionsv1.SchemeGroupVersion.WithKind("CustomResourceDefinition"))
+		u.SetName(name)
+		_, err := client.Resource(crdsGVR).Create(context.Background(), u, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	failed := waitForCRDsEstablished(
+		context.Background(), client, []string{"widgets.example.com"}, time.Millisecond, time.Second)
+	assert.Empty(t, failed)
+	assert.Equal(t, 3, counts["widgets.example.com"], "polled until established")
+
+	failed = waitForCRDsEstablished(
+		context.Background(), client,
+		[]string{"gadgets.example.com", "gizmos.example.com", "missing.example.com"},
+		time.Millisecond, 20*time.Millisecond,
+	)
+	assert.Equal(t, map[string]string{
+		"gadgets.example.com": "names were not accepted: already in use",
+		"gizmos.example.com":  "not established within 20ms",
+		"missing.example.com": "not established within 20ms",
+	}, failed)
+	assert.Greater(t, counts["gizmos.example.com"], 1, "polled until the timeout")
+}
@@ -8,6 +8,8 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
 )
 
 func detectGVR(cl discovery.DiscoveryInterface, u *unstructured.Unstructured) (schema.GroupVersionResource, bool, error) {
@@ -41,36 +43,22 @@ func detectGVR(cl discovery.DiscoveryInterface, u *unstructured.Unstructured) (s
 	return schema.GroupVersionResource{}, false, fmt.Errorf("not found")
 }
 
-func gvkFromFile(path string) (schema.GroupVersionKind, error) {
-	mappings := map[string]schema.GroupVersionKind{
-		"cronjobs/*.json":      {Version: "v1", Kind: "CronJob", Group: "batch"},
-		"deployments/*.json":   {Version: "v1", Kind: "Deployment", Group: "apps"},
-		"events/*.json":        {Version: "v1", Kind: "Event"},
-		"ingress/*.json":       {Version: "v1", Kind: "Ingress", Group: "networking.k8s.io"},
-		"jobs/*.json":          {Version: "v1", Kind: "Job", Group: "batch"},
-		"limitranges/*.json":   {Version: "v1", Kind: "LimitRange"},
-		"nodes.json":           {Version: "v1", Kind: "Node"},
-		"pods/*.json":          {Version: "v1", Kind: "Pod"},
-		"pvcs/*.json":          {Version: "v1", Kind: "PersistentVolumeClaim"},
-		"pvs.json":             {Version: "v1", Kind: "PersistentVolume"},
-		"replicasets/*.json":   {Version: "v1", Kind: "ReplicaSet", Group: "apps"},
-		"services/*.json":      {Version: "v1", Kind: "Service"},
-		"statefulsets/*.json":  {Version: "v1", Kind: "StatefulSet", Group: "apps"},
-		"storage-classes.json": {Version: "v1", Kind: "StorageClass", Group: "storage.k8s.io"},
+// populateGVKFromPath sets GVK for items loaded from given path. Kind was not stored
+// in older troubleshoot versions for non-CRDs, try to figure out the kind by the
+// filename.
+func populateGVKFromPath(b bundle.Bundle, path string, list *unstructured.UnstructuredList) error {
+	if len(list.Items) == 0 || list.Items[0].GetKind() != "" {
+		return nil
 	}
 
-	for pattern, gvk := range mappings {
-		ok, err := filepath.Match(pattern, path)
-		if err != nil {
-			return schema.GroupVersionKind{}, err
-		}
-
-		if ok {
-			return gvk, nil
-		}
+	relPath, err := filepath.Rel(b.Layout().ClusterResources(), path)
+	if err != nil {
+		return fmt.Errorf("failed to detect kind for path %q: %w", path, err)
 	}
-
-	return schema.GroupVersionKind{}, nil
+	if gvk := bundle.GVKFromPath(b.Layout(), relPath); !gvk.Empty() {
+		populateGVK(list, gvk)
+	}
+	return nil
 }
 
 func populateGVK(list *unstructured.UnstructuredList, gvk schema.GroupVersionKind) {
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io/fs"
 	"path/filepath"
-	"strings"
 
 	"github.com/mesosphere/dkp-cli-runtime/core/output"
 	"github.com/spf13/afero"
@@ -20,10 +19,10 @@ import (
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/util/retry"
-	"k8s.io/utils/strings/slices"
 
 	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
 	"github.com/mhrabovcin/troubleshoot-live/pkg/cli"
+	"github.com/mhrabovcin/troubleshoot-live/pkg/rewriter"
 	"github.com/mhrabovcin/troubleshoot-live/pkg/utils"
 )
 
@@ -32,8 +31,15 @@ func AnnotationForOriginalValue(name string) string {
 	return fmt.Sprintf("troubleshoot-live/%s", name)
 }
 
-// ImportBundle creates resources in provided API server.
-func ImportBundle(ctx context.Context, b bundle.Bundle, restCfg *rest.Config, out output.Output) error {
+// ImportBundle creates resources in provided API server. Each resource is
+// modified by the provided rewriter before it is created.
+func ImportBundle(
+	ctx context.Context,
+	b bundle.Bundle,
+	restCfg *rest.Config,
+	rr rewriter.ResourceRewriter,
+	out output.Output,
+) error {
 	dynamicClient, err := dynamic.NewForConfig(restCfg)
 	if err != nil {
 		return err
@@ -48,12 +54,20 @@ func ImportBundle(ctx context.Context, b bundle.Bundle, restCfg *rest.Config, ou
 		dynamicClient:   dynamicClient,
 		discoveryClient: discoveryClient,
 		bundle:          b,
+		rewriter:        rr,
 		out:             out,
 	}
+	return importAll(ctx, cfg)
+}
 
+// importAll runs all importers in order. Namespaces are imported first, so
+// that namespaced resources, including custom resources imported with their
+// CRDs, are created in existing namespaces.
+func importAll(ctx context.Context, cfg *importerConfig) error {
 	importers := []importerFn{
-		importCRDs,
 		importNamespaces,
+		importCRDs,
+		importOpenShiftCRDs,
 		importClusterResources,
 		importCMs,
 		importSecrets,
@@ -67,8 +81,8 @@ func ImportBundle(ctx context.Context, b bundle.Bundle, restCfg *rest.Config, ou
 	}
 
 	if len(importErrors) > 0 {
-		out.Warn("\n!!! There were failures when importing the bundle data.")
-		out.Warn("!!! The data in the API server are most likely incomplete\n")
+		cfg.out.Warn("\n!!! There were failures when importing the bundle data.")
+		cfg.out.Warn("!!! The data in the API server are most likely incomplete\n")
 		return errors.Join(importErrors...)
 	}
 
@@ -79,7 +93,13 @@ type importerConfig struct {
 	dynamicClient   dynamic.Interface
 	discoveryClient discovery.DiscoveryInterface
 	bundle          bundle.Bundle
+	rewriter        rewriter.ResourceRewriter
 	out             output.Output
+
+	// customResourcesFiles are paths of files with custom resources imported
+	// together with their CRDs, which are not imported again with other
+	// cluster resources.
+	customResourcesFiles map[string]bool
 }
 
 type importerFn func(context.Context, *importerConfig) error
@@ -108,7 +128,7 @@ func importNamespaces(
 	return list.EachListItem(func(o runtime.Object) error {
 		u, _ := meta.Accessor(o)
 		namespaces = append(namespaces, u.GetName())
-		return importObject(ctx, cfg.dynamicClient, gvr, o, includeStatus)
+		return importObject(ctx, cfg, gvr, o, includeStatus)
 	})
 }
 
@@ -116,23 +136,6 @@ func importClusterResources(
 	ctx context.Context,
 	cfg *importerConfig,
 ) error {
-	skipResources := []string{
-		// crds are imported during a separate step
-		"custom-resource-definitions.json",
-		"pod-disruption-budgets-info.json",
-		// api-resources from the discovery client
-		"resources.json",
-		// api-groups from the discovery client
-		"groups.json",
-		// namespaces are imported as first resource in a separate step
-		"namespaces.json",
-	}
-
-	skipDirs := []string{
-		"auth-cani-list",
-		"pod-disruption-budgets",
-	}
-
 	return afero.Walk(cfg.bundle, cfg.bundle.Layout().ClusterResources(), func(path string, info fs.FileInfo, err error) error {
 		if err != nil {
 			cfg.out.Warnf("Failed to read file %q from bundle: %s", path, err)
@@ -140,7 +143,7 @@ func importClusterResources(
 		}
 
 		// Do not process any resources from the directory
-		if info.IsDir() && slices.Contains(skipDirs, filepath.Base(info.Name())) {
+		if info.IsDir() && isSkippedDir(cfg.bundle, info.Name()) {
 			return fs.SkipDir
 		}
 
@@ -148,12 +151,13 @@ func importClusterResources(
 			return nil
 		}
 
-		if slices.Contains(skipResources, filepath.Base(info.Name())) {
+		// Chunked files are loaded at once when the first chunk is visited.
+		path, ok := chunkedFilePath(path)
+		if !ok {
 			return nil
 		}
 
-		// skip failed resources
-		if strings.HasSuffix(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)), "-errors") {
+		if isSkippedResource(cfg.bundle, filepath.Base(path)) || isErrorsFile(path) || cfg.customResourcesFiles[path] {
 			return nil
 		}
 
@@ -168,16 +172,8 @@ func importClusterResources(
 			return nil
 		}
 
-		// Kind was not stored in older troubleshoot versions for non-CRDs, try to
-		// figure out the kind by the filename.
-		if list.Items[0].GetKind() == "" {
-			relPath, err := filepath.Rel(cfg.bundle.Layout().ClusterResources(), path)
-			if err != nil {
-				return fmt.Errorf("failed to detect kind for path %q: %w", path, err)
-			}
-			if gvk, err := gvkFromFile(relPath); err == nil {
-				populateGVK(list, gvk)
-			}
+		if err := populateGVKFromPath(cfg.bundle, path, list); err != nil {
+			return err
 		}
 
 		cfg.out.V(1).Infof("Importing objects from: %s ...", path)
@@ -189,7 +185,7 @@ func importClusterResources(
 		}
 
 		_ = list.EachListItem(func(o runtime.Object) error {
-			err := importObject(ctx, cfg.dynamicClient, gvr, o, includeStatus)
+			err := importObject(ctx, cfg, gvr, o, includeStatus)
 			if err != nil {
 				u, _ := meta.Accessor(o)
 				cfg.out.Warnf(
@@ -231,7 +227,7 @@ func importCMOrSecrets(
 			return nil
 		}
 
-		if err := importObject(ctx, cfg.dynamicClient, gvr, obj, true); err != nil {
+		if err := importObject(ctx, cfg, gvr, obj, true); err != nil {
 			return err
 		}
 
@@ -265,15 +261,17 @@ func importSecrets(
 
 func importObject(
 	ctx context.Context,
-	cl dynamic.Interface,
+	cfg *importerConfig,
 	gvr schema.GroupVersionResource,
 	o runtime.Object,
 	includeStatus bool,
 ) error {
-	if err := prepareForImport(o); err != nil {
+	if err := prepareForImport(o, cfg.rewriter); err != nil {
 		return err
 	}
 
+	cl := cfg.dynamicClient
+
 	u := o.(*unstructured.Unstructured)
 
 	if u.GetKind() == "Job" {
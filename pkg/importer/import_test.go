@@ -0,0 +1,85 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/mesosphere/dkp-cli-runtime/core/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/rewriter"
+)
+
+func TestImportAll_CustomResourcesInNamespaces(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/namespaces.json": `{"items": [{"metadata": {"name": "default"}}]}`,
+		"cluster-resources/custom-resource-definitions.json": `{"items": [
+			{"apiVersion": "apiextensions.k8s.io/v1", "kind": "CustomResourceDefinition",
+			 "metadata": {"name": "widgets.example.com"},
+			 "spec": {"group": "example.com", "scope": "Namespaced",
+			  "names": {"plural": "widgets", "kind": "Widget", "listKind": "WidgetList"},
+			  "versions": [{"name": "v1", "served": true, "storage": true}]}}
+		]}`,
+		"cluster-resources/custom-resources/widgets.example.com/default.json": `{"items": [
+			{"apiVersion": "example.com/v1", "kind": "Widget", "metadata": {"name": "a", "namespace": "default"}},
+			{"apiVersion": "example.com/v1", "kind": "Widget", "metadata": {"name": "b", "namespace": "default"}}
+		]}`,
+	})
+
+	client, _ := crdsClient(map[string][]any{
+		"widgets.example.com": {map[string]any{"type": "Established", "status": "True"}},
+	}, 1)
+
+	// API server rejects resources in namespaces that don't exist.
+	var mu sync.Mutex
+	creates := map[string]int{}
+	namespacesGVR := schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	client.PrependReactor("create", "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		mu.Lock()
+		creates[action.GetResource().Resource+"/"+action.GetNamespace()]++
+		mu.Unlock()
+		if ns := action.GetNamespace(); ns != "" {
+			if _, err := client.Tracker().Get(namespacesGVR, "", ns); err != nil {
+				return true, nil, err
+			}
+		}
+		return false, nil, nil
+	})
+
+	discovery := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}
+	discovery.Resources = []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{{Name: "namespaces", Kind: "Namespace"}}},
+		{GroupVersion: "example.com/v1", APIResources: []metav1.APIResource{{Name: "widgets", Kind: "Widget", Namespaced: true}}},
+	}
+
+	cfg := &importerConfig{
+		dynamicClient:   client,
+		discoveryClient: discovery,
+		bundle:          b,
+		rewriter:        rewriter.Default(),
+		out:             output.NewNonInteractiveShell(&bytes.Buffer{}, &bytes.Buffer{}, 0),
+	}
+	require.NoError(t, importAll(context.Background(), cfg))
+
+	widgets, err := client.Resource(schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}).
+		Namespace("default").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, widgets.Items, 2)
+	assert.Equal(t, 2, creates["widgets/default"], "each custom resource is created once")
+
+	gets := 0
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "get" && action.GetResource().Resource == "widgets" {
+			gets++
+		}
+	}
+	assert.Equal(t, 2, gets, "custom resources are not imported again with other cluster resources")
+}
@@ -0,0 +1,107 @@
+package importer
+
+import (
+	"context"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+// importOpenShiftCRDs creates CRDs for OpenShift resources that are not served
+// by the API server. OpenShift serves the resources by its own API server,
+// so bundles don't contain their CRDs. The CRDs preserve all fields to make
+// the resources browsable without the OpenShift validation.
+func importOpenShiftCRDs(ctx context.Context, cfg *importerConfig) error {
+	resources := bundle.OpenShiftResources(cfg.bundle.Layout())
+	if len(resources) == 0 {
+		return nil
+	}
+
+	crdGVR := schema.GroupVersionResource{
+		Group:    "apiextensions.k8s.io",
+		Version:  "v1",
+		Resource: "customresourcedefinitions",
+	}
+
+	var imported []string
+	for i := range resources {
+		crd, err := openShiftCRD(&resources[i])
+		if err != nil {
+			return err
+		}
+		served, _ := crdServedResource(crd)
+		if isResourceServed(cfg.discoveryClient, served) {
+			continue
+		}
+
+		if err := importObject(ctx, cfg, crdGVR, crd, false); err != nil {
+			cfg.out.Warnf("Failed to import CRD %q for OpenShift resources with error: %s", crd.GetName(), err)
+			continue
+		}
+		imported = append(imported, crd.GetName())
+	}
+
+	cfg.out.V(1).Infof("Waiting for %d OpenShift CRDs to be established by API server", len(imported))
+	failed := waitForCRDsEstablished(
+		ctx, cfg.dynamicClient, imported, crdEstablishPollInterval, DefaultCRDEstablishTimeout)
+	for _, name := range imported {
+		if reason, ok := failed[name]; ok {
+			cfg.out.Warnf("CRD %q: %s, OpenShift resources may fail to import", name, reason)
+		}
+	}
+	return nil
+}
+
+// openShiftCRD returns CRD serving the OpenShift resource with schema that
+// preserves unknown fields.
+func openShiftCRD(r *bundle.OpenShiftResource) (*unstructured.Unstructured, error) {
+	scope := apiextensionsv1.ClusterScoped
+	if r.Namespaced {
+		scope = apiextensionsv1.NamespaceScoped
+	}
+
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiextensionsv1.SchemeGroupVersion.String(),
+			Kind:       "CustomResourceDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: r.Resource + "." + r.GVK.Group},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: r.GVK.Group,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural:   r.Resource,
+				Singular: strings.ToLower(r.GVK.Kind),
+				Kind:     r.GVK.Kind,
+				ListKind: r.GVK.Kind + "List",
+			},
+			Scope: scope,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+				Name:    r.GVK.Version,
+				Served:  true,
+				Storage: true,
+				Schema: &apiextensionsv1.CustomResourceValidation{
+					OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+						Type:                   "object",
+						XPreserveUnknownFields: ptr.To(true),
+					},
+				},
+				Subresources: &apiextensionsv1.CustomResourceSubresources{
+					Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+				},
+			}},
+		},
+	}
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(crd)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: obj}, nil
+}
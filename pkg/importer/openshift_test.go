@@ -0,0 +1,49 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+func TestOpenShiftCRD(t *testing.T) {
+	crd, err := openShiftCRD(&bundle.OpenShiftResource{
+		GVK:        schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1", Kind: "Route"},
+		Resource:   "routes",
+		Namespaced: true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "routes.route.openshift.io", crd.GetName())
+	scope, _, _ := unstructured.NestedString(crd.Object, "spec", "scope")
+	assert.Equal(t, "Namespaced", scope)
+
+	served, ok := crdServedResource(crd)
+	require.True(t, ok)
+	assert.Equal(t, servedCRD{name: "routes.route.openshift.io", groupVersion: "route.openshift.io/v1", resource: "routes"}, served)
+}
+
+func TestPopulateGVKFromPath_OpenShift(t *testing.T) {
+	b := bundle.FromFsWithLayout(afero.NewMemMapFs(), bundle.WithOpenShiftResources(bundle.FromFs(nil).Layout()))
+
+	for path, kind := range map[string]string{
+		"cluster-resources/routes/default.json":                                         "Route",
+		"cluster-resources/custom-resources/deploymentconfigs.apps.openshift.io/a.json": "DeploymentConfig",
+		"cluster-resources/security-context-constraints.json":                           "SecurityContextConstraints",
+		"cluster-resources/pods/default.json":                                           "Pod",
+	} {
+		list := &unstructured.UnstructuredList{Items: []unstructured.Unstructured{{Object: map[string]any{}}}}
+		require.NoError(t, populateGVKFromPath(b, path, list))
+		assert.Equal(t, kind, list.Items[0].GetKind(), path)
+	}
+
+	list := &unstructured.UnstructuredList{Items: []unstructured.Unstructured{{Object: map[string]any{}}}}
+	require.NoError(t, populateGVKFromPath(bundle.FromFs(afero.NewMemMapFs()), "cluster-resources/routes/default.json", list))
+	assert.Empty(t, list.Items[0].GetKind(), "OpenShift paths are known only for OpenShift bundles")
+}
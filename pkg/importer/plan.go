@@ -0,0 +1,159 @@
+package importer
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+	"github.com/mhrabovcin/troubleshoot-live/pkg/utils"
+)
+
+// PlanStatus describes how a file from the bundle is going to be processed
+// by the import.
+type PlanStatus string
+
+const (
+	// PlanImportable marks files with resources that will be imported.
+	PlanImportable PlanStatus = "importable"
+	// PlanSkipped marks files that are not imported on purpose.
+	PlanSkipped PlanStatus = "skipped"
+	// PlanProblematic marks files that are expected to fail the import.
+	PlanProblematic PlanStatus = "problematic"
+)
+
+// PlanEntry is the import plan for a single file from the bundle.
+type PlanEntry struct {
+	Path   string     `json:"path"`
+	Status PlanStatus `json:"status"`
+	Reason string     `json:"reason,omitempty"`
+	// Kinds contains number of resources per detected GroupVersionKind.
+	Kinds map[string]int `json:"kinds,omitempty"`
+}
+
+// Plan describes what is going to be imported from the bundle.
+type Plan struct {
+	Entries []PlanEntry `json:"entries"`
+}
+
+// Count returns number of plan entries with given status.
+func (p *Plan) Count(status PlanStatus) int {
+	count := 0
+	for i := range p.Entries {
+		if p.Entries[i].Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+func (p *Plan) add(path string, status PlanStatus, reason string) *PlanEntry {
+	p.Entries = append(p.Entries, PlanEntry{Path: path, Status: status, Reason: reason})
+	return &p.Entries[len(p.Entries)-1]
+}
+
+// ImportPlan walks the bundle cluster resources and classifies each file by
+// applying the same skip rules and GVK inference as the import. The plan is
+// created without API server, so resources that would be rejected by the API
+// server, e.g. custom resources without CRD, are reported as importable.
+func ImportPlan(b bundle.Bundle) (*Plan, error) {
+	plan := &Plan{}
+	root := b.Layout().ClusterResources()
+
+	err := afero.Walk(b, root, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			plan.add(path, PlanProblematic, fmt.Sprintf("failed to read: %s", err))
+			return nil
+		}
+
+		if info.IsDir() {
+			if path != root && isSkippedDir(b, info.Name()) {
+				plan.add(path, PlanSkipped, "directory is skipped")
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		path, ok := chunkedFilePath(path)
+		if !ok {
+			return nil
+		}
+
+		switch {
+		case path == filepath.Join(root, "custom-resource-definitions.json"):
+			planFile(b, plan, path, "imported before other resources", schema.GroupVersionKind{
+				Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition",
+			})
+			return nil
+		case path == filepath.Join(root, "namespaces.json"):
+			planFile(b, plan, path, "imported before other resources", schema.GroupVersionKind{
+				Version: "v1", Kind: "Namespace",
+			})
+			return nil
+		case isSkippedResource(b, path):
+			plan.add(path, PlanSkipped, "file is skipped")
+			return nil
+		case isErrorsFile(path):
+			plan.add(path, PlanSkipped, "file contains collection errors")
+			return nil
+		}
+
+		planFile(b, plan, path, "", schema.GroupVersionKind{})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	planCMOrSecrets(b, plan, b.Layout().ConfigMaps(), "/v1, Kind=ConfigMap")
+	planCMOrSecrets(b, plan, b.Layout().Secrets(), "/v1, Kind=Secret")
+
+	return plan, nil
+}
+
+func planCMOrSecrets(b bundle.Bundle, plan *Plan, root, kind string) {
+	_ = afero.Walk(b, root, func(path string, info fs.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		plan.add(path, PlanImportable, "").Kinds = map[string]int{kind: 1}
+		return nil
+	})
+}
+
+func planFile(b bundle.Bundle, plan *Plan, path, reason string, fallback schema.GroupVersionKind) {
+	list, err := bundle.LoadResourcesFromFile(b, path)
+	if err != nil {
+		plan.add(path, PlanProblematic, utils.MaxErrorString(err, 200).Error())
+		return
+	}
+
+	if len(list.Items) == 0 {
+		plan.add(path, PlanSkipped, "no resources")
+		return
+	}
+
+	if fallback.Empty() {
+		if err := populateGVKFromPath(b, path, list); err != nil {
+			plan.add(path, PlanProblematic, err.Error())
+			return
+		}
+	} else {
+		populateGVK(list, fallback)
+	}
+
+	kinds := map[string]int{}
+	for i := range list.Items {
+		gvk := list.Items[i].GroupVersionKind()
+		if gvk.Kind == "" || gvk.Version == "" {
+			plan.add(path, PlanProblematic, "cannot detect kind of resources")
+			return
+		}
+		kinds[gvk.String()]++
+	}
+
+	plan.add(path, PlanImportable, reason).Kinds = kinds
+}
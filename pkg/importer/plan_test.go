@@ -0,0 +1,63 @@
+package importer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+func newTestBundle(t *testing.T, files map[string]string) bundle.Bundle {
+	t.Helper()
+
+	fs := afero.NewMemMapFs()
+	for path, data := range files {
+		require.NoError(t, afero.WriteReader(fs, path, bytes.NewBufferString(data)))
+	}
+	return bundle.FromFs(fs)
+}
+
+func TestImportPlan(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-resources/namespaces.json":                  `{"items": [{"metadata": {"name": "default"}}]}`,
+		"cluster-resources/pods/default.json":                `{"items": [{"metadata": {"name": "a"}}, {"metadata": {"name": "b"}}]}`,
+		"cluster-resources/pods/broken-errors.json":          `["forbidden"]`,
+		"cluster-resources/pods/compressed-errors.json.gz":   `not gzip`,
+		"cluster-resources/groups.json":                      `{}`,
+		"cluster-resources/auth-cani-list/default.json":      `{}`,
+		"cluster-resources/unknown/default.json":             `{"items": [{"metadata": {"name": "a"}}]}`,
+		"cluster-resources/custom/default.json":              `{"items": [{"apiVersion": "example.com/v1", "kind": "Foo"}]}`,
+		"cluster-resources/invalid.json":                     `{`,
+		"configmaps/default/kube-root-ca.crt.json":           `{}`,
+		"cluster-resources/custom-resource-definitions.json": `[]`,
+	})
+
+	plan, err := ImportPlan(b)
+	require.NoError(t, err)
+
+	byPath := map[string]PlanEntry{}
+	for _, e := range plan.Entries {
+		byPath[e.Path] = e
+	}
+
+	assert.Equal(t, PlanImportable, byPath["cluster-resources/namespaces.json"].Status)
+	assert.Equal(t, map[string]int{"/v1, Kind=Namespace": 1}, byPath["cluster-resources/namespaces.json"].Kinds)
+	assert.Equal(t, map[string]int{"/v1, Kind=Pod": 2}, byPath["cluster-resources/pods/default.json"].Kinds)
+	assert.Equal(t, PlanSkipped, byPath["cluster-resources/pods/broken-errors.json"].Status)
+	assert.Equal(t, PlanSkipped, byPath["cluster-resources/pods/compressed-errors.json.gz"].Status)
+	assert.Equal(t, PlanSkipped, byPath["cluster-resources/groups.json"].Status)
+	assert.Equal(t, PlanSkipped, byPath["cluster-resources/auth-cani-list"].Status)
+	assert.NotContains(t, byPath, "cluster-resources/auth-cani-list/default.json")
+	assert.Equal(t, PlanProblematic, byPath["cluster-resources/unknown/default.json"].Status)
+	assert.Equal(t, map[string]int{"example.com/v1, Kind=Foo": 1}, byPath["cluster-resources/custom/default.json"].Kinds)
+	assert.Equal(t, PlanProblematic, byPath["cluster-resources/invalid.json"].Status)
+	assert.Equal(t, PlanImportable, byPath["configmaps/default/kube-root-ca.crt.json"].Status)
+	assert.Equal(t, PlanSkipped, byPath["cluster-resources/custom-resource-definitions.json"].Status)
+
+	assert.Equal(t, 4, plan.Count(PlanImportable))
+	assert.Equal(t, 2, plan.Count(PlanProblematic))
+}
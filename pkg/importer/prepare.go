@@ -8,10 +8,7 @@ import (
 
 // prepareForImport modifies object loaded from support bundle file in a way
 // that can be imported.
-func prepareForImport(in any) error {
-	// TODO(mh): inject
-	rr := rewriter.Default()
-
+func prepareForImport(in any, rr rewriter.ResourceRewriter) error {
 	u, ok := in.(*unstructured.Unstructured)
 	if !ok {
 		panic("non unstructured obj")
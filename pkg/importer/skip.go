@@ -0,0 +1,37 @@
+package importer
+
+import (
+	"path/filepath"
+	"strings"
+
+	"k8s.io/utils/strings/slices"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+func isSkippedResource(b bundle.Bundle, name string) bool {
+	return b.Layout().SkipResourceMatches(filepath.Base(name))
+}
+
+func isSkippedDir(b bundle.Bundle, name string) bool {
+	return slices.Contains(b.Layout().SkipDirs(), filepath.Base(name))
+}
+
+// isErrorsFile checks if the file contains errors from collecting resources
+// instead of resources, e.g. `pods-errors.json` or `pods-errors.json.gz`.
+func isErrorsFile(path string) bool {
+	name := strings.TrimSuffix(filepath.Base(path), ".gz")
+	return strings.HasSuffix(strings.TrimSuffix(name, filepath.Ext(name)), "-errors")
+}
+
+// chunkedFilePath returns path of the chunked file for the first chunk, e.g.
+// `pods/default.json` for `pods/default.json.part1`. False is returned for
+// other chunks, which are loaded together with the first chunk. Paths of
+// files that are not chunked are returned unchanged.
+func chunkedFilePath(path string) (string, bool) {
+	base, part, ok := bundle.SplitChunkPath(path)
+	if !ok {
+		return path, true
+	}
+	return base, part == 1
+}
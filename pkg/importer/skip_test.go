@@ -0,0 +1,22 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsErrorsFile(t *testing.T) {
+	for path, expected := range map[string]bool{
+		"cluster-resources/pods-errors.json":            true,
+		"cluster-resources/pods/default-errors.json":    true,
+		"cluster-resources/pods-errors.yaml":            true,
+		"cluster-resources/pods-errors.json.gz":         true,
+		"cluster-resources/pods/default-errors.json.gz": true,
+		"cluster-resources/pods.json":                   false,
+		"cluster-resources/pods/default.json.gz":        false,
+		"cluster-resources/pods/my-errors-app.json":     false,
+	} {
+		assert.Equal(t, expected, isErrorsFile(path), path)
+	}
+}
@@ -1,15 +1,33 @@
 package kubernetes
 
 import (
+	"encoding/json"
 	"log"
 	"os"
 	"path/filepath"
 
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
 )
 
+// KubeconfigExtensionName is the name of the cluster extension with metadata
+// of the cluster from which was the bundle collected.
+const KubeconfigExtensionName = "troubleshoot-live"
+
+// defaultClusterName is used in the kubeconfig when the cluster name cannot
+// be detected from the bundle.
+const defaultClusterName = "support-bundle"
+
+// ClusterMetadata describes the cluster from which was the bundle collected.
+type ClusterMetadata struct {
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
 // WriteProxyKubeconfig creates a KUBECONFIG file for http proxy server. If path
 // for kubeconfig is not provided then default value is create in `CWD`.
 func WriteProxyKubeconfig(host, path string) (string, error) {
@@ -35,6 +53,44 @@ func WriteProxyKubeconfig(host, path string) (string, error) {
 	return absPath, nil
 }
 
+// GenerateKubeconfig creates kubeconfig for the API server serving the bundle
+// at serverURL. The cluster and context are named after the cluster detected
+// from the bundle and the cluster metadata are stored in the cluster extension
+// KubeconfigExtensionName.
+func GenerateKubeconfig(b bundle.Bundle, serverURL string) ([]byte, error) {
+	name, err := bundle.DetectClusterName(b)
+	if err != nil {
+		return nil, err
+	}
+	version, err := bundle.DetectClusterVersion(b)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := json.Marshal(ClusterMetadata{Name: name, Version: version})
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		name = defaultClusterName
+	}
+	cluster := clientcmdapi.NewCluster()
+	cluster.Server = serverURL
+	cluster.Extensions[KubeconfigExtensionName] = &runtime.Unknown{Raw: metadata, ContentType: runtime.ContentTypeJSON}
+
+	return clientcmd.Write(clientcmdapi.Config{
+		Kind:       "Config",
+		APIVersion: "v1",
+		Clusters:   map[string]*clientcmdapi.Cluster{name: cluster},
+		Contexts: map[string]*clientcmdapi.Context{
+			name: {Cluster: name, AuthInfo: name},
+		},
+		AuthInfos:      map[string]*clientcmdapi.AuthInfo{name: {}},
+		CurrentContext: name,
+	})
+}
+
 func restConfigToKubeconfig(rc *rest.Config, path string) error {
 	clusters := map[string]*clientcmdapi.Cluster{}
 	clusters["default-cluster"] = &clientcmdapi.Cluster{
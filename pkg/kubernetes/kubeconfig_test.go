@@ -0,0 +1,62 @@
+package kubernetes
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+func newTestBundle(t *testing.T, files map[string]string) bundle.Bundle {
+	t.Helper()
+
+	fs := afero.NewMemMapFs()
+	for path, data := range files {
+		require.NoError(t, afero.WriteReader(fs, path, bytes.NewBufferString(data)))
+	}
+	return bundle.FromFs(fs)
+}
+
+func TestGenerateKubeconfig(t *testing.T) {
+	b := newTestBundle(t, map[string]string{
+		"cluster-info/cluster_version.json": `{"info": {"gitVersion": "v1.27.3"}, "string": "v1.27.3"}`,
+		"cluster-resources/configmaps/kube-system.json": `{"items": [{
+  "metadata": {"name": "kubeadm-config", "namespace": "kube-system"},
+  "data": {"ClusterConfiguration": "apiVersion: kubeadm.k8s.io/v1beta3\nkind: ClusterConfiguration\nclusterName: prod-eu\n"}
+}]}`,
+	})
+
+	data, err := GenerateKubeconfig(b, "http://localhost:8080")
+	require.NoError(t, err)
+
+	cfg, err := clientcmd.Load(data)
+	require.NoError(t, err)
+	assert.Equal(t, "prod-eu", cfg.CurrentContext)
+	require.Contains(t, cfg.Contexts, "prod-eu")
+	assert.Equal(t, "prod-eu", cfg.Contexts["prod-eu"].Cluster)
+	require.Contains(t, cfg.Clusters, "prod-eu")
+	assert.Equal(t, "http://localhost:8080", cfg.Clusters["prod-eu"].Server)
+
+	extension, ok := cfg.Clusters["prod-eu"].Extensions[KubeconfigExtensionName].(*runtime.Unknown)
+	require.True(t, ok)
+	metadata := ClusterMetadata{}
+	require.NoError(t, json.Unmarshal(extension.Raw, &metadata))
+	assert.Equal(t, ClusterMetadata{Name: "prod-eu", Version: "v1.27.3"}, metadata)
+}
+
+func TestGenerateKubeconfig_UnknownCluster(t *testing.T) {
+	data, err := GenerateKubeconfig(newTestBundle(t, map[string]string{}), "http://localhost:8080")
+	require.NoError(t, err)
+
+	cfg, err := clientcmd.Load(data)
+	require.NoError(t, err)
+	assert.Equal(t, defaultClusterName, cfg.CurrentContext)
+	assert.Equal(t, "http://localhost:8080", cfg.Clusters[defaultClusterName].Server)
+}
@@ -0,0 +1,76 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Supported values for the logs encoding option. The empty value serves the
+// log bytes as they are stored in the bundle unless a BOM is detected.
+const (
+	EncodingAuto    = ""
+	EncodingLatin1  = "latin1"
+	EncodingUTF16LE = "utf-16le"
+	EncodingUTF16BE = "utf-16be"
+)
+
+const (
+	bomUTF8    = "\xEF\xBB\xBF"
+	bomUTF16LE = "\xFF\xFE"
+	bomUTF16BE = "\xFE\xFF"
+)
+
+// ValidateEncoding checks if the provided logs encoding is supported.
+func ValidateEncoding(encoding string) error {
+	switch encoding {
+	case EncodingAuto, EncodingLatin1, EncodingUTF16LE, EncodingUTF16BE:
+		return nil
+	default:
+		return fmt.Errorf("unsupported logs encoding %q", encoding)
+	}
+}
+
+// decodeToUTF8 transcodes log data to UTF-8. A BOM always takes precedence
+// over the provided encoding, as it is unambiguous. Without BOM the data are
+// transcoded only if the encoding was explicitly provided, to avoid mangling
+// logs that contain binary data.
+func decodeToUTF8(data []byte, encoding string) []byte {
+	switch {
+	case bytes.HasPrefix(data, []byte(bomUTF8)):
+		return data[len(bomUTF8):]
+	case bytes.HasPrefix(data, []byte(bomUTF16LE)):
+		return decodeUTF16(data[len(bomUTF16LE):], binary.LittleEndian)
+	case bytes.HasPrefix(data, []byte(bomUTF16BE)):
+		return decodeUTF16(data[len(bomUTF16BE):], binary.BigEndian)
+	}
+
+	switch encoding {
+	case EncodingLatin1:
+		return decodeLatin1(data)
+	case EncodingUTF16LE:
+		return decodeUTF16(data, binary.LittleEndian)
+	case EncodingUTF16BE:
+		return decodeUTF16(data, binary.BigEndian)
+	default:
+		return data
+	}
+}
+
+func decodeUTF16(data []byte, order binary.ByteOrder) []byte {
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		units = append(units, order.Uint16(data[i:]))
+	}
+	return []byte(string(utf16.Decode(units)))
+}
+
+func decodeLatin1(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	for _, b := range data {
+		out = utf8.AppendRune(out, rune(b))
+	}
+	return out
+}
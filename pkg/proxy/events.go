@@ -0,0 +1,47 @@
+package proxy
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+// EventsPath is the path of the handler serving events from all namespaces.
+const EventsPath = "/troubleshoot-live/events"
+
+// EventsHandler serves events from all namespaces of the bundle as a JSON
+// list sorted by the time of the last occurrence, the most recent first.
+// The optional `limit` query parameter limits the number of returned events.
+func EventsHandler(b bundle.Bundle, l *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit := 0
+		if value := r.URL.Query().Get("limit"); value != "" {
+			var err error
+			if limit, err = strconv.Atoi(value); err != nil || limit < 0 {
+				http.Error(w, "limit must be a non-negative integer", http.StatusBadRequest)
+				return
+			}
+		}
+
+		events, err := bundle.LoadAllEvents(b)
+		if err != nil {
+			l.Error("failed to load events", "err", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if limit > 0 && len(events.Items) > limit {
+			events.Items = events.Items[:limit]
+		}
+
+		data, err := json.Marshal(events)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	}
+}
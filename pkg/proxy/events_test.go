@@ -0,0 +1,43 @@
+package proxy
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+func TestEventsHandler(t *testing.T) {
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		"cluster-resources/events/default.json": []byte(`{"items": [
+  {"metadata": {"name": "old"}, "lastTimestamp": "2024-01-01T10:00:00Z"},
+  {"metadata": {"name": "new"}, "lastTimestamp": "2024-01-01T11:00:00Z"}
+]}`),
+		"cluster-resources/events/kube-system.json": []byte(`{"items": [
+  {"apiVersion": "events.k8s.io/v1", "kind": "Event", "metadata": {"name": "middle"}, "eventTime": "2024-01-01T10:30:00.000000Z"}
+]}`),
+	}))
+
+	serve := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		EventsHandler(b, slog.Default())(w, httptest.NewRequest(http.MethodGet, EventsPath+query, http.NoBody))
+		return w
+	}
+
+	w := serve("?limit=2")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	list := &unstructured.UnstructuredList{}
+	require.NoError(t, list.UnmarshalJSON(w.Body.Bytes()))
+	require.Len(t, list.Items, 2)
+	assert.Equal(t, "new", list.Items[0].GetName())
+	assert.Equal(t, "middle", list.Items[1].GetName())
+
+	assert.Equal(t, http.StatusBadRequest, serve("?limit=abc").Code)
+}
@@ -0,0 +1,243 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/spf13/afero"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/httpstream/spdy"
+	"k8s.io/apimachinery/pkg/util/remotecommand"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+const (
+	execStdoutSuffix = "-stdout.txt"
+	execStderrSuffix = "-stderr.txt"
+)
+
+// capturedExec is an output of a command executed in a pod by the exec collector.
+type capturedExec struct {
+	stdout []byte
+	stderr []byte
+}
+
+// findCapturedExecs returns outputs stored by the exec collector for given pod
+// indexed by the collector name. The exec collector stores outputs in
+// `<collector>/<namespace>/<pod>/<name>-stdout.txt` and `-stderr.txt` files.
+func findCapturedExecs(b bundle.Bundle, namespace, pod string) (map[string]*capturedExec, error) {
+	captured := map[string]*capturedExec{}
+	for _, suffix := range []string{execStdoutSuffix, execStderrSuffix} {
+		matches, err := afero.Glob(b, filepath.Join("*", namespace, pod, "*"+suffix))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, match := range matches {
+			data, err := afero.ReadFile(b, match)
+			if err != nil {
+				return nil, err
+			}
+
+			name := strings.TrimSuffix(filepath.Base(match), suffix)
+			if captured[name] == nil {
+				captured[name] = &capturedExec{}
+			}
+			if suffix == execStdoutSuffix {
+				captured[name].stdout = data
+			} else {
+				captured[name].stderr = data
+			}
+		}
+	}
+	return captured, nil
+}
+
+// ExecHandler serves k8s `exec` subresource from command outputs captured by
+// the exec collector. The collector name is used as the command, e.g.
+// `kubectl exec pod -- mysql-version` returns the output captured by the
+// `mysql-version` exec collector. Any other command fails with a message that
+// lists the captured commands available for the pod. The outputs are looked up
+// in the bundle namespace of the pod given by the namespace mapping.
+func ExecHandler(b bundle.Bundle, l *slog.Logger, namespaces map[string]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		namespace := namespaceMapping(namespaces).bundleNamespace(vars["namespace"])
+		captured, err := findCapturedExecs(b, namespace, vars["pod"])
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		command := r.URL.Query()["command"]
+		output, ok := captured[strings.Join(command, " ")]
+		if !ok {
+			output = &capturedExec{stderr: []byte(unknownCommandMessage(command, captured))}
+		}
+
+		protocol, err := httpstream.Handshake(r, w, []string{
+			remotecommand.StreamProtocolV4Name,
+			remotecommand.StreamProtocolV3Name,
+			remotecommand.StreamProtocolV2Name,
+		})
+		if err != nil {
+			// Clients fall back to SPDY when other upgrade protocols fail.
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		l := l.With("url", r.URL, "protocol", protocol)
+		if err := serveExec(w, r, protocol, output, ok); err != nil {
+			l.Error("failed to serve exec", "err", err)
+			return
+		}
+		l.Debug("served captured exec output")
+	}
+}
+
+func unknownCommandMessage(command []string, captured map[string]*capturedExec) string {
+	names := make([]string, 0, len(captured))
+	for name := range captured {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if len(names) == 0 {
+		return fmt.Sprintf("command %q is not available: the bundle doesn't contain any captured exec output for the pod\n", command)
+	}
+	return fmt.Sprintf(
+		"command %q is not available: only commands captured in the bundle can be executed: %s\n",
+		command, strings.Join(names, ", "),
+	)
+}
+
+// execStreams are streams created by the client.
+type execStreams struct {
+	errorStream  httpstream.Stream
+	stdoutStream httpstream.Stream
+	stderrStream httpstream.Stream
+}
+
+func serveExec(w http.ResponseWriter, r *http.Request, protocol string, output *capturedExec, success bool) error {
+	streamCh := make(chan httpstream.Stream)
+	conn := spdy.NewResponseUpgrader().UpgradeResponse(w, r, func(s httpstream.Stream, _ <-chan struct{}) error {
+		streamCh <- s
+		return nil
+	})
+	if conn == nil {
+		return fmt.Errorf("failed to upgrade connection")
+	}
+	defer conn.Close()
+
+	streams, err := waitForExecStreams(r, protocol, streamCh)
+	if err != nil {
+		return err
+	}
+
+	tty := r.URL.Query().Get("tty") == "true"
+	if streams.stdoutStream != nil {
+		data := output.stdout
+		// With tty there is no stderr stream and both outputs are merged.
+		if tty {
+			data = append(append([]byte{}, output.stdout...), output.stderr...)
+		}
+		if _, err := streams.stdoutStream.Write(data); err != nil {
+			return err
+		}
+		streams.stdoutStream.Close()
+	}
+	if streams.stderrStream != nil {
+		if _, err := streams.stderrStream.Write(output.stderr); err != nil {
+			return err
+		}
+		streams.stderrStream.Close()
+	}
+
+	defer streams.errorStream.Close()
+	return writeExecStatus(streams.errorStream, protocol, success)
+}
+
+// waitForExecStreams waits until client creates all streams requested by the
+// query parameters.
+func waitForExecStreams(r *http.Request, protocol string, streamCh <-chan httpstream.Stream) (*execStreams, error) {
+	query := r.URL.Query()
+	tty := query.Get("tty") == "true"
+
+	expected := 1 // error stream
+	for _, name := range []string{"stdin", "stdout"} {
+		if query.Get(name) == "true" {
+			expected++
+		}
+	}
+	if query.Get("stderr") == "true" && !tty {
+		expected++
+	}
+	if tty && protocol != remotecommand.StreamProtocolV2Name {
+		expected++ // resize stream
+	}
+
+	streams := &execStreams{}
+	timeout := time.After(remotecommand.DefaultStreamCreationTimeout)
+	for received := 0; received < expected; received++ {
+		select {
+		case stream := <-streamCh:
+			switch stream.Headers().Get(corev1.StreamType) {
+			case corev1.StreamTypeError:
+				streams.errorStream = stream
+			case corev1.StreamTypeStdout:
+				streams.stdoutStream = stream
+			case corev1.StreamTypeStderr:
+				streams.stderrStream = stream
+			case corev1.StreamTypeStdin, corev1.StreamTypeResize:
+				// Input is not processed, captured outputs are static.
+				go func() { _, _ = io.Copy(io.Discard, stream) }()
+			}
+		case <-timeout:
+			return nil, fmt.Errorf("timed out waiting for client to create streams")
+		case <-r.Context().Done():
+			return nil, r.Context().Err()
+		}
+	}
+
+	if streams.errorStream == nil {
+		return nil, fmt.Errorf("client didn't create error stream")
+	}
+	return streams, nil
+}
+
+// writeExecStatus writes the command result to error stream. Since v4 the
+// result is a serialized metav1.Status, older protocols expect an error
+// message and no data on success.
+func writeExecStatus(errorStream io.Writer, protocol string, success bool) error {
+	if protocol != remotecommand.StreamProtocolV4Name {
+		if success {
+			return nil
+		}
+		_, err := errorStream.Write([]byte("command terminated with non-zero exit code: 1"))
+		return err
+	}
+
+	status := metav1.Status{Status: metav1.StatusSuccess}
+	if !success {
+		status = metav1.Status{
+			Status:  metav1.StatusFailure,
+			Reason:  remotecommand.NonZeroExitCodeReason,
+			Message: "command terminated with non-zero exit code: 1",
+			Details: &metav1.StatusDetails{
+				Causes: []metav1.StatusCause{{Type: remotecommand.ExitCodeCauseType, Message: "1"}},
+			},
+		}
+	}
+	return json.NewEncoder(errorStream).Encode(status)
+}
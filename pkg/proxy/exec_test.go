@@ -0,0 +1,78 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+func execInTestServer(t *testing.T, b bundle.Bundle, command string, namespaces map[string]string) (string, string, error) {
+	t.Helper()
+
+	r := mux.NewRouter()
+	r.Handle("/api/v1/namespaces/{namespace}/pods/{pod}/exec", ExecHandler(b, slog.Default(), namespaces))
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	execURL, err := url.Parse(server.URL + "/api/v1/namespaces/default/pods/mysql-0/exec")
+	require.NoError(t, err)
+	execURL.RawQuery = url.Values{
+		"command": []string{command},
+		"stdout":  []string{"true"},
+		"stderr":  []string{"true"},
+	}.Encode()
+
+	executor, err := remotecommand.NewSPDYExecutor(&rest.Config{Host: server.URL}, "POST", execURL)
+	require.NoError(t, err)
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	err = executor.StreamWithContext(context.Background(), remotecommand.StreamOptions{
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+	return stdout.String(), stderr.String(), err
+}
+
+func TestExecHandler_CapturedCommand(t *testing.T) {
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		"mysql/default/mysql-0/mysql-version-stdout.txt": []byte("mysql  Ver 8.0.35\n"),
+		"mysql/default/mysql-0/mysql-version-stderr.txt": []byte("warning\n"),
+	}))
+
+	stdout, stderr, err := execInTestServer(t, b, "mysql-version", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "mysql  Ver 8.0.35\n", stdout)
+	assert.Equal(t, "warning\n", stderr)
+}
+
+func TestExecHandler_UnknownCommand(t *testing.T) {
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		"mysql/default/mysql-0/mysql-version-stdout.txt": []byte("mysql  Ver 8.0.35\n"),
+	}))
+
+	stdout, stderr, err := execInTestServer(t, b, "ls", nil)
+	assert.ErrorContains(t, err, "exit code 1")
+	assert.Empty(t, stdout)
+	assert.Contains(t, stderr, "only commands captured in the bundle can be executed: mysql-version")
+}
+
+func TestExecHandler_NamespaceMapping(t *testing.T) {
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		"mysql/prod/mysql-0/mysql-version-stdout.txt": []byte("mysql  Ver 8.0.35\n"),
+	}))
+
+	stdout, _, err := execInTestServer(t, b, "mysql-version", map[string]string{"prod": "default"})
+	require.NoError(t, err)
+	assert.Equal(t, "mysql  Ver 8.0.35\n", stdout)
+}
@@ -0,0 +1,30 @@
+package proxy
+
+import (
+	"net/http"
+)
+
+// retryAfterSeconds is the value of Retry-After header for requests that were
+// rejected by the concurrency limit.
+const retryAfterSeconds = "1"
+
+// limitConcurrency rejects requests over the maximum number of concurrently
+// served requests with 503 and Retry-After header. Zero value disables
+// the limit.
+func limitConcurrency(h http.HandlerFunc, maxConcurrent int) http.HandlerFunc {
+	if maxConcurrent <= 0 {
+		return h
+	}
+
+	semaphore := make(chan struct{}, maxConcurrent)
+	return func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case semaphore <- struct{}{}:
+			defer func() { <-semaphore }()
+			h(w, r)
+		default:
+			w.Header().Set("Retry-After", retryAfterSeconds)
+			http.Error(w, "too many concurrent requests, try again later", http.StatusServiceUnavailable)
+		}
+	}
+}
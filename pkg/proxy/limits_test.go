@@ -0,0 +1,58 @@
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+func TestLimitConcurrency(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	h := limitConcurrency(func(w http.ResponseWriter, _ *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}, 2)
+
+	done := make(chan int, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			w := httptest.NewRecorder()
+			h(w, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+			done <- w.Code
+		}()
+		<-started
+	}
+
+	w := httptest.NewRecorder()
+	h(w, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	close(release)
+	assert.Equal(t, http.StatusOK, <-done)
+	assert.Equal(t, http.StatusOK, <-done)
+
+	go func() { <-started }()
+	w = httptest.NewRecorder()
+	h(w, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+	assert.Equal(t, http.StatusOK, w.Code, "slots are released after requests finish")
+}
+
+func TestLogsHandler_ReadTimeout(t *testing.T) {
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		"pod-logs/default/test-app.log": []byte("logs"),
+	}))
+
+	w := serveLogs(t, b, "container=app", WithLogsReadTimeout(time.Nanosecond))
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+
+	w = serveLogs(t, b, "container=app", WithLogsReadTimeout(time.Minute))
+	assert.Equal(t, "logs", w.Body.String())
+}
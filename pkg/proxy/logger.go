@@ -0,0 +1,80 @@
+package proxy
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+)
+
+// EnvLogLevel overrides the level of the logger created by NewLogger, e.g.
+// `debug` or `warn`.
+const EnvLogLevel = "TSLIVE_LOG_LEVEL"
+
+const (
+	// LogFormatText formats records as `key=value` pairs.
+	LogFormatText = "text"
+	// LogFormatJSON formats records as JSON objects.
+	LogFormatJSON = "json"
+)
+
+// LoggerOption configures the logger created by NewLogger.
+type LoggerOption func(*loggerOptions)
+
+type loggerOptions struct {
+	format string
+	level  slog.Level
+	output io.Writer
+}
+
+// WithLoggerFormat sets the format of log records, LogFormatText or
+// LogFormatJSON.
+func WithLoggerFormat(format string) LoggerOption {
+	return func(o *loggerOptions) {
+		o.format = format
+	}
+}
+
+// WithLoggerLevel sets the minimal level of logged records. The level is
+// overridden by EnvLogLevel when set.
+func WithLoggerLevel(level slog.Level) LoggerOption {
+	return func(o *loggerOptions) {
+		o.level = level
+	}
+}
+
+// WithLoggerOutput sets the writer for log records.
+func WithLoggerOutput(w io.Writer) LoggerOption {
+	return func(o *loggerOptions) {
+		o.output = w
+	}
+}
+
+// NewLogger creates logger for the handlers. By default records at info level
+// and above are written to stderr in the text format.
+func NewLogger(opts ...LoggerOption) (*slog.Logger, error) {
+	options := &loggerOptions{
+		format: LogFormatText,
+		level:  slog.LevelInfo,
+		output: os.Stderr,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+
+	if value := os.Getenv(EnvLogLevel); value != "" {
+		if err := options.level.UnmarshalText([]byte(value)); err != nil {
+			return nil, fmt.Errorf("invalid %s value %q: %w", EnvLogLevel, value, err)
+		}
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: options.level}
+	switch options.format {
+	case LogFormatText:
+		return slog.New(slog.NewTextHandler(options.output, handlerOpts)), nil
+	case LogFormatJSON:
+		return slog.New(slog.NewJSONHandler(options.output, handlerOpts)), nil
+	default:
+		return nil, fmt.Errorf("unsupported log format %q, expected %q or %q", options.format, LogFormatText, LogFormatJSON)
+	}
+}
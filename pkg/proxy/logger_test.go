@@ -0,0 +1,53 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLogger_Level(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l, err := NewLogger(WithLoggerOutput(buf))
+	require.NoError(t, err)
+
+	l.Debug("hidden")
+	l.Info("visible")
+	assert.NotContains(t, buf.String(), "hidden")
+	assert.Contains(t, buf.String(), "msg=visible")
+}
+
+func TestNewLogger_LevelFromEnv(t *testing.T) {
+	t.Setenv(EnvLogLevel, "warn")
+
+	buf := &bytes.Buffer{}
+	l, err := NewLogger(WithLoggerOutput(buf), WithLoggerLevel(slog.LevelDebug))
+	require.NoError(t, err)
+
+	l.Info("hidden")
+	l.Warn("visible")
+	assert.NotContains(t, buf.String(), "hidden")
+	assert.Contains(t, buf.String(), "visible")
+
+	t.Setenv(EnvLogLevel, "verbose")
+	_, err = NewLogger()
+	assert.ErrorContains(t, err, EnvLogLevel)
+}
+
+func TestNewLogger_JSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l, err := NewLogger(WithLoggerOutput(buf), WithLoggerFormat(LogFormatJSON))
+	require.NoError(t, err)
+
+	l.Info("served", "handler", "LogsHandler")
+	record := map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "LogsHandler", record["handler"])
+
+	_, err = NewLogger(WithLoggerFormat("xml"))
+	assert.Error(t, err)
+}
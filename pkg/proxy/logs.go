@@ -2,76 +2,284 @@ package proxy
 
 import (
 	"bytes"
-	"fmt"
+	"context"
+	"errors"
 	"log/slog"
 	"net/http"
-	"path/filepath"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
-	"github.com/spf13/afero"
 
 	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
 )
 
+// LogsOption configures the LogsHandler.
+type LogsOption func(*logsOptions)
+
+type logsOptions struct {
+	encoding         string
+	namespaceMapping namespaceMapping
+	maxLineLength    int
+	readTimeout      time.Duration
+	maxConcurrency   int
+	maxGlobMatches   int
+	contentType      string
+	remoteTimeout    time.Duration
+}
+
+// DefaultLogsMaxGlobMatches is the default limit of directories matching the
+// pod that are considered when looking up pod logs.
+const DefaultLogsMaxGlobMatches = 100
+
+// DefaultLogsRemoteTimeout is the default limit of time spent by fetching logs
+// from the remote log backend, when the read timeout is not set.
+const DefaultLogsRemoteTimeout = 30 * time.Second
+
+// DefaultLogsContentType is the default content type of served logs. Logs
+// are always served as text, also when stored by the container runtime as
+// JSON lines.
+const DefaultLogsContentType = "text/plain; charset=utf-8"
+
+// WithLogsEncoding sets the encoding used for transcoding logs that do not
+// start with a BOM. Logs with BOM are always transcoded to UTF-8.
+func WithLogsEncoding(encoding string) LogsOption {
+	return func(o *logsOptions) {
+		o.encoding = encoding
+	}
+}
+
+// WithLogsMaxLineLength truncates log lines longer than given number of bytes.
+// Zero value disables truncating.
+func WithLogsMaxLineLength(maxLength int) LogsOption {
+	return func(o *logsOptions) {
+		o.maxLineLength = maxLength
+	}
+}
+
+// WithLogsReadTimeout limits time spent by reading logs from the bundle. Zero
+// value disables the timeout.
+func WithLogsReadTimeout(timeout time.Duration) LogsOption {
+	return func(o *logsOptions) {
+		o.readTimeout = timeout
+	}
+}
+
+// WithLogsMaxConcurrency limits number of concurrently served logs requests.
+// Requests over the limit are rejected with 503. Zero value disables the limit.
+func WithLogsMaxConcurrency(maxConcurrency int) LogsOption {
+	return func(o *logsOptions) {
+		o.maxConcurrency = maxConcurrency
+	}
+}
+
+// WithLogsMaxGlobMatches limits number of directories matching the pod that
+// are considered when looking up logs of pods missing in the pod logs
+// directory. The lookup gives up when the bundle contains more matches. Zero
+// value disables the limit.
+func WithLogsMaxGlobMatches(maxMatches int) LogsOption {
+	return func(o *logsOptions) {
+		o.maxGlobMatches = maxMatches
+	}
+}
+
+// WithLogsRemoteTimeout limits time spent by fetching logs from the remote log
+// backend configured in the layout, see DefaultLogsRemoteTimeout. The timeout
+// applies only when the logs read timeout is not set. Zero value disables
+// the timeout.
+func WithLogsRemoteTimeout(timeout time.Duration) LogsOption {
+	return func(o *logsOptions) {
+		o.remoteTimeout = timeout
+	}
+}
+
+// WithLogsContentType overrides the DefaultLogsContentType, e.g. for clients
+// expecting a different type.
+func WithLogsContentType(contentType string) LogsOption {
+	return func(o *logsOptions) {
+		o.contentType = contentType
+	}
+}
+
+// WithLogsNamespaceMapping configures the mapping from the bundle namespace to
+// the namespace the resources were imported to. The logs are then looked up in
+// the original bundle namespace. New applies the mapping also to captured exec
+// outputs, port-forward responses and metrics.
+func WithLogsNamespaceMapping(mapping map[string]string) LogsOption {
+	return func(o *logsOptions) {
+		o.namespaceMapping = mapping
+	}
+}
+
+func newLogsOptions(opts ...LogsOption) *logsOptions {
+	options := &logsOptions{
+		maxGlobMatches: DefaultLogsMaxGlobMatches,
+		contentType:    DefaultLogsContentType,
+		remoteTimeout:  DefaultLogsRemoteTimeout,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return options
+}
+
 // LogsHandler serves logs for k8s `logs` subresource from the provided bundle.
-func LogsHandler(b bundle.Bundle, l *slog.Logger) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+// Logs of the previous container instance are served with `previous=true` and
+// with `combined=true` the previous logs are followed by the current logs.
+// With `allRestarts=true` logs of all container restarts are served. With
+// `follow=true` the logs are streamed and the response is kept open until
+// the client disconnects. With `pretty=true` JSON log lines are reformatted
+// to `key=value` pairs.
+func LogsHandler(b bundle.Bundle, l *slog.Logger, opts ...LogsOption) http.HandlerFunc {
+	options := newLogsOptions(opts...)
+
+	return limitConcurrency(func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
+		namespace := options.namespaceMapping.bundleNamespace(vars["namespace"])
 
-		podLogsPath := ""
+		ctx := r.Context()
+		if options.readTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, options.readTimeout)
+			defer cancel()
+		}
 
-		// Search for pod logs path in the bundle which could be collected either by the
-		// pod logs collector or by the cluster resources collector, which collects pod logs
-		// for failing pods.
-		filename := fmt.Sprintf("%s-%s.log", vars["pod"], r.URL.Query().Get("container"))
-		candidatePaths := []string{
-			filepath.Join(b.Layout().PodLogs(), vars["namespace"], filename),
-			filepath.Join(b.Layout().ClusterResources(), "pods/logs", vars["namespace"], vars["pod"], r.URL.Query().Get("container")+".log"),
+		query, err := parseLogsQuery(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		}
-		for _, candidatePath := range candidatePaths {
-			if exists, _ := afero.Exists(b, candidatePath); exists {
-				podLogsPath = candidatePath
-				break
-			}
+
+		if query.maxAge > 0 {
+			query.since = maxAgeCutoff(b, query.maxAge, l)
 		}
 
-		if podLogsPath == "" {
-			http.Error(w, "pod logs not found in the bundle", http.StatusInternalServerError)
+		container := r.URL.Query().Get("container")
+		sources, err := resolveRequestedLogs(b, l, namespace, vars["pod"], container, query, options.maxGlobMatches)
+		if errors.Is(err, errPodLogsNotFound) {
+			if remoteURL := bundle.RemoteLogURL(b.Layout(), namespace, vars["pod"], container); remoteURL != "" {
+				serveRemoteLogs(ctx, w, r, remoteURL, query, options, l)
+				return
+			}
+			http.Error(w, podLogsNotFoundMessage(b, namespace, vars["pod"], container), http.StatusNotFound)
 			return
 		}
-
-		data, err := afero.ReadFile(b, podLogsPath)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		l := l.With("url", r.URL, "logs source", podLogsPath)
-
-		// By default the `k9s` requests logs prefixed with timestamp and in the logs pane
-		// only displays a portion without the timestamp, by cutting prefix separated by first
-		// space byte(' '). The troubleshoot.sh requests logs without timestamps, which causes
-		// issues in the logs pane and for some pods the logs are cut from beginnging.
-		// This will backfill zeroed timestamp for each line.
-		if r.URL.Query().Get("timestamps") == "true" {
-			lines := bytes.Split(data, []byte("\n"))
-			timestampPrefixRegexp := regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d{6})?Z `)
-			if !timestampPrefixRegexp.Match(lines[0]) {
-				l.Debug("adding timestamp prefix to logs")
-				zeroTime := []byte(time.UnixMicro(0).Format(time.RFC3339Nano))
-				// Add prefix to each line.
-				for i := range lines {
-					lines[i] = bytes.Join([][]byte{zeroTime, lines[i]}, []byte{' '})
-				}
-				data = bytes.Join(lines, []byte("\n"))
+		// Bundles are immutable, clients can cache the logs until the files
+		// change. Streamed logs are not cached, like the kubelet streams.
+		if !query.follow {
+			etag, err := podLogsETag(b, sources.paths(), r.URL.RawQuery)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("ETag", etag)
+			if etagMatches(r.Header.Get("If-None-Match"), etag) {
+				w.WriteHeader(http.StatusNotModified)
+				return
 			}
 		}
 
-		l.Debug("serving logs")
-		if _, err := w.Write(data); err != nil {
-			slog.Error("failed to write response data", "err", err)
+		data, err := readRequestedLogs(ctx, b, sources)
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			http.Error(w, "timed out reading pod logs from the bundle", http.StatusGatewayTimeout)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		l := l.With("url", r.URL, "logs source", strings.Join(sources.paths(), ","))
+		if query.follow {
+			followPodLogs(w, r, processPodLogs(data, query, options, l), options, l)
+			return
 		}
+		servePodLogs(w, data, query, options, l)
+	}, options.maxConcurrency)
+}
+
+// servePodLogs writes logs processed according to the handler options and
+// the request query.
+func servePodLogs(w http.ResponseWriter, data []byte, query *logsQuery, options *logsOptions, l *slog.Logger) {
+	data = processPodLogs(data, query, options, l)
+
+	l.Debug("serving logs")
+	w.Header().Set("Content-Type", options.contentType)
+	if _, err := w.Write(data); err != nil {
+		slog.Error("failed to write response data", "err", err)
+	}
+}
+
+// processPodLogs processes logs according to the handler options and
+// the request query. The lines are filtered by the grep patterns before
+// truncating and the tail is taken from the filtered lines.
+func processPodLogs(data []byte, query *logsQuery, options *logsOptions, l *slog.Logger) []byte {
+	// Logs from windows containers can be stored as UTF-16, transcode them
+	// before any further processing.
+	data = decodeToUTF8(data, options.encoding)
+
+	data, numbers := filterLines(data, query)
+
+	// Structured logs are reformatted after filtering, so the patterns match
+	// the original lines, and before the tail is taken.
+	if query.pretty {
+		data = prettyJSONLines(data)
+	}
+
+	// Extremely long lines, e.g. dumped binary blobs, break rendering in
+	// clients like k9s.
+	data = truncateLines(data, options.maxLineLength)
+
+	data, firstLine := tailLogLines(data, query.tailLines)
+
+	// By default the `k9s` requests logs prefixed with timestamp and in the logs pane
+	// only displays a portion without the timestamp, by cutting prefix separated by first
+	// space byte(' '). The troubleshoot.sh requests logs without timestamps, which causes
+	// issues in the logs pane and for some pods the logs are cut from beginnging.
+	// This will backfill zeroed timestamp for each line.
+	if query.timestamps {
+		data = backfillTimestamps(data, l)
+	}
+
+	// Line numbers are absolute, i.e. the position of the line in the
+	// whole log, also when only the tail or filtered lines are served.
+	if query.lineNumbers {
+		data = numberLines(data, firstLine, numbers)
+	}
+	return data
+}
+
+// maxAgeCutoff returns the time maxAge before the bundle collection, which
+// is used instead of the current time because the logs are historical. Zero
+// time is returned when the collection time is unknown and the lines are
+// not filtered.
+func maxAgeCutoff(b bundle.Bundle, maxAge time.Duration, l *slog.Logger) time.Time {
+	collectedAt, err := bundle.DetectCollectionTime(b)
+	if err != nil || collectedAt.IsZero() {
+		l.Warn("bundle collection time is unknown, logs are not filtered by maxAge", "err", err)
+		return time.Time{}
+	}
+	return collectedAt.Add(-maxAge)
+}
+
+func backfillTimestamps(data []byte, l *slog.Logger) []byte {
+	lines := bytes.Split(data, []byte("\n"))
+	timestampPrefixRegexp := regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d{6})?Z `)
+	if timestampPrefixRegexp.Match(lines[0]) {
+		return data
+	}
+
+	l.Debug("adding timestamp prefix to logs")
+	zeroTime := []byte(time.UnixMicro(0).Format(time.RFC3339Nano))
+	// Add prefix to each line.
+	for i := range lines {
+		lines[i] = bytes.Join([][]byte{zeroTime, lines[i]}, []byte{' '})
 	}
+	return bytes.Join(lines, []byte("\n"))
 }
@@ -0,0 +1,124 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+// combinedLogsSeparator is the line served between logs of the previous and
+// the current container instance.
+const combinedLogsSeparator = "----- previous container logs end, current container logs start -----"
+
+// restartLogsSeparator is the line served before logs of each container
+// restart except the first one with the `allRestarts` query.
+func restartLogsSeparator(restart int) string {
+	return fmt.Sprintf("----- container restart %d -----", restart)
+}
+
+// podLogsSource is a logs file served in a response with other files.
+type podLogsSource struct {
+	files *podLogsFiles
+	// separator is the line served before the logs when they don't come
+	// first.
+	separator string
+}
+
+// podLogsSources are logs files served in a single response.
+type podLogsSources []podLogsSource
+
+func (s podLogsSources) paths() []string {
+	var paths []string
+	for _, source := range s {
+		paths = append(paths, source.files.paths()...)
+	}
+	return paths
+}
+
+// resolveRequestedLogs returns logs files of the current or the previous
+// container instance. With the `combined` query both are returned, previous
+// first, and it is enough when only one of them exists. With the
+// `allRestarts` query logs of all container restarts stored in the kubelet
+// pod logs directory are returned, or the combined logs when the directory
+// wasn't collected.
+func resolveRequestedLogs(
+	b bundle.Bundle, l *slog.Logger, namespace, pod, container string, query *logsQuery, maxGlobMatches int,
+) (podLogsSources, error) {
+	if query.allRestarts {
+		sources, err := resolveRestartsLogs(b, l, namespace, pod, container, maxGlobMatches)
+		if err != nil || len(sources) > 0 {
+			return sources, err
+		}
+	}
+
+	if !query.combined && !query.allRestarts {
+		files, err := resolvePodLogsFiles(b, l, namespace, pod, container, query.previous, maxGlobMatches)
+		if err != nil {
+			return nil, err
+		}
+		return podLogsSources{{files: files}}, nil
+	}
+
+	var sources podLogsSources
+	for _, previous := range []bool{true, false} {
+		files, err := resolvePodLogsFiles(b, l, namespace, pod, container, previous, maxGlobMatches)
+		if errors.Is(err, errPodLogsNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, podLogsSource{files: files, separator: combinedLogsSeparator})
+	}
+	if len(sources) == 0 {
+		return nil, errPodLogsNotFound
+	}
+	return sources, nil
+}
+
+// resolveRestartsLogs returns logs of all container restarts from the kubelet
+// pod logs directory ordered by the restart number.
+func resolveRestartsLogs(
+	b bundle.Bundle, l *slog.Logger, namespace, pod, container string, maxGlobMatches int,
+) (podLogsSources, error) {
+	dir, err := findKubeletContainerDir(b, l, namespace, pod, container, maxGlobMatches)
+	if err != nil || dir == "" {
+		return nil, err
+	}
+	restarts, err := kubeletRestartLogs(b, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make(podLogsSources, 0, len(restarts))
+	for _, restart := range restarts {
+		sources = append(sources, podLogsSource{
+			files:     &podLogsFiles{combined: restart.path},
+			separator: restartLogsSeparator(restart.restart),
+		})
+	}
+	return sources, nil
+}
+
+// readRequestedLogs reads and concatenates logs of all sources separated by
+// the separator lines.
+func readRequestedLogs(ctx context.Context, b bundle.Bundle, sources podLogsSources) ([]byte, error) {
+	var result []byte
+	for i, source := range sources {
+		data, err := readPodLogs(ctx, b, source.files)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			if len(result) > 0 && result[len(result)-1] != '\n' {
+				result = append(result, '\n')
+			}
+			result = append(result, source.separator+"\n"...)
+		}
+		result = append(result, data...)
+	}
+	return result, nil
+}
@@ -0,0 +1,60 @@
+package proxy
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+func TestLogsHandler_Combined(t *testing.T) {
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		"pod-logs/default/test-app-previous.log":  []byte("starting\npanic: nil map"),
+		"pod-logs/default/test-app.log":           []byte("starting\nlistening on :8080\n"),
+		"pod-logs/default/test-sidecar.log":       []byte("sidecar\n"),
+		"pod-logs/default/test-init-previous.log": []byte("init failed\n"),
+	}))
+
+	w := serveLogs(t, b, "container=app&combined=true")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "starting\npanic: nil map\n"+combinedLogsSeparator+"\nstarting\nlistening on :8080\n", w.Body.String())
+
+	w = serveLogs(t, b, "container=app&previous=true")
+	assert.Equal(t, "starting\npanic: nil map", w.Body.String())
+
+	w = serveLogs(t, b, "container=app&combined=true&tailLines=2")
+	assert.Equal(t, "starting\nlistening on :8080\n", w.Body.String())
+
+	// Only one of the logs exists.
+	w = serveLogs(t, b, "container=sidecar&combined=true")
+	assert.Equal(t, "sidecar\n", w.Body.String())
+	w = serveLogs(t, b, "container=init&combined=true")
+	assert.Equal(t, "init failed\n", w.Body.String())
+
+	w = serveLogs(t, b, "container=missing&combined=true")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestLogsHandler_AllRestarts(t *testing.T) {
+	dir := "host-logs/var/log/pods/default_test_7f3c9a2e/app/"
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		dir + "0.log": []byte("attempt 0\n"),
+		dir + "1.log": []byte("attempt 1"),
+		dir + "2.log": []byte("attempt 2\n"),
+		dir + "x.log": []byte("ignored"),
+		"pod-logs/default/test-sidecar-previous.log": []byte("previous\n"),
+		"pod-logs/default/test-sidecar.log":          []byte("current\n"),
+	}))
+
+	w := serveLogs(t, b, "container=app&allRestarts=true")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "attempt 0\n"+
+		restartLogsSeparator(1)+"\nattempt 1\n"+
+		restartLogsSeparator(2)+"\nattempt 2\n", w.Body.String())
+
+	// Without kubelet logs the previous and current logs are combined.
+	w = serveLogs(t, b, "container=sidecar&allRestarts=true")
+	assert.Equal(t, "previous\n"+combinedLogsSeparator+"\ncurrent\n", w.Body.String())
+}
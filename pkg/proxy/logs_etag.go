@@ -0,0 +1,64 @@
+package proxy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+// podLogsETag returns weak ETag derived from size and modification time of
+// the logs files and from the query, which changes the served content, e.g.
+// with `timestamps=true`. The ETag is weak because the response can be
+// transformed, e.g. compressed, while the content stays semantically the same.
+func podLogsETag(b bundle.Bundle, paths []string, query string) (string, error) {
+	h := sha256.New()
+	for _, path := range paths {
+		files, err := logsFileParts(b, path)
+		if err != nil {
+			return "", err
+		}
+		for _, file := range files {
+			fi, err := b.Stat(file)
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(h, "%s:%d:%d\n", file, fi.Size(), fi.ModTime().UnixNano())
+		}
+	}
+	fmt.Fprintf(h, "?%s", query)
+	return fmt.Sprintf(`W/"%s"`, hex.EncodeToString(h.Sum(nil))[:32]), nil
+}
+
+// logsFileParts returns the path itself or its chunks when the logs file was
+// split to chunks.
+func logsFileParts(b bundle.Bundle, path string) ([]string, error) {
+	exists, err := afero.Exists(b, path)
+	if err != nil || exists {
+		return []string{path}, err
+	}
+	chunks, err := bundle.ChunkPaths(b, path)
+	if err != nil || len(chunks) > 0 {
+		return chunks, err
+	}
+	return []string{path}, nil
+}
+
+// etagMatches checks If-None-Match header value against the ETag using weak
+// comparison.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
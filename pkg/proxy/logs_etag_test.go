@@ -0,0 +1,48 @@
+package proxy
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+func TestLogsHandler_ETag(t *testing.T) {
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		"pod-logs/default/test-app.log": []byte("logs"),
+	}))
+
+	w := serveLogs(t, b, "container=app")
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Regexp(t, `^W/".+"$`, etag)
+
+	request := func(query, ifNoneMatch string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/namespaces/default/pods/test/log?"+query, http.NoBody)
+		r = mux.SetURLVars(r, map[string]string{"namespace": "default", "pod": "test"})
+		r.Header.Set("If-None-Match", ifNoneMatch)
+		w := httptest.NewRecorder()
+		LogsHandler(b, slog.Default())(w, r)
+		return w
+	}
+
+	w = request("container=app", etag)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	w = request("container=app", `"other", `+etag[len("W/"):])
+	assert.Equal(t, http.StatusNotModified, w.Code, "strong form matches weak ETag")
+
+	w = request("container=app&timestamps=true", etag)
+	assert.Equal(t, http.StatusOK, w.Code, "different query changes the content")
+
+	w = request("container=app", `W/"other"`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "logs", w.Body.String())
+}
@@ -0,0 +1,32 @@
+package proxy
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+// followPodLogs streams logs like the kubelet does for a running container.
+// The headers are flushed before any data, so the response uses chunked
+// transfer encoding without Content-Length and clients, e.g. k9s, switch to
+// the streaming mode. The bundle logs don't change, so after all lines are
+// sent the response is kept open until the client disconnects. While
+// the response is open it counts to the concurrency limit of the handler.
+func followPodLogs(w http.ResponseWriter, r *http.Request, data []byte, options *logsOptions, l *slog.Logger) {
+	rc := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", options.contentType)
+	w.Header().Del("Content-Length")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		l.Error("streaming logs is not supported by the response writer", "err", err)
+	}
+
+	l.Debug("streaming logs")
+	if _, err := w.Write(data); err != nil {
+		l.Error("failed to write response data", "err", err)
+		return
+	}
+	_ = rc.Flush()
+
+	<-r.Context().Done()
+}
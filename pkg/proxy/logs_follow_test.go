@@ -0,0 +1,46 @@
+package proxy
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+func TestLogsHandler_FollowIsChunked(t *testing.T) {
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		"pod-logs/default/test-app.log": []byte("line 1\nline 2\n"),
+	}))
+
+	r := mux.NewRouter()
+	r.Handle("/api/v1/namespaces/{namespace}/pods/{pod}/log", LogsHandler(b, slog.Default()))
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		server.URL+"/api/v1/namespaces/default/pods/test/log?container=app&follow=true", http.NoBody)
+	require.NoError(t, err)
+	resp, err := server.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"chunked"}, resp.TransferEncoding)
+	assert.Equal(t, int64(-1), resp.ContentLength)
+	assert.Empty(t, resp.Header.Get("ETag"))
+
+	data := make([]byte, len("line 1\nline 2\n"))
+	_, err = io.ReadFull(resp.Body, data)
+	require.NoError(t, err)
+	assert.Equal(t, "line 1\nline 2\n", string(data))
+}
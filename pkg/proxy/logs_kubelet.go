@@ -0,0 +1,116 @@
+package proxy
+
+import (
+	"log/slog"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+// kubeletPodLogsRoots returns patterns of directories with the kubelet pod
+// logs copied from hosts, e.g. `host-logs/var/log/pods` copied by the copy from
+// host collector or `<collector>/<node>/var/log/pods` for collectors run on
+// each node. Only these directories are searched, so that lookups don't visit
+// the whole bundle.
+func kubeletPodLogsRoots() []string {
+	return []string{
+		filepath.Join("var", "log", "pods"),
+		filepath.Join("*", "var", "log", "pods"),
+		filepath.Join("*", "*", "var", "log", "pods"),
+	}
+}
+
+// findKubeletPodLogs looks up the container logs in directories copied from
+// the kubelet pod logs directory, `/var/log/pods/<namespace>_<pod>_<uid>/<container>/<restart>.log`,
+// e.g. by the copy from host collector. The logs are available even for pods
+// that were deleted before the bundle was collected, e.g. pods of completed
+// jobs. Logs of the latest container restart are returned, or logs of the
+// restart before it with previous.
+func findKubeletPodLogs(
+	b bundle.Bundle, l *slog.Logger, namespace, pod, container string, previous bool, maxMatches int,
+) (string, error) {
+	dir, err := findKubeletContainerDir(b, l, namespace, pod, container, maxMatches)
+	if err != nil || dir == "" {
+		return "", err
+	}
+
+	restarts, err := kubeletRestartLogs(b, dir)
+	if err != nil {
+		return "", err
+	}
+	index := len(restarts) - 1
+	if previous {
+		index--
+	}
+	if index < 0 {
+		return "", nil
+	}
+	return restarts[index].path, nil
+}
+
+// findKubeletContainerDir returns the container directory in the kubelet pod
+// logs directories, see kubeletPodLogsRoots. The lookup gives up when
+// maxMatches directories matching the pod don't contain the container logs,
+// which protects requests from pathological bundles.
+func findKubeletContainerDir(
+	b bundle.Bundle, l *slog.Logger, namespace, pod, container string, maxMatches int,
+) (string, error) {
+	if strings.ContainsAny(namespace+pod+container, `/\*?[`) {
+		return "", nil
+	}
+
+	matches := 0
+	for _, root := range kubeletPodLogsRoots() {
+		podDirs, err := afero.Glob(b, filepath.Join(root, namespace+"_"+pod+"_*"))
+		if err != nil {
+			return "", err
+		}
+		sort.Strings(podDirs)
+
+		for _, podDir := range podDirs {
+			if exists, _ := afero.DirExists(b, filepath.Join(podDir, container)); exists {
+				return filepath.Join(podDir, container), nil
+			}
+			matches++
+			if maxMatches > 0 && matches >= maxMatches {
+				l.Warn("too many directories match the pod, giving up pod logs lookup",
+					"namespace", namespace, "pod", pod, "max matches", maxMatches)
+				return "", nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// restartLogsFile is a `<restart>.log` file of a container.
+type restartLogsFile struct {
+	restart int
+	path    string
+}
+
+// kubeletRestartLogs returns the `<restart>.log` files from the container
+// directory ordered by the restart number.
+func kubeletRestartLogs(b bundle.Bundle, dir string) ([]restartLogsFile, error) {
+	entries, err := afero.ReadDir(b, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var restarts []restartLogsFile
+	for _, entry := range entries {
+		restart, err := strconv.Atoi(strings.TrimSuffix(entry.Name(), ".log"))
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".log") || err != nil {
+			continue
+		}
+		restarts = append(restarts, restartLogsFile{restart: restart, path: filepath.Join(dir, entry.Name())})
+	}
+	sort.Slice(restarts, func(i, j int) bool {
+		return restarts[i].restart < restarts[j].restart
+	})
+	return restarts, nil
+}
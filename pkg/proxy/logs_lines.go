@@ -0,0 +1,232 @@
+package proxy
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// truncateLines shortens lines that are longer than maxLength bytes and adds
+// a marker with the number of removed bytes. Lines are cut on a rune boundary.
+func truncateLines(data []byte, maxLength int) []byte {
+	if maxLength <= 0 {
+		return data
+	}
+
+	lines := bytes.Split(data, []byte("\n"))
+	for i, line := range lines {
+		if len(line) <= maxLength {
+			continue
+		}
+
+		cut := maxLength
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		marker := fmt.Sprintf("… [truncated %d bytes]", len(line)-cut)
+		lines[i] = append(line[:cut:cut], marker...)
+	}
+	return bytes.Join(lines, []byte("\n"))
+}
+
+// logsQuery contains query parameters of the logs request that change
+// the served lines.
+type logsQuery struct {
+	tailLines   int
+	grep        *regexp.Regexp
+	grepInvert  *regexp.Regexp
+	timestamps  bool
+	lineNumbers bool
+	previous    bool
+	combined    bool
+	allRestarts bool
+	// follow requests streaming of the logs like for a running container.
+	follow bool
+	// pretty requests reformatting of JSON log lines.
+	pretty bool
+	// maxAge limits served lines to the lines logged within the duration
+	// before the bundle collection.
+	maxAge time.Duration
+	// since is the cutoff time of the maxAge, zero when lines are not
+	// filtered by time.
+	since time.Time
+}
+
+// parseLogsQuery parses the logs request query. The `grep` parameter keeps
+// only lines matching the regexp and `grepv` removes lines matching
+// the regexp. The `combined` parameter requests logs of the previous
+// container instance followed by the current logs and `allRestarts` requests
+// logs of all container restarts. The `maxAge` parameter is
+// a duration, e.g. `1h`.
+func parseLogsQuery(values url.Values) (*logsQuery, error) {
+	tailLines, err := parseTailLines(values.Get("tailLines"))
+	if err != nil {
+		return nil, err
+	}
+	grep, err := parseLinesPattern(values, "grep")
+	if err != nil {
+		return nil, err
+	}
+	grepInvert, err := parseLinesPattern(values, "grepv")
+	if err != nil {
+		return nil, err
+	}
+
+	maxAge, err := parseMaxAge(values.Get("maxAge"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &logsQuery{
+		tailLines:   tailLines,
+		grep:        grep,
+		grepInvert:  grepInvert,
+		timestamps:  values.Get("timestamps") == "true",
+		lineNumbers: values.Get("lineNumbers") == "true",
+		previous:    values.Get("previous") == "true",
+		combined:    values.Get("combined") == "true",
+		allRestarts: values.Get("allRestarts") == "true",
+		follow:      values.Get("follow") == "true",
+		pretty:      values.Get("pretty") == "true",
+		maxAge:      maxAge,
+	}, nil
+}
+
+func parseLinesPattern(values url.Values, name string) (*regexp.Regexp, error) {
+	pattern := values.Get(name)
+	if pattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s pattern %q: %w", name, pattern, err)
+	}
+	return re, nil
+}
+
+// parseTailLines parses the `tailLines` query parameter. Zero means that the
+// whole log is served.
+func parseTailLines(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	tailLines, err := strconv.Atoi(value)
+	if err != nil || tailLines < 0 {
+		return 0, fmt.Errorf("invalid tailLines %q: must be a non-negative integer", value)
+	}
+	return tailLines, nil
+}
+
+func parseMaxAge(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	maxAge, err := time.ParseDuration(value)
+	if err != nil || maxAge <= 0 {
+		return 0, fmt.Errorf("invalid maxAge %q: must be a positive duration, e.g. 1h", value)
+	}
+	return maxAge, nil
+}
+
+// tailLogLines returns the last tailLines lines of the logs and the 1-based
+// number of the first returned line. Zero tailLines returns all lines.
+func tailLogLines(data []byte, tailLines int) ([]byte, int) {
+	if tailLines <= 0 || len(data) == 0 {
+		return data, 1
+	}
+	lines := splitLogLines(data)
+	if len(lines) <= tailLines {
+		return data, 1
+	}
+	first := len(lines) - tailLines
+	return keepTrailingNewline(joinLogLines(lines[first:]), data), first + 1
+}
+
+// filterLines keeps lines matching the grep pattern, not matching
+// the grepInvert pattern and logged after the since cutoff of the query.
+// Returns the filtered logs and 1-based numbers of the kept lines in
+// the original logs. Nil numbers are returned when the logs are not filtered.
+func filterLines(data []byte, query *logsQuery) ([]byte, []int) {
+	grep, grepInvert := query.grep, query.grepInvert
+	if grep == nil && grepInvert == nil && query.since.IsZero() {
+		return data, nil
+	}
+
+	lines := splitLogLines(data)
+	var times []time.Time
+	if !query.since.IsZero() {
+		times = lineTimes(lines)
+	}
+	kept := make([][]byte, 0, len(lines))
+	numbers := make([]int, 0, len(lines))
+	for i, line := range lines {
+		if (grep != nil && !grep.Match(line)) || (grepInvert != nil && grepInvert.Match(line)) {
+			continue
+		}
+		if times != nil && times[i].Before(query.since) {
+			continue
+		}
+		kept = append(kept, line)
+		numbers = append(numbers, i+1)
+	}
+	if len(kept) == 0 {
+		return []byte{}, numbers
+	}
+	return keepTrailingNewline(joinLogLines(kept), data), numbers
+}
+
+// lineTimes returns timestamps of the lines prefixed by a RFC3339 timestamp.
+// Lines without timestamp, e.g. continuation lines of stack traces, get
+// the timestamp of the previous line and leading lines the first timestamp.
+// Nil is returned when none of the lines has a timestamp.
+func lineTimes(lines [][]byte) []time.Time {
+	times := make([]time.Time, len(lines))
+	var last time.Time
+	for i, line := range lines {
+		prefix, _, _ := bytes.Cut(line, []byte(" "))
+		if t, err := time.Parse(time.RFC3339Nano, string(prefix)); err == nil {
+			if last.IsZero() {
+				for j := 0; j < i; j++ {
+					times[j] = t
+				}
+			}
+			last = t
+		}
+		times[i] = last
+	}
+	if last.IsZero() {
+		return nil
+	}
+	return times
+}
+
+// numberLines prefixes each line with its number. Lines are numbered from
+// firstLine, or by the original numbers of filtered lines when numbers are
+// not nil.
+func numberLines(data []byte, firstLine int, numbers []int) []byte {
+	if len(data) == 0 {
+		return data
+	}
+	lines := splitLogLines(data)
+	for i := range lines {
+		number := firstLine + i
+		if numbers != nil {
+			number = numbers[firstLine-1+i]
+		}
+		lines[i] = append([]byte(strconv.Itoa(number)+" "), lines[i]...)
+	}
+	return keepTrailingNewline(joinLogLines(lines), data)
+}
+
+// keepTrailingNewline removes the trailing newline added by joinLogLines when
+// the original logs didn't end with one.
+func keepTrailingNewline(data, original []byte) []byte {
+	if bytes.HasSuffix(original, []byte("\n")) {
+		return data
+	}
+	return bytes.TrimSuffix(data, []byte("\n"))
+}
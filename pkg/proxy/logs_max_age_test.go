@@ -0,0 +1,44 @@
+package proxy
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+func TestLogsHandler_MaxAge(t *testing.T) {
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		"version.yaml": []byte("apiVersion: troubleshoot.sh/v1beta2\nkind: SupportBundle\nspec:\n  collectedAt: \"2024-01-01T12:00:00Z\"\n"),
+		"pod-logs/default/test-app.log": []byte("2024-01-01T10:00:00Z starting\n" +
+			"2024-01-01T11:30:00Z panic: nil map\n" +
+			"goroutine 1 [running]:\n" +
+			"2024-01-01T11:45:00.123456Z restarting\n"),
+		"pod-logs/default/test-plain.log": []byte("no timestamps\nat all\n"),
+	}))
+
+	w := serveLogs(t, b, "container=app&maxAge=1h")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2024-01-01T11:30:00Z panic: nil map\ngoroutine 1 [running]:\n2024-01-01T11:45:00.123456Z restarting\n", w.Body.String())
+
+	w = serveLogs(t, b, "container=app&maxAge=20m&lineNumbers=true")
+	assert.Equal(t, "4 2024-01-01T11:45:00.123456Z restarting\n", w.Body.String())
+
+	// Logs without timestamps are served whole.
+	w = serveLogs(t, b, "container=plain&maxAge=1h")
+	assert.Equal(t, "no timestamps\nat all\n", w.Body.String())
+
+	w = serveLogs(t, b, "container=app&maxAge=-1h")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLogsHandler_MaxAgeUnknownCollectionTime(t *testing.T) {
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		"pod-logs/default/test-app.log": []byte("2024-01-01T10:00:00Z starting\n"),
+	}))
+
+	w := serveLogs(t, b, "container=app&maxAge=1h")
+	assert.Equal(t, "2024-01-01T10:00:00Z starting\n", w.Body.String())
+}
@@ -0,0 +1,31 @@
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+// podLogsNotFoundMessage explains why the logs are missing in the bundle. It
+// distinguishes namespaces that were not collected, pods that are missing in
+// the collected namespace and containers of collected pods without logs.
+func podLogsNotFoundMessage(b bundle.Bundle, namespace, pod, container string) string {
+	list, err := bundle.LoadResources(b, filepath.Join(b.Layout().ClusterResources(), "pods", namespace))
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return fmt.Sprintf("pods of namespace %q were not collected in the bundle", namespace)
+	case err != nil:
+		return fmt.Sprintf("logs of pod %q in namespace %q not found in the bundle", pod, namespace)
+	}
+
+	for i := range list.Items {
+		if list.Items[i].GetName() == pod {
+			return fmt.Sprintf("logs of container %q of pod %q in namespace %q were not collected in the bundle",
+				container, pod, namespace)
+		}
+	}
+	return fmt.Sprintf("pod %q not found in namespace %q in the bundle", pod, namespace)
+}
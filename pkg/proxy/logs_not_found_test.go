@@ -0,0 +1,46 @@
+package proxy
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+func TestLogsHandler_NotFound(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string][]byte
+		expected string
+	}{
+		{
+			name:     "namespace not collected",
+			files:    map[string][]byte{"cluster-resources/pods/kube-system.json": []byte(`{"items": []}`)},
+			expected: `pods of namespace "default" were not collected in the bundle`,
+		},
+		{
+			name: "pod not found in namespace",
+			files: map[string][]byte{
+				"cluster-resources/pods/default.json": []byte(`{"items": [{"metadata": {"name": "other"}}]}`),
+			},
+			expected: `pod "test" not found in namespace "default" in the bundle`,
+		},
+		{
+			name: "container logs not collected",
+			files: map[string][]byte{
+				"cluster-resources/pods/default.json": []byte(`{"items": [{"metadata": {"name": "test"}}]}`),
+			},
+			expected: `logs of container "app" of pod "test" in namespace "default" were not collected in the bundle`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveLogs(t, bundle.FromFs(newMemFs(t, tt.files)), "container=app")
+			assert.Equal(t, http.StatusNotFound, w.Code)
+			assert.Equal(t, tt.expected+"\n", w.Body.String())
+		})
+	}
+}
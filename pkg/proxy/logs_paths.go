@@ -0,0 +1,94 @@
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+// previousLogsSuffix is added by collectors to names of files with logs of
+// the previous container instance.
+const previousLogsSuffix = "-previous"
+
+// logsExt is the extension of logs files. Logs compressed by collectors have
+// the `.log.gz` extension.
+const logsExt = ".log"
+
+// podLogsCandidatePaths returns paths without extension in the bundle where
+// logs for given pod container could be stored. The logs can be collected
+// either by the pod logs collector or by the cluster resources collector,
+// which collects pod logs for failing pods. With previous the paths of logs
+// of the previous container instance are returned.
+func podLogsCandidatePaths(b bundle.Bundle, namespace, pod, container string, previous bool) []string {
+	suffix := ""
+	if previous {
+		suffix = previousLogsSuffix
+	}
+
+	var paths []string
+	for _, podName := range fileNameVariants(pod) {
+		for _, containerName := range fileNameVariants(container) {
+			paths = append(paths,
+				filepath.Join(b.Layout().PodLogs(), namespace, fmt.Sprintf("%s-%s%s", podName, containerName, suffix)),
+				filepath.Join(b.Layout().ClusterResources(), "pods/logs", namespace, podName, containerName+suffix),
+			)
+		}
+	}
+	return paths
+}
+
+// fileNameVariants returns forms in which could be a name stored as a part of
+// file name in the bundle. The raw name is used only if it doesn't contain
+// path separators and no variant is `.` or `..`, so that the name cannot
+// point outside of the logs directory.
+func fileNameVariants(name string) []string {
+	var variants []string
+	add := func(v string) {
+		if v == "." || v == ".." {
+			return
+		}
+		for _, existing := range variants {
+			if existing == v {
+				return
+			}
+		}
+		variants = append(variants, v)
+	}
+
+	if !strings.ContainsAny(name, `/\`) {
+		add(name)
+	}
+	add(url.PathEscape(name))
+	add(sanitizeFileName(name))
+	return variants
+}
+
+// sanitizeFileName replaces characters that are not safe in file names.
+func sanitizeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '-'
+		}
+		return r
+	}, name)
+}
+
+// findLogsFile returns the first existing logs file for the paths without
+// extension, see bundle.FindFile. Empty path is returned when none of
+// the files exists.
+func findLogsFile(b bundle.Bundle, paths ...string) (string, error) {
+	for _, path := range paths {
+		found, err := bundle.FindFile(b, path, logsExt)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		return found, err
+	}
+	return "", nil
+}
@@ -0,0 +1,25 @@
+package proxy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+func TestFileNameVariants(t *testing.T) {
+	assert.Equal(t, []string{"app"}, fileNameVariants("app"))
+	assert.Equal(t, []string{"sidecar v2", "sidecar%20v2"}, fileNameVariants("sidecar v2"))
+	assert.Equal(t, []string{"init%2Fstep", "init-step"}, fileNameVariants("init/step"))
+	assert.Empty(t, fileNameVariants("."))
+	assert.Empty(t, fileNameVariants(".."))
+}
+
+func TestPodLogsCandidatePaths_DotNames(t *testing.T) {
+	b := bundle.FromFs(newMemFs(t, nil))
+	for _, name := range []string{".", ".."} {
+		assert.Empty(t, podLogsCandidatePaths(b, "default", name, "app", false), "pod %q", name)
+		assert.Empty(t, podLogsCandidatePaths(b, "default", "test", name, false), "container %q", name)
+	}
+}
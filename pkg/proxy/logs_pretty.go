@@ -0,0 +1,101 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// prettyLogLeadingKeys are keys of structured logs printed first, in this
+// order, followed by the remaining keys sorted by name.
+func prettyLogLeadingKeys() []string {
+	return []string{"time", "ts", "timestamp", "level", "lvl", "severity", "msg", "message"}
+}
+
+// prettyJSONLines reformats lines with a JSON object, optionally prefixed by
+// a RFC3339 timestamp, to the `key=value` form, e.g.
+// `{"level":"info","msg":"started","port":8080}` is printed as
+// `level=info msg=started port=8080`. Each line stays a single line so that
+// tail and line numbers are not affected. Other lines are not changed.
+func prettyJSONLines(data []byte) []byte {
+	lines := splitLogLines(data)
+	for i, line := range lines {
+		prefix, rest := []byte{}, line
+		if timestamp, after, ok := bytes.Cut(line, []byte(" ")); ok {
+			if _, err := time.Parse(time.RFC3339Nano, string(timestamp)); err == nil {
+				prefix, rest = line[:len(timestamp)+1], after
+			}
+		}
+		if formatted, ok := formatJSONLogLine(rest); ok {
+			lines[i] = append(append([]byte{}, prefix...), formatted...)
+		}
+	}
+	if len(lines) == 0 {
+		return data
+	}
+	return keepTrailingNewline(joinLogLines(lines), data)
+}
+
+func formatJSONLogLine(line []byte) ([]byte, bool) {
+	trimmed := bytes.TrimSpace(line)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return nil, false
+	}
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	decoder.UseNumber()
+	fields := map[string]any{}
+	if err := decoder.Decode(&fields); err != nil || decoder.More() {
+		return nil, false
+	}
+
+	keys := make([]string, 0, len(fields))
+	leading := map[string]bool{}
+	for _, key := range prettyLogLeadingKeys() {
+		leading[key] = true
+		if _, ok := fields[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	rest := make([]string, 0, len(fields))
+	for key := range fields {
+		if !leading[key] {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	keys = append(keys, rest...)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+formatLogValue(fields[key]))
+	}
+	return []byte(strings.Join(pairs, " ")), true
+}
+
+// formatLogValue formats the value in the logfmt style. Strings with spaces,
+// quotes or `=` are quoted, nested objects and arrays are printed as JSON.
+func formatLogValue(value any) string {
+	switch value := value.(type) {
+	case string:
+		if value == "" || strings.ContainsAny(value, " \t\"=\n") {
+			return strconv.Quote(value)
+		}
+		return value
+	case json.Number:
+		return value.String()
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(value)
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprint(value)
+		}
+		return string(data)
+	}
+}
@@ -0,0 +1,31 @@
+package proxy
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+func TestLogsHandler_Pretty(t *testing.T) {
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		"pod-logs/default/test-app.log": []byte(`starting server
+{"ts":"2024-01-01T10:00:00Z","msg":"listening on :8080","level":"info","port":8080,"tls":false}
+2024-01-01T10:00:01Z {"level":"error","msg":"request failed","error":"timeout","request":{"path":"/","id":7}}
+{"not closed":
+`),
+	}))
+
+	w := serveLogs(t, b, "container=app&pretty=true")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `starting server
+ts=2024-01-01T10:00:00Z level=info msg="listening on :8080" port=8080 tls=false
+2024-01-01T10:00:01Z level=error msg="request failed" error=timeout request={"id":7,"path":"/"}
+{"not closed":
+`, w.Body.String())
+
+	w = serveLogs(t, b, "container=app&pretty=true&tailLines=1&grep=listening&lineNumbers=true")
+	assert.Equal(t, "2 ts=2024-01-01T10:00:00Z level=info msg=\"listening on :8080\" port=8080 tls=false\n", w.Body.String())
+}
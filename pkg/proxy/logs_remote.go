@@ -0,0 +1,65 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// fetchRemoteLogs downloads logs from the remote log backend. The timeout is
+// applied when the context has no deadline, so that a stalled backend doesn't
+// block the request forever.
+func fetchRemoteLogs(ctx context.Context, url string, timeout time.Duration) ([]byte, error) {
+	if _, ok := ctx.Deadline(); !ok && timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("remote log backend returned %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// serveRemoteLogs serves logs of pods without logs in the bundle from
+// the remote log backend configured in the layout. Failures of the backend
+// are reported as 502.
+func serveRemoteLogs(
+	ctx context.Context,
+	w http.ResponseWriter,
+	r *http.Request,
+	url string,
+	query *logsQuery,
+	options *logsOptions,
+	l *slog.Logger,
+) {
+	l = l.With("url", r.URL, "logs source", url)
+
+	data, err := fetchRemoteLogs(ctx, url, options.remoteTimeout)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		http.Error(w, "timed out reading pod logs from the remote log backend", http.StatusGatewayTimeout)
+		return
+	case err != nil:
+		l.Warn("failed to fetch remote logs", "err", err)
+		http.Error(w, fmt.Sprintf("failed to fetch pod logs from the remote log backend: %s", err), http.StatusBadGateway)
+		return
+	}
+
+	servePodLogs(w, data, query, options, l)
+}
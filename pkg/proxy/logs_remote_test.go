@@ -0,0 +1,93 @@
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+func remoteLogsBundle(t *testing.T, template string, files map[string][]byte) bundle.Bundle {
+	t.Helper()
+
+	files[".troubleshoot-live/config.yaml"] = []byte(fmt.Sprintf("remoteLogURLTemplate: %q\n", template))
+	fs := newMemFs(t, files)
+	layout, err := bundle.LoadLayoutWithFallback(fs)
+	require.NoError(t, err)
+	return bundle.FromFsWithLayout(fs, layout)
+}
+
+func TestLogsHandler_RemoteLogs(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		if r.URL.Path == "/logs/default/test/broken" {
+			http.Error(w, "backend failure", http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, "remote 1\nremote 2\n")
+	}))
+	defer server.Close()
+
+	b := remoteLogsBundle(t, server.URL+"/logs/{namespace}/{pod}/{container}", map[string][]byte{
+		"pod-logs/default/test-local.log": []byte("local"),
+	})
+
+	w := serveLogs(t, b, "container=app&tailLines=1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "remote 2\n", w.Body.String())
+
+	w = serveLogs(t, b, "container=local")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "local", w.Body.String(), "logs from the bundle take precedence")
+
+	w = serveLogs(t, b, "container=broken")
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	assert.Contains(t, w.Body.String(), "500 Internal Server Error")
+
+	assert.Equal(t, []string{"/logs/default/test/app", "/logs/default/test/broken"}, requested)
+}
+
+func TestLogsHandler_RemoteLogsTimeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	b := remoteLogsBundle(t, server.URL+"/{pod}", map[string][]byte{})
+
+	w := serveLogs(t, b, "container=app", WithLogsReadTimeout(50*time.Millisecond))
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+}
+
+func TestLogsHandler_RemoteLogsDefaultTimeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	b := remoteLogsBundle(t, server.URL+"/{pod}", map[string][]byte{})
+
+	w := serveLogs(t, b, "container=app", WithLogsRemoteTimeout(50*time.Millisecond))
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code, "stalled backend times out without read timeout")
+}
+
+func TestLogsHandler_RemoteLogsUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	b := remoteLogsBundle(t, server.URL+"/{pod}", map[string][]byte{})
+
+	w := serveLogs(t, b, "container=app")
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+}
@@ -0,0 +1,166 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+const (
+	stdoutLogsSuffix = "-stdout"
+	stderrLogsSuffix = "-stderr"
+)
+
+var errPodLogsNotFound = errors.New("pod logs not found in the bundle")
+
+// podLogsFiles are files from which are logs of a container served. Logs are
+// stored either in a single file or split to stdout and stderr files.
+type podLogsFiles struct {
+	combined string
+	stdout   string
+	stderr   string
+}
+
+// paths returns all existing files.
+func (f *podLogsFiles) paths() []string {
+	var paths []string
+	for _, path := range []string{f.combined, f.stdout, f.stderr} {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// resolvePodLogsFiles returns the first existing candidate path. When none of
+// the paths exists, logs split by collectors to `<name>-stdout.log` and
+// `<name>-stderr.log` files are looked up and then logs copied from
+// the kubelet pod logs directories. With previous the logs of the previous
+// container instance are returned.
+func resolvePodLogsFiles(
+	b bundle.Bundle, l *slog.Logger, namespace, pod, container string, previous bool, maxGlobMatches int,
+) (*podLogsFiles, error) {
+	candidates := podLogsCandidatePaths(b, namespace, pod, container, previous)
+	path, err := findLogsFile(b, candidates...)
+	if err != nil || path != "" {
+		return &podLogsFiles{combined: path}, err
+	}
+
+	for _, candidate := range candidates {
+		files := &podLogsFiles{}
+		if files.stdout, err = findLogsFile(b, candidate+stdoutLogsSuffix); err != nil {
+			return nil, err
+		}
+		if files.stderr, err = findLogsFile(b, candidate+stderrLogsSuffix); err != nil {
+			return nil, err
+		}
+		if len(files.paths()) > 0 {
+			return files, nil
+		}
+	}
+
+	path, err = findKubeletPodLogs(b, l, namespace, pod, container, previous, maxGlobMatches)
+	if err != nil {
+		return nil, err
+	}
+	if path != "" {
+		return &podLogsFiles{combined: path}, nil
+	}
+
+	return nil, errPodLogsNotFound
+}
+
+// readPodLogs reads the logs, split logs are merged.
+func readPodLogs(ctx context.Context, b bundle.Bundle, files *podLogsFiles) ([]byte, error) {
+	if files.combined != "" {
+		return bundle.ReadFileContext(ctx, b, files.combined)
+	}
+
+	stdout, err := readOptionalFile(ctx, b, files.stdout)
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := readOptionalFile(ctx, b, files.stderr)
+	if err != nil {
+		return nil, err
+	}
+	return mergeSplitLogs(stdout, stderr), nil
+}
+
+// readOptionalFile returns nil data if the path is empty.
+func readOptionalFile(ctx context.Context, fs afero.Fs, path string) ([]byte, error) {
+	if path == "" {
+		return nil, nil
+	}
+	return bundle.ReadFileContext(ctx, fs, path)
+}
+
+// mergeSplitLogs merges stdout and stderr logs. Lines are interleaved by
+// timestamp when every line starts with one, otherwise stderr follows stdout.
+func mergeSplitLogs(stdout, stderr []byte) []byte {
+	stdoutLines, stderrLines := splitLogLines(stdout), splitLogLines(stderr)
+	if len(stdoutLines) == 0 || len(stderrLines) == 0 {
+		return append(append([]byte{}, stdout...), stderr...)
+	}
+
+	stdoutTimes, ok := logLinesTimestamps(stdoutLines)
+	if !ok {
+		return joinLogLines(stdoutLines, stderrLines)
+	}
+	stderrTimes, ok := logLinesTimestamps(stderrLines)
+	if !ok {
+		return joinLogLines(stdoutLines, stderrLines)
+	}
+
+	merged := make([][]byte, 0, len(stdoutLines)+len(stderrLines))
+	i, j := 0, 0
+	for i < len(stdoutLines) && j < len(stderrLines) {
+		if !stderrTimes[j].Before(stdoutTimes[i]) {
+			merged = append(merged, stdoutLines[i])
+			i++
+		} else {
+			merged = append(merged, stderrLines[j])
+			j++
+		}
+	}
+	merged = append(merged, stdoutLines[i:]...)
+	merged = append(merged, stderrLines[j:]...)
+	return joinLogLines(merged)
+}
+
+// splitLogLines splits logs to lines without the trailing new line.
+func splitLogLines(data []byte) [][]byte {
+	data = bytes.TrimSuffix(data, []byte("\n"))
+	if len(data) == 0 {
+		return nil
+	}
+	return bytes.Split(data, []byte("\n"))
+}
+
+func joinLogLines(lines ...[][]byte) []byte {
+	var all [][]byte
+	for _, l := range lines {
+		all = append(all, l...)
+	}
+	return append(bytes.Join(all, []byte("\n")), '\n')
+}
+
+// logLinesTimestamps parses RFC3339 timestamp prefix of each line.
+func logLinesTimestamps(lines [][]byte) ([]time.Time, bool) {
+	timestamps := make([]time.Time, 0, len(lines))
+	for _, line := range lines {
+		prefix, _, _ := bytes.Cut(line, []byte(" "))
+		t, err := time.Parse(time.RFC3339Nano, string(prefix))
+		if err != nil {
+			return nil, false
+		}
+		timestamps = append(timestamps, t)
+	}
+	return timestamps, true
+}
@@ -0,0 +1,338 @@
+package proxy
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/gorilla/mux"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+func newMemFs(t *testing.T, files map[string][]byte) afero.Fs {
+	t.Helper()
+
+	fs := afero.NewMemMapFs()
+	for path, data := range files {
+		require.NoError(t, afero.WriteReader(fs, path, bytes.NewReader(data)))
+	}
+	return fs
+}
+
+func serveLogs(t *testing.T, b bundle.Bundle, query string, opts ...LogsOption) *httptest.ResponseRecorder {
+	t.Helper()
+
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/namespaces/default/pods/test/log?"+query, http.NoBody)
+	r = mux.SetURLVars(r, map[string]string{"namespace": "default", "pod": "test"})
+	w := httptest.NewRecorder()
+	LogsHandler(b, slog.Default(), opts...)(w, r)
+	return w
+}
+
+func utf16LEWithBOM(s string) []byte {
+	data := []byte(bomUTF16LE)
+	for _, u := range utf16.Encode([]rune(s)) {
+		data = binary.LittleEndian.AppendUint16(data, u)
+	}
+	return data
+}
+
+func gzipBytes(t *testing.T, s string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write([]byte(s))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestLogsHandler_UTF16BOM(t *testing.T) {
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		"pod-logs/default/test-app.log": utf16LEWithBOM("line 1 ✓\nline 2"),
+	}))
+
+	w := serveLogs(t, b, "container=app")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "line 1 ✓\nline 2", w.Body.String())
+}
+
+func TestLogsHandler_Latin1(t *testing.T) {
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		"pod-logs/default/test-app.log": {'c', 'a', 'f', 0xE9},
+	}))
+
+	w := serveLogs(t, b, "container=app")
+	assert.Equal(t, []byte{'c', 'a', 'f', 0xE9}, w.Body.Bytes(), "no transcoding without BOM by default")
+
+	w = serveLogs(t, b, "container=app", WithLogsEncoding(EncodingLatin1))
+	assert.Equal(t, "café", w.Body.String())
+}
+
+func TestLogsHandler_NamespaceMapping(t *testing.T) {
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		"pod-logs/original/test-app.log": []byte("logs"),
+	}))
+
+	w := serveLogs(t, b, "container=app", WithLogsNamespaceMapping(map[string]string{"original": "default"}))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "logs", w.Body.String())
+}
+
+func TestLogsHandler_MaxLineLength(t *testing.T) {
+	longLine := strings.Repeat("x", 1<<20)
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		"pod-logs/default/test-app.log": []byte("short\n" + longLine + "\nčččč"),
+	}))
+
+	w := serveLogs(t, b, "container=app")
+	assert.Equal(t, "short\n"+longLine+"\nčččč", w.Body.String(), "lines are not truncated by default")
+
+	w = serveLogs(t, b, "container=app", WithLogsMaxLineLength(5))
+	expected := "short\nxxxxx… [truncated 1048571 bytes]\nčč… [truncated 4 bytes]"
+	assert.Equal(t, expected, w.Body.String())
+}
+
+func TestLogsHandler_TailLinesWithLineNumbers(t *testing.T) {
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		"pod-logs/default/test-app.log": []byte("one\ntwo\nthree\nfour\nfive\n"),
+	}))
+
+	w := serveLogs(t, b, "container=app&tailLines=2")
+	assert.Equal(t, "four\nfive\n", w.Body.String())
+
+	w = serveLogs(t, b, "container=app&tailLines=2&lineNumbers=true")
+	assert.Equal(t, "4 four\n5 five\n", w.Body.String(), "line numbers are absolute")
+
+	w = serveLogs(t, b, "container=app&lineNumbers=true")
+	assert.Equal(t, "1 one\n2 two\n3 three\n4 four\n5 five\n", w.Body.String())
+
+	w = serveLogs(t, b, "container=app&tailLines=10&lineNumbers=true")
+	assert.Equal(t, "1 one\n2 two\n3 three\n4 four\n5 five\n", w.Body.String())
+
+	w = serveLogs(t, b, "container=app&tailLines=-1")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLogsHandler_Grep(t *testing.T) {
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		"pod-logs/default/test-app.log": []byte("info start\nerror db\ninfo ready\nerror timeout\ndebug tick\n"),
+	}))
+
+	w := serveLogs(t, b, "container=app&grep=^error")
+	assert.Equal(t, "error db\nerror timeout\n", w.Body.String())
+
+	w = serveLogs(t, b, "container=app&grepv=^(info|debug)")
+	assert.Equal(t, "error db\nerror timeout\n", w.Body.String())
+
+	w = serveLogs(t, b, "container=app&grepv=error")
+	assert.Equal(t, "info start\ninfo ready\ndebug tick\n", w.Body.String())
+
+	w = serveLogs(t, b, "container=app&grep=info|error&grepv=ready&tailLines=2&lineNumbers=true")
+	assert.Equal(t, "2 error db\n4 error timeout\n", w.Body.String(), "tail of filtered lines with original numbers")
+
+	w = serveLogs(t, b, "container=app&grep=warning")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	w = serveLogs(t, b, "container=app&grep=(")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLogsHandler_ContentType(t *testing.T) {
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		"pod-logs/default/test-app.log": []byte(`{"log":"hello\n","stream":"stdout","time":"2024-01-01T00:00:00Z"}`),
+	}))
+
+	w := serveLogs(t, b, "container=app")
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+
+	w = serveLogs(t, b, "container=app", WithLogsContentType("text/x-log"))
+	assert.Equal(t, "text/x-log", w.Header().Get("Content-Type"))
+}
+
+func TestLogsHandler_SpecialCharacters(t *testing.T) {
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		"pod-logs/default/test-sidecar%20v2.log":                   []byte("escaped"),
+		"cluster-resources/pods/logs/default/test/init-step-1.log": []byte("sanitized"),
+		"pod-logs/default/secret.log":                              []byte("secret"),
+	}))
+
+	w := serveLogs(t, b, "container="+url.QueryEscape("sidecar v2"))
+	assert.Equal(t, "escaped", w.Body.String())
+
+	w = serveLogs(t, b, "container="+url.QueryEscape("init/step-1"))
+	assert.Equal(t, "sanitized", w.Body.String())
+
+	w = serveLogs(t, b, "container="+url.QueryEscape("../secret"))
+	assert.NotEqual(t, "secret", w.Body.String())
+}
+
+func TestLogsHandler_SplitStdoutStderr(t *testing.T) {
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		"pod-logs/default/test-app-stdout.log": []byte(
+			"2024-01-01T10:00:00.000000Z starting\n2024-01-01T10:00:02.000000Z ready\n"),
+		"pod-logs/default/test-app-stderr.log": []byte(
+			"2024-01-01T10:00:01.000000Z warning: deprecated flag\n2024-01-01T10:00:03.000000Z error: lost connection\n"),
+		"pod-logs/default/test-plain-stdout.log": []byte("out 1\nout 2\n"),
+		"pod-logs/default/test-plain-stderr.log": []byte("err 1\n"),
+		"pod-logs/default/test-only-stderr.log":  []byte("err only\n"),
+	}))
+
+	w := serveLogs(t, b, "container=app")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, strings.Join([]string{
+		"2024-01-01T10:00:00.000000Z starting",
+		"2024-01-01T10:00:01.000000Z warning: deprecated flag",
+		"2024-01-01T10:00:02.000000Z ready",
+		"2024-01-01T10:00:03.000000Z error: lost connection",
+		"",
+	}, "\n"), w.Body.String())
+
+	w = serveLogs(t, b, "container=plain")
+	assert.Equal(t, "out 1\nout 2\nerr 1\n", w.Body.String(), "stderr follows stdout without timestamps")
+
+	w = serveLogs(t, b, "container=only")
+	assert.Equal(t, "err only\n", w.Body.String())
+}
+
+func TestLogsHandler_Compressed(t *testing.T) {
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		"pod-logs/default/test-app.log.gz":          gzipBytes(t, "line 1\nline 2"),
+		"pod-logs/default/test-split-stdout.log.gz": gzipBytes(t, "out 1\n"),
+		"pod-logs/default/test-split-stderr.log":    []byte("err 1\n"),
+		"pod-logs/default/test-both.log":            []byte("plain"),
+		"pod-logs/default/test-both.log.gz":         gzipBytes(t, "compressed"),
+		"pod-logs/default/test-broken.log.gz":       []byte("not gzip"),
+	}))
+
+	w := serveLogs(t, b, "container=app")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "line 1\nline 2", w.Body.String())
+
+	w = serveLogs(t, b, "container=app&timestamps=true")
+	assert.Equal(t, "1970-01-01T00:00:00Z line 1\n1970-01-01T00:00:00Z line 2", w.Body.String())
+
+	w = serveLogs(t, b, "container=split")
+	assert.Equal(t, "out 1\nerr 1\n", w.Body.String())
+
+	w = serveLogs(t, b, "container=both")
+	assert.Equal(t, "plain", w.Body.String(), "uncompressed logs take precedence")
+
+	w = serveLogs(t, b, "container=broken")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestLogsHandler_Chunked(t *testing.T) {
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		"pod-logs/default/test-app.log.part1":          []byte("line 1\n"),
+		"pod-logs/default/test-app.log.part2":          []byte("line 2"),
+		"pod-logs/default/test-split-stdout.log.part1": []byte("out 1\n"),
+	}))
+
+	w := serveLogs(t, b, "container=app")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "line 1\nline 2", w.Body.String())
+
+	w = serveLogs(t, b, "container=split")
+	assert.Equal(t, "out 1\n", w.Body.String())
+}
+
+func TestLogsHandler_CompressedCached(t *testing.T) {
+	cache := bundle.NewDecompressedCacheFs(newMemFs(t, map[string][]byte{
+		"pod-logs/default/test-app.log.gz": gzipBytes(t, "line 1\nline 2"),
+	}), 1024)
+	b := bundle.FromFs(cache)
+
+	w := serveLogs(t, b, "container=app")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "line 1\nline 2", w.Body.String())
+	assert.Equal(t, int64(len("line 1\nline 2")), cache.Size(), "decompressed logs are cached")
+}
+
+func TestLogsHandler_KubeletPodLogs(t *testing.T) {
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		// Completed job pod that is not present in cluster resources.
+		"host-logs/var/log/pods/default_test_7f3c9a2e-1b2d-4c5e-9f00-aa11bb22cc33/app/0.log":   []byte("attempt 0"),
+		"host-logs/var/log/pods/default_test_7f3c9a2e-1b2d-4c5e-9f00-aa11bb22cc33/app/1.log":   []byte("attempt 1"),
+		"host-logs/var/log/pods/default_test_7f3c9a2e-1b2d-4c5e-9f00-aa11bb22cc33/app/10.log":  []byte("attempt 10"),
+		"host-logs/var/log/pods/default_test-2_0a0a0a0a-0000-0000-0000-000000000000/app/0.log": []byte("other pod"),
+	}))
+
+	w := serveLogs(t, b, "container=app")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "attempt 10", w.Body.String())
+
+	w = serveLogs(t, b, "container=app&previous=true")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "attempt 1", w.Body.String())
+
+	w = serveLogs(t, b, "container=sidecar")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// openCountingFs counts directories and files opened by the handler.
+type openCountingFs struct {
+	afero.Fs
+	opened int
+}
+
+func (fs *openCountingFs) Open(name string) (afero.File, error) {
+	fs.opened++
+	return fs.Fs.Open(name)
+}
+
+func TestLogsHandler_KubeletPodLogsDecoyDirs(t *testing.T) {
+	files := map[string][]byte{
+		"host-logs/var/log/pods/default_test_zzz/app/0.log": []byte("found"),
+	}
+	for i := 0; i < 200; i++ {
+		files[fmt.Sprintf("host-logs/var/log/journal/decoy-%03d/system.journal", i)] = []byte("decoy")
+		files[fmt.Sprintf("other/a/b/c/decoy-%03d/default_test_decoy/app/0.log", i)] = []byte("decoy")
+	}
+	fs := &openCountingFs{Fs: newMemFs(t, files)}
+	b := bundle.FromFs(fs)
+
+	w := serveLogs(t, b, "container=app")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "found", w.Body.String())
+	assert.Less(t, fs.opened, 50, "lookup visits only the kubelet pod logs roots")
+
+	fs.opened = 0
+	w = serveLogs(t, b, "container=missing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Less(t, fs.opened, 50, "lookup visits only the kubelet pod logs roots")
+}
+
+func TestLogsHandler_KubeletPodLogsMaxGlobMatches(t *testing.T) {
+	files := map[string][]byte{
+		"host-logs/var/log/pods/default_test_zzz/app/0.log": []byte("found"),
+	}
+	for i := 0; i < 10; i++ {
+		files[fmt.Sprintf("host-logs/var/log/pods/default_test_decoy-%02d/other/0.log", i)] = []byte("decoy")
+	}
+	b := bundle.FromFs(newMemFs(t, files))
+
+	w := serveLogs(t, b, "container=app", WithLogsMaxGlobMatches(5))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serveLogs(t, b, "container=app", WithLogsMaxGlobMatches(20))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "found", w.Body.String())
+
+	w = serveLogs(t, b, "container=app", WithLogsMaxGlobMatches(0))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,227 @@
+package proxy
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	apidiscoveryv2beta1 "k8s.io/api/apidiscovery/v2beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+// MetricsPath is the path of the resource metrics API used by `kubectl top`.
+const MetricsPath = "/apis/" + bundle.MetricsGroupVersion
+
+// metricsResources are resources of the metrics API served from the bundle.
+func metricsResources() []metav1.APIResource {
+	return []metav1.APIResource{
+		{Name: "nodes", Kind: "NodeMetrics", Verbs: metav1.Verbs{"get", "list"}},
+		{Name: "pods", Kind: "PodMetrics", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}},
+	}
+}
+
+// MetricsHandler serves the `metrics.k8s.io` API from resource metrics
+// captured in the bundle, so that `kubectl top` returns usage at the time of
+// the collection. Empty lists are served when the metrics weren't collected.
+// The `labelSelector` query parameter is supported for lists. Pod metrics are
+// served in namespaces given by the namespace mapping.
+func MetricsHandler(b bundle.Bundle, l *slog.Logger, namespaces map[string]string) http.Handler {
+	nodes, pods := metricsResources()[0], metricsResources()[1]
+	mapping := namespaceMapping(namespaces)
+
+	r := mux.NewRouter()
+	r.Handle(MetricsPath, metricsResourceListHandler())
+	r.Handle(MetricsPath+"/nodes", metricsHandler(b, l, bundle.LoadNodeMetrics, nodes, mapping))
+	r.Handle(MetricsPath+"/nodes/{name}", metricsHandler(b, l, bundle.LoadNodeMetrics, nodes, mapping))
+	r.Handle(MetricsPath+"/pods", metricsHandler(b, l, bundle.LoadPodMetrics, pods, mapping))
+	r.Handle(MetricsPath+"/namespaces/{namespace}/pods", metricsHandler(b, l, bundle.LoadPodMetrics, pods, mapping))
+	r.Handle(MetricsPath+"/namespaces/{namespace}/pods/{name}", metricsHandler(b, l, bundle.LoadPodMetrics, pods, mapping))
+	return r
+}
+
+func metricsResourceListHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, &metav1.APIResourceList{
+			TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+			GroupVersion: bundle.MetricsGroupVersion,
+			APIResources: metricsResources(),
+		})
+	}
+}
+
+// metricsHandler serves a list of metrics or a single item, when the request
+// path contains its name.
+func metricsHandler(
+	b bundle.Bundle,
+	l *slog.Logger,
+	load func(bundle.Bundle) (*unstructured.UnstructuredList, error),
+	resource metav1.APIResource,
+	mapping namespaceMapping,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		selector, err := labels.Parse(r.URL.Query().Get("labelSelector"))
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, apierrors.NewBadRequest(err.Error()).Status())
+			return
+		}
+
+		list, err := load(b)
+		if err != nil {
+			l.Error("failed to load metrics", "url", r.URL, "err", err)
+			writeJSON(w, http.StatusInternalServerError, apierrors.NewInternalError(err).Status())
+			return
+		}
+
+		result := &unstructured.UnstructuredList{Items: []unstructured.Unstructured{}}
+		result.SetAPIVersion(bundle.MetricsGroupVersion)
+		result.SetKind(resource.Kind + "List")
+		for i := range list.Items {
+			item := &list.Items[i]
+			if namespace, ok := vars["namespace"]; ok && mapping.servedNamespace(item.GetNamespace()) != namespace {
+				continue
+			}
+			if resource.Namespaced {
+				item = item.DeepCopy()
+				item.SetNamespace(mapping.servedNamespace(item.GetNamespace()))
+			}
+			if name, ok := vars["name"]; ok && item.GetName() == name {
+				writeJSON(w, http.StatusOK, item)
+				return
+			}
+			if selector.Matches(labels.Set(item.GetLabels())) {
+				result.Items = append(result.Items, *item)
+			}
+		}
+
+		if name, ok := vars["name"]; ok {
+			group, _, _ := strings.Cut(bundle.MetricsGroupVersion, "/")
+			err := apierrors.NewNotFound(schema.GroupResource{Group: group, Resource: resource.Name}, name)
+			writeJSON(w, http.StatusNotFound, err.Status())
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
+// addMetricsDiscovery adds the metrics API group to the `/apis` discovery
+// responses of the API server. It is used when the bundle contains captured
+// metrics, see hasCapturedMetrics. `kubectl top` checks the discovery before
+// requesting metrics. Both the legacy APIGroupList and the aggregated
+// discovery in JSON are supported.
+func addMetricsDiscovery(r *http.Response) error {
+	if r.StatusCode != http.StatusOK || r.Request == nil || r.Request.URL.Path != "/apis" ||
+		!strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		return nil
+	}
+
+	data, err := readResponseBody(r)
+	if err != nil {
+		return err
+	}
+
+	content := map[string]any{}
+	if err := json.Unmarshal(data, &content); err == nil {
+		if err := appendMetricsGroup(content); err == nil {
+			if modified, err := json.Marshal(content); err == nil {
+				data = modified
+				r.Header.Del("ETag")
+			}
+		}
+	}
+	return writeResponseBody(r, data)
+}
+
+// hasCapturedMetrics checks whether the bundle contains node or pod metrics.
+func hasCapturedMetrics(b bundle.Bundle) bool {
+	nodes, err := bundle.LoadNodeMetrics(b)
+	if err == nil && len(nodes.Items) > 0 {
+		return true
+	}
+	pods, err := bundle.LoadPodMetrics(b)
+	return err == nil && len(pods.Items) > 0
+}
+
+// appendMetricsGroup adds the metrics API group to the discovery content
+// unless it is already present.
+func appendMetricsGroup(content map[string]any) error {
+	group, version, _ := strings.Cut(bundle.MetricsGroupVersion, "/")
+
+	var entry any
+	var key string
+	var nameFields []string
+	switch content["kind"] {
+	case "APIGroupList":
+		key, nameFields = "groups", []string{"name"}
+		groupVersion := metav1.GroupVersionForDiscovery{GroupVersion: bundle.MetricsGroupVersion, Version: version}
+		entry = &metav1.APIGroup{
+			Name:             group,
+			Versions:         []metav1.GroupVersionForDiscovery{groupVersion},
+			PreferredVersion: groupVersion,
+		}
+	case "APIGroupDiscoveryList":
+		key, nameFields = "items", []string{"metadata", "name"}
+		entry = metricsGroupDiscovery(group, version)
+	default:
+		return nil
+	}
+
+	groups, _, _ := unstructured.NestedSlice(content, key)
+	for _, existing := range groups {
+		if g, ok := existing.(map[string]any); ok {
+			if name, _, _ := unstructured.NestedString(g, nameFields...); name == group {
+				return nil
+			}
+		}
+	}
+
+	converted, err := runtime.DefaultUnstructuredConverter.ToUnstructured(entry)
+	if err != nil {
+		return err
+	}
+	content[key] = append(groups, converted)
+	return nil
+}
+
+func metricsGroupDiscovery(group, version string) *apidiscoveryv2beta1.APIGroupDiscovery {
+	discovery := apidiscoveryv2beta1.APIVersionDiscovery{
+		Version:   version,
+		Freshness: apidiscoveryv2beta1.DiscoveryFreshnessCurrent,
+	}
+	for _, resource := range metricsResources() {
+		scope := apidiscoveryv2beta1.ScopeCluster
+		if resource.Namespaced {
+			scope = apidiscoveryv2beta1.ScopeNamespace
+		}
+		discovery.Resources = append(discovery.Resources, apidiscoveryv2beta1.APIResourceDiscovery{
+			Resource:     resource.Name,
+			ResponseKind: &metav1.GroupVersionKind{Group: group, Version: version, Kind: resource.Kind},
+			Scope:        scope,
+			Verbs:        resource.Verbs,
+		})
+	}
+	return &apidiscoveryv2beta1.APIGroupDiscovery{
+		ObjectMeta: metav1.ObjectMeta{Name: group},
+		Versions:   []apidiscoveryv2beta1.APIVersionDiscovery{discovery},
+	}
+}
@@ -0,0 +1,149 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+func newMetricsTestBundle(t *testing.T) bundle.Bundle {
+	t.Helper()
+	return bundle.FromFs(newMemFs(t, map[string][]byte{
+		"metrics/top-nodes.txt": []byte("NAME CPU(cores) CPU% MEMORY(bytes) MEMORY%\nworker-1 250m 12% 1Gi 26%\n"),
+		"metrics/top-pods.txt": []byte(`NAMESPACE     NAME      CPU(cores)   MEMORY(bytes)
+kube-system   coredns   3m           15Mi
+default       web       10m          64Mi
+`),
+	}))
+}
+
+func TestMetricsHandler(t *testing.T) {
+	h := MetricsHandler(newMetricsTestBundle(t), slog.Default(), nil)
+	serve := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, http.NoBody))
+		return w
+	}
+
+	w := serve(MetricsPath + "/namespaces/default/pods")
+	require.Equal(t, http.StatusOK, w.Code)
+	list := &unstructured.UnstructuredList{}
+	require.NoError(t, list.UnmarshalJSON(w.Body.Bytes()))
+	assert.Equal(t, "PodMetricsList", list.GetKind())
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "web", list.Items[0].GetName())
+
+	w = serve(MetricsPath + "/pods")
+	require.NoError(t, list.UnmarshalJSON(w.Body.Bytes()))
+	assert.Len(t, list.Items, 2)
+
+	w = serve(MetricsPath + "/nodes/worker-1")
+	require.Equal(t, http.StatusOK, w.Code)
+	node := &unstructured.Unstructured{}
+	require.NoError(t, node.UnmarshalJSON(w.Body.Bytes()))
+	assert.Equal(t, "NodeMetrics", node.GetKind())
+
+	w = serve(MetricsPath + "/nodes/missing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serve(MetricsPath)
+	resources := &metav1.APIResourceList{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), resources))
+	assert.Equal(t, bundle.MetricsGroupVersion, resources.GroupVersion)
+}
+
+func TestMetricsHandler_NamespaceMapping(t *testing.T) {
+	h := MetricsHandler(newMetricsTestBundle(t), slog.Default(), map[string]string{"default": "tenant-a"})
+	list := &unstructured.UnstructuredList{}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, MetricsPath+"/namespaces/tenant-a/pods", http.NoBody))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, list.UnmarshalJSON(w.Body.Bytes()))
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "web", list.Items[0].GetName())
+	assert.Equal(t, "tenant-a", list.Items[0].GetNamespace())
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, MetricsPath+"/namespaces/tenant-a/pods/web", http.NoBody))
+	require.Equal(t, http.StatusOK, w.Code)
+	pod := &unstructured.Unstructured{}
+	require.NoError(t, pod.UnmarshalJSON(w.Body.Bytes()))
+	assert.Equal(t, "tenant-a", pod.GetNamespace())
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, MetricsPath+"/namespaces/default/pods", http.NoBody))
+	list = &unstructured.UnstructuredList{}
+	require.NoError(t, list.UnmarshalJSON(w.Body.Bytes()))
+	assert.Empty(t, list.Items, "remapped namespace is not served under the bundle namespace")
+}
+
+func TestMetricsHandler_NotCollected(t *testing.T) {
+	w := httptest.NewRecorder()
+	MetricsHandler(bundle.FromFs(newMemFs(t, nil)), slog.Default(), nil).
+		ServeHTTP(w, httptest.NewRequest(http.MethodGet, MetricsPath+"/nodes", http.NoBody))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"apiVersion": "metrics.k8s.io/v1beta1", "kind": "NodeMetricsList", "items": []}`,
+		w.Body.String())
+}
+
+func TestAddMetricsDiscovery(t *testing.T) {
+	tests := map[string]struct {
+		contentType string
+		body        string
+		key         string
+		nameFields  []string
+	}{
+		"legacy": {
+			contentType: "application/json",
+			body:        `{"kind": "APIGroupList", "apiVersion": "v1", "groups": [{"name": "apps"}]}`,
+			key:         "groups",
+			nameFields:  []string{"name"},
+		},
+		"aggregated": {
+			contentType: "application/json;g=apidiscovery.k8s.io;v=v2beta1;as=APIGroupDiscoveryList",
+			body:        `{"kind": "APIGroupDiscoveryList", "apiVersion": "apidiscovery.k8s.io/v2beta1", "items": [{"metadata": {"name": "apps"}}]}`,
+			key:         "items",
+			nameFields:  []string{"metadata", "name"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{tc.contentType}},
+				Body:       io.NopCloser(bytes.NewBufferString(tc.body)),
+				Request:    httptest.NewRequest(http.MethodGet, "/apis", http.NoBody),
+			}
+			require.NoError(t, addMetricsDiscovery(r))
+
+			data, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			content := map[string]any{}
+			require.NoError(t, json.Unmarshal(data, &content))
+
+			groups, _, _ := unstructured.NestedSlice(content, tc.key)
+			require.Len(t, groups, 2)
+			name, _, _ := unstructured.NestedString(groups[1].(map[string]any), tc.nameFields...)
+			assert.Equal(t, "metrics.k8s.io", name)
+		})
+	}
+}
+
+func TestHasCapturedMetrics(t *testing.T) {
+	assert.True(t, hasCapturedMetrics(newMetricsTestBundle(t)))
+	assert.False(t, hasCapturedMetrics(bundle.FromFs(newMemFs(t, nil))))
+}
@@ -0,0 +1,26 @@
+package proxy
+
+// namespaceMapping maps namespaces of the bundle to the namespaces the
+// resources were imported to, see rewriter.RemapNamespace. Handlers serving
+// data from the bundle look up the data in the original bundle namespace.
+type namespaceMapping map[string]string
+
+// bundleNamespace returns the namespace under which are resources stored in
+// the bundle for the namespace served by API server.
+func (m namespaceMapping) bundleNamespace(namespace string) string {
+	for original, remapped := range m {
+		if remapped == namespace {
+			return original
+		}
+	}
+	return namespace
+}
+
+// servedNamespace returns the namespace served by API server for resources
+// stored in the bundle namespace.
+func (m namespaceMapping) servedNamespace(namespace string) string {
+	if remapped, ok := m[namespace]; ok {
+		return remapped
+	}
+	return namespace
+}
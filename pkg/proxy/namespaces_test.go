@@ -0,0 +1,17 @@
+package proxy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespaceMapping(t *testing.T) {
+	m := namespaceMapping{"default": "tenant-a"}
+
+	assert.Equal(t, "default", m.bundleNamespace("tenant-a"))
+	assert.Equal(t, "kube-system", m.bundleNamespace("kube-system"))
+	assert.Equal(t, "tenant-a", m.servedNamespace("default"))
+	assert.Equal(t, "kube-system", m.servedNamespace("kube-system"))
+	assert.Equal(t, "default", namespaceMapping(nil).bundleNamespace("default"))
+}
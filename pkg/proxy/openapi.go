@@ -0,0 +1,58 @@
+package proxy
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+const (
+	// OpenAPIV3Path is the path of the OpenAPI v3 discovery.
+	OpenAPIV3Path = "/openapi/v3"
+
+	// collectedOpenAPIV3Dir is the bundle directory with OpenAPI v3 documents
+	// collected from the cluster. The discovery index is stored in the
+	// `index.json` file and documents of group versions as `<path>.json`,
+	// e.g. `openapi/v3/apis/apps/v1.json` for `/openapi/v3/apis/apps/v1`.
+	collectedOpenAPIV3Dir = "openapi/v3"
+	openAPIV3IndexFile    = "index.json"
+)
+
+// OpenAPIV3Handler serves OpenAPI v3 documents collected in the bundle so
+// that client side validation and `kubectl explain` use schemas of the
+// original cluster. When the bundle doesn't contain the discovery index the
+// requests are passed to the fallback handler serving documents of the local
+// API server.
+func OpenAPIV3Handler(b bundle.Bundle, fallback http.Handler, l *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		indexPath := filepath.Join(collectedOpenAPIV3Dir, openAPIV3IndexFile)
+		if ok, _ := afero.Exists(b, indexPath); !ok {
+			fallback.ServeHTTP(w, r)
+			return
+		}
+
+		// The `hash` query parameter is ignored, the bundle contains a single
+		// version of each document.
+		name := strings.TrimPrefix(path.Clean(r.URL.Path), OpenAPIV3Path)
+		filePath := indexPath
+		if name != "" && name != "/" {
+			filePath = filepath.Join(collectedOpenAPIV3Dir, filepath.FromSlash(strings.TrimPrefix(name, "/"))+".json")
+		}
+
+		data, err := afero.ReadFile(b, filePath)
+		if err != nil {
+			l.Debug("OpenAPI v3 document not collected", "url", r.URL, "err", err)
+			http.Error(w, fmt.Sprintf("OpenAPI v3 document %q was not collected in the bundle", r.URL.Path), http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	}
+}
@@ -0,0 +1,49 @@
+package proxy
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+const collectedOpenAPIV3Index = `{"paths": {
+  "api/v1": {"serverRelativeURL": "/openapi/v3/api/v1?hash=ABC"},
+  "apis/apps/v1": {"serverRelativeURL": "/openapi/v3/apis/apps/v1?hash=DEF"}
+}}`
+
+func TestOpenAPIV3Handler(t *testing.T) {
+	fallback := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("fallback"))
+	})
+	serve := func(b bundle.Bundle, url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		OpenAPIV3Handler(b, fallback, slog.Default())(w, httptest.NewRequest(http.MethodGet, url, http.NoBody))
+		return w
+	}
+
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		"openapi/v3/index.json":        []byte(collectedOpenAPIV3Index),
+		"openapi/v3/apis/apps/v1.json": []byte(`{"openapi": "3.0.0", "info": {"title": "Kubernetes", "version": "v1.28.3"}}`),
+	}))
+
+	w := serve(b, "/openapi/v3")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, collectedOpenAPIV3Index, w.Body.String())
+
+	w = serve(b, "/openapi/v3/apis/apps/v1?hash=DEF")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "v1.28.3")
+
+	w = serve(b, "/openapi/v3/api/v1?hash=ABC")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "was not collected in the bundle")
+
+	w = serve(bundle.FromFs(newMemFs(t, map[string][]byte{})), "/openapi/v3/apis/apps/v1")
+	assert.Equal(t, "fallback", w.Body.String())
+}
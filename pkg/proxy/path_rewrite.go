@@ -0,0 +1,58 @@
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// PathRewrite maps request paths matching the pattern to the replacement,
+// which can reference the pattern groups, e.g. `$1`.
+type PathRewrite struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// ParsePathRewrites parses rules in the `<pattern>=<replacement>` format,
+// e.g. `^/legacy/(.*)$=/api/v1/$1`. The pattern is split at the first `=`.
+func ParsePathRewrites(rules []string) ([]PathRewrite, error) {
+	rewrites := make([]PathRewrite, 0, len(rules))
+	for _, rule := range rules {
+		pattern, replacement, ok := strings.Cut(rule, "=")
+		if !ok || pattern == "" {
+			return nil, fmt.Errorf("path rewrite %q must be in format <pattern>=<replacement>", rule)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path rewrite pattern %q: %w", pattern, err)
+		}
+		rewrites = append(rewrites, PathRewrite{Pattern: re, Replacement: replacement})
+	}
+	return rewrites, nil
+}
+
+// RewritePaths rewrites request paths by the first matching rule before
+// the request is passed to the handler, so that clients requesting
+// nonstandard paths are routed to the proxy handlers. The handler is
+// returned unchanged when there are no rules.
+func RewritePaths(h http.Handler, rewrites []PathRewrite) http.Handler {
+	if len(rewrites) == 0 {
+		return h
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, rewrite := range rewrites {
+			if !rewrite.Pattern.MatchString(r.URL.Path) {
+				continue
+			}
+			r2 := r.Clone(r.Context())
+			r2.URL.Path = rewrite.Pattern.ReplaceAllString(r.URL.Path, rewrite.Replacement)
+			r2.URL.RawPath = ""
+			r2.RequestURI = r2.URL.RequestURI()
+			h.ServeHTTP(w, r2)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
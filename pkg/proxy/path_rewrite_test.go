@@ -0,0 +1,43 @@
+package proxy
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+func TestRewritePaths(t *testing.T) {
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		"pod-logs/default/test-app.log": []byte("line 1\n"),
+	}))
+	r := mux.NewRouter()
+	r.Handle("/api/v1/namespaces/{namespace}/pods/{pod}/log", LogsHandler(b, slog.Default()))
+
+	rewrites, err := ParsePathRewrites([]string{`^/api/v1/namespaces/([^/]+)/pods/([^/]+)/logs$=/api/v1/namespaces/$1/pods/$2/log`})
+	require.NoError(t, err)
+	h := RewritePaths(r, rewrites)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/namespaces/default/pods/test/logs?container=app", http.NoBody))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "line 1\n", w.Body.String())
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/namespaces/default/pods/test/log?container=app", http.NoBody))
+	assert.Equal(t, "line 1\n", w.Body.String(), "paths without matching rule are not changed")
+}
+
+func TestParsePathRewrites_Invalid(t *testing.T) {
+	_, err := ParsePathRewrites([]string{"/no-replacement"})
+	assert.Error(t, err)
+
+	_, err = ParsePathRewrites([]string{"(=/x"})
+	assert.Error(t, err)
+}
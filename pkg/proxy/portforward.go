@@ -0,0 +1,166 @@
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/spf13/afero"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/httpstream/spdy"
+	"k8s.io/client-go/tools/portforward"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+// capturedEndpointsDir is the bundle directory with HTTP responses captured
+// from pod ports, stored as `port-forward/<namespace>/<pod>/<port>/<path>`,
+// e.g. `port-forward/default/app-0/9090/metrics`. The response for the `/`
+// path is stored in the `index` file.
+const capturedEndpointsDir = "port-forward"
+
+// PortForwardHandler serves k8s `portforward` subresource. There is no running
+// pod behind the forwarded port, so HTTP requests are answered with responses
+// captured in the bundle and any other request fails with a message
+// explaining that the port cannot be forwarded. The responses are looked up in
+// the bundle namespace of the pod given by the namespace mapping.
+func PortForwardHandler(b bundle.Bundle, l *slog.Logger, namespaces map[string]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		if _, err := httpstream.Handshake(r, w, []string{portforward.PortForwardProtocolV1Name}); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		streamCh := make(chan httpstream.Stream)
+		conn := spdy.NewResponseUpgrader().UpgradeResponse(w, r, func(s httpstream.Stream, _ <-chan struct{}) error {
+			streamCh <- s
+			return nil
+		})
+		if conn == nil {
+			l.Error("failed to upgrade connection", "url", r.URL)
+			return
+		}
+		defer conn.Close()
+
+		endpoints := &capturedEndpoints{
+			b:         b,
+			namespace: namespaceMapping(namespaces).bundleNamespace(vars["namespace"]),
+			pod:       vars["pod"],
+		}
+		for {
+			select {
+			case stream := <-streamCh:
+				go endpoints.serveStream(stream, l.With("url", r.URL, "port", stream.Headers().Get(corev1.PortHeader)))
+			case <-conn.CloseChan():
+				return
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}
+}
+
+// capturedEndpoints serves HTTP responses captured for ports of a pod.
+type capturedEndpoints struct {
+	b         bundle.Bundle
+	namespace string
+	pod       string
+}
+
+func (e *capturedEndpoints) portDir(port string) string {
+	return filepath.Join(capturedEndpointsDir, e.namespace, e.pod, port)
+}
+
+func (e *capturedEndpoints) hasPort(port string) bool {
+	ok, _ := afero.DirExists(e.b, e.portDir(port))
+	return ok && !strings.ContainsAny(port, `/\.`)
+}
+
+func (e *capturedEndpoints) notAvailableMessage(port string) string {
+	return fmt.Sprintf(
+		"port %s of pod %s/%s cannot be forwarded: troubleshoot-live serves a snapshot of the cluster "+
+			"and the bundle doesn't contain any captured responses for the port", port, e.namespace, e.pod)
+}
+
+func (e *capturedEndpoints) serveStream(stream httpstream.Stream, l *slog.Logger) {
+	defer stream.Close()
+
+	port := stream.Headers().Get(corev1.PortHeader)
+	switch stream.Headers().Get(corev1.StreamType) {
+	case corev1.StreamTypeError:
+		if !e.hasPort(port) {
+			_, _ = stream.Write([]byte(e.notAvailableMessage(port)))
+		}
+	case corev1.StreamTypeData:
+		req, err := http.ReadRequest(bufio.NewReader(stream))
+		if err != nil {
+			l.Debug("forwarded data is not an HTTP request", "err", err)
+			return
+		}
+		if err := e.response(port, req.URL.Path).Write(stream); err != nil {
+			l.Error("failed to write forwarded response", "err", err)
+		}
+	}
+}
+
+// response returns captured response for the request path.
+func (e *capturedEndpoints) response(port, requestPath string) *http.Response {
+	if !e.hasPort(port) {
+		return textResponse(http.StatusServiceUnavailable, e.notAvailableMessage(port))
+	}
+
+	name := strings.TrimPrefix(path.Clean("/"+requestPath), "/")
+	if name == "" {
+		name = "index"
+	}
+	data, err := afero.ReadFile(e.b, filepath.Join(e.portDir(port), filepath.FromSlash(name)))
+	if err != nil {
+		return textResponse(http.StatusNotFound, fmt.Sprintf(
+			"path %q was not captured in the bundle, captured paths: %s\n",
+			requestPath, strings.Join(e.capturedPaths(port), ", ")))
+	}
+	resp := textResponse(http.StatusOK, string(data))
+	resp.Header.Set("Content-Type", http.DetectContentType(data))
+	return resp
+}
+
+func (e *capturedEndpoints) capturedPaths(port string) []string {
+	var paths []string
+	root := e.portDir(port)
+	_ = afero.Walk(e.b, root, func(p string, info fs.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, p)
+		if rel == "index" {
+			rel = ""
+		}
+		paths = append(paths, "/"+filepath.ToSlash(rel))
+		return nil
+	})
+	sort.Strings(paths)
+	return paths
+}
+
+func textResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode:    status,
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"text/plain; charset=utf-8"}},
+		Body:          io.NopCloser(bytes.NewBufferString(body)),
+		ContentLength: int64(len(body)),
+		Close:         true,
+	}
+}
@@ -0,0 +1,103 @@
+package proxy
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+
+	"github.com/mhrabovcin/troubleshoot-live/pkg/bundle"
+)
+
+// forwardPort starts port forwarding to the pod port and returns the local
+// address.
+func forwardPort(t *testing.T, b bundle.Bundle, port int, namespaces map[string]string) string {
+	t.Helper()
+
+	r := mux.NewRouter()
+	r.Handle("/api/v1/namespaces/{namespace}/pods/{pod}/portforward", PortForwardHandler(b, slog.Default(), namespaces))
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	pfURL, err := url.Parse(server.URL + "/api/v1/namespaces/default/pods/app-0/portforward")
+	require.NoError(t, err)
+
+	transport, upgrader, err := spdy.RoundTripperFor(&rest.Config{Host: server.URL})
+	require.NoError(t, err)
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, pfURL)
+
+	stopCh, readyCh := make(chan struct{}), make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	fw, err := portforward.NewOnAddresses(
+		dialer, []string{"127.0.0.1"}, []string{fmt.Sprintf("0:%d", port)}, stopCh, readyCh, io.Discard, io.Discard)
+	require.NoError(t, err)
+	go func() { _ = fw.ForwardPorts() }()
+	<-readyCh
+
+	ports, err := fw.GetPorts()
+	require.NoError(t, err)
+	return fmt.Sprintf("http://127.0.0.1:%d", ports[0].Local)
+}
+
+func get(t *testing.T, u string) (int, string) {
+	t.Helper()
+
+	resp, err := http.Get(u) //nolint:noctx // test request
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestPortForwardHandler_CapturedEndpoint(t *testing.T) {
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		"port-forward/default/app-0/9090/metrics": []byte("http_requests_total 42\n"),
+		"port-forward/default/app-0/9090/index":   []byte("ok"),
+	}))
+	addr := forwardPort(t, b, 9090, nil)
+
+	status, body := get(t, addr+"/metrics")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "http_requests_total 42\n", body)
+
+	status, body = get(t, addr+"/")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "ok", body)
+
+	status, body = get(t, addr+"/healthz")
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Contains(t, body, "captured paths: /, /metrics")
+}
+
+func TestPortForwardHandler_NotCaptured(t *testing.T) {
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		"port-forward/default/app-0/9090/metrics": []byte("http_requests_total 42\n"),
+	}))
+	addr := forwardPort(t, b, 8080, nil)
+
+	status, body := get(t, addr+"/")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Contains(t, body, "port 8080 of pod default/app-0 cannot be forwarded")
+}
+
+func TestPortForwardHandler_NamespaceMapping(t *testing.T) {
+	b := bundle.FromFs(newMemFs(t, map[string][]byte{
+		"port-forward/prod/app-0/9090/metrics": []byte("http_requests_total 42\n"),
+	}))
+	addr := forwardPort(t, b, 9090, map[string]string{"prod": "default"})
+
+	status, body := get(t, addr+"/metrics")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "http_requests_total 42\n", body)
+}
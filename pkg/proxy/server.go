@@ -13,17 +13,35 @@ import (
 )
 
 // New create new proxy handler that can be used by HTTP library.
-func New(cfg *rest.Config, b bundle.Bundle, rr rewriter.ResourceRewriter) http.Handler {
+func New(cfg *rest.Config, b bundle.Bundle, rr rewriter.ResourceRewriter, logsOpts ...LogsOption) http.Handler {
+	namespaces := newLogsOptions(logsOpts...).namespaceMapping
 	proxyHandler, err := ReverseProxyForAPIServerHandler(cfg)
 	if err != nil {
 		log.Fatalln(err)
 	}
 	// disable bodyclose linting as it seems like false positive
 	// https://github.com/timakin/bodyclose/issues/42
-	proxyHandler.ModifyResponse = proxyModifyResponse(rr) //nolint:bodyclose // false positive
+	rewrite := proxyModifyResponse(rr) //nolint:bodyclose // false positive
+	// Bundles are immutable, captured metrics are checked once instead of on
+	// each discovery request.
+	capturedMetrics := hasCapturedMetrics(b)
+	proxyHandler.ModifyResponse = func(r *http.Response) error {
+		if err := rewrite(r); err != nil {
+			return err
+		}
+		if !capturedMetrics {
+			return nil
+		}
+		return addMetricsDiscovery(r)
+	}
 
 	r := mux.NewRouter()
-	r.Handle("/api/v1/namespaces/{namespace}/pods/{pod}/log", LogsHandler(b, slog.With("handler", "LogsHandler")))
+	r.Handle("/api/v1/namespaces/{namespace}/pods/{pod}/log", LogsHandler(b, slog.With("handler", "LogsHandler"), logsOpts...))
+	r.Handle("/api/v1/namespaces/{namespace}/pods/{pod}/exec", ExecHandler(b, slog.With("handler", "ExecHandler"), namespaces))
+	r.Handle("/api/v1/namespaces/{namespace}/pods/{pod}/portforward", PortForwardHandler(b, slog.With("handler", "PortForwardHandler"), namespaces))
+	r.Handle(EventsPath, EventsHandler(b, slog.With("handler", "EventsHandler")))
+	r.PathPrefix(MetricsPath).Handler(MetricsHandler(b, slog.With("handler", "MetricsHandler"), namespaces))
+	r.PathPrefix(OpenAPIV3Path).Handler(OpenAPIV3Handler(b, proxyHandler, slog.With("handler", "OpenAPIV3Handler")))
 	r.PathPrefix("/").Handler(proxyHandler)
 	return r
 }
@@ -0,0 +1,81 @@
+package rewriter
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var _ ResourceRewriter = (*remapNamespace)(nil)
+
+// RemapNamespace moves resources to a different namespace on import based on
+// provided mapping from the original namespace to a new one. This allows to
+// import multiple bundles to single API server without collisions. It rewrites:
+//   - `metadata.namespace` of namespaced resources,
+//   - `metadata.name` of Namespace resources,
+//   - `subjects[].namespace` of RoleBinding and ClusterRoleBinding resources.
+//
+// Owner references are always namespace local and do not need rewriting. Other
+// cross-namespace references, e.g. a namespace in a service DNS name stored in
+// a ConfigMap, are not rewritten. The resources are served with the new
+// namespace and the original value is kept in an annotation.
+func RemapNamespace(mapping map[string]string) ResourceRewriter {
+	return &remapNamespace{
+		mapping: mapping,
+	}
+}
+
+type remapNamespace struct {
+	mapping map[string]string
+}
+
+func (r *remapNamespace) BeforeImport(u *unstructured.Unstructured) error {
+	isNamespaceKind := MatchGVK(schema.FromAPIVersionAndKind("v1", "Namespace"))
+	if isNamespaceKind(u) {
+		if remapped, ok := r.mapping[u.GetName()]; ok {
+			if err := addAnnotation(u, annotationForField("metadata", "name"), u.GetName()); err != nil {
+				return err
+			}
+			u.SetName(remapped)
+		}
+		return nil
+	}
+
+	if remapped, ok := r.mapping[u.GetNamespace()]; ok {
+		if err := addAnnotation(u, annotationForField("metadata", "namespace"), u.GetNamespace()); err != nil {
+			return err
+		}
+		u.SetNamespace(remapped)
+	}
+
+	return r.remapSubjects(u)
+}
+
+func (r *remapNamespace) remapSubjects(u *unstructured.Unstructured) error {
+	if u.GetKind() != "RoleBinding" && u.GetKind() != "ClusterRoleBinding" {
+		return nil
+	}
+
+	subjects, ok, err := unstructured.NestedSlice(u.Object, "subjects")
+	if err != nil || !ok {
+		return err
+	}
+
+	for i := range subjects {
+		subject, ok := subjects[i].(map[string]any)
+		if !ok {
+			continue
+		}
+		namespace, _ := subject["namespace"].(string)
+		if remapped, ok := r.mapping[namespace]; ok {
+			subject["namespace"] = remapped
+		}
+	}
+
+	return unstructured.SetNestedSlice(u.Object, subjects, "subjects")
+}
+
+// BeforeServing keeps the remapped namespace, so that resources from multiple
+// bundles can be distinguished.
+func (r *remapNamespace) BeforeServing(_ *unstructured.Unstructured) error {
+	return nil
+}
@@ -0,0 +1,58 @@
+package rewriter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRemapNamespace(t *testing.T) {
+	r := RemapNamespace(map[string]string{"default": "b-default"})
+
+	pod := &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+	}
+	pod = testRewriterBeforeImport(t, r, pod)
+	assert.Equal(t, "b-default", pod.GetNamespace())
+	assert.Equal(t, "default", pod.GetAnnotations()[annotationForField("metadata", "namespace")])
+
+	pod = testRewriterBeforeServing(t, r, pod)
+	assert.Equal(t, "b-default", pod.GetNamespace())
+
+	other := &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "kube-system"},
+	}
+	other = testRewriterBeforeImport(t, r, other)
+	assert.Equal(t, "kube-system", other.GetNamespace())
+	assert.Empty(t, other.GetAnnotations())
+}
+
+func TestRemapNamespace_Namespace(t *testing.T) {
+	ns := &corev1.Namespace{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+	}
+	ns = testRewriterBeforeImport(t, RemapNamespace(map[string]string{"default": "b-default"}), ns)
+	assert.Equal(t, "b-default", ns.GetName())
+}
+
+func TestRemapNamespace_Subjects(t *testing.T) {
+	binding := &rbacv1.ClusterRoleBinding{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRoleBinding"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Subjects: []rbacv1.Subject{
+			{Kind: "ServiceAccount", Name: "sa", Namespace: "default"},
+			{Kind: "ServiceAccount", Name: "sa", Namespace: "other"},
+			{Kind: "User", Name: "admin"},
+		},
+	}
+	binding = testRewriterBeforeImport(t, RemapNamespace(map[string]string{"default": "b-default"}), binding)
+	assert.Equal(t, "b-default", binding.Subjects[0].Namespace)
+	assert.Equal(t, "other", binding.Subjects[1].Namespace)
+	assert.Empty(t, binding.Subjects[2].Namespace)
+}